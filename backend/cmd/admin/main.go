@@ -0,0 +1,147 @@
+// Command admin runs operational tasks against the backend database:
+// migrations, seeding demo data, and user and session management.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/infrastructure"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
+)
+
+const usage = `usage: admin <command> [arguments]
+
+commands:
+  migrate up                     apply pending migrations
+  migrate down [-steps N]        roll back the last N migrations (default 1)
+  migrate status                 list migrations and whether they are applied
+  seed                           insert demo data into an empty database
+  user create -email E -name N [-plan P]
+  user reset-password -email E
+  user disable -email E
+  user unlock -email E           clear failed login lockout
+  sessions revoke-all [-email E] revoke sessions of one user, or of everyone
+  config print [-redacted]       show the effective configuration
+
+Passwords are read from stdin so they do not end up in shell history.
+`
+
+// env holds the connections shared by all commands
+type env struct {
+	cfg     *config.Config
+	log     logger.Logger
+	pool    *pgxpool.Pool
+	redis   *redis.Client
+	service *services.AppService
+}
+
+func main() {
+	_ = godotenv.Load(".env")
+	_ = godotenv.Load("../.env")
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "migrate":
+		err = runMigrate(ctx, os.Args[2:])
+	case "seed":
+		err = runSeed(ctx, os.Args[2:])
+	case "user":
+		err = runUser(ctx, os.Args[2:])
+	case "sessions":
+		err = runSessions(ctx, os.Args[2:])
+	case "config":
+		err = runConfig(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", os.Args[1], usage)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "admin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// connect loads configuration and opens Postgres, plus Redis when configured
+// so that login throttling state is shared with the running servers
+func connect(ctx context.Context) (*env, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if cfg.PostgresDSN == "" {
+		return nil, fmt.Errorf("POSTGRES_DSN must be set")
+	}
+
+	log, err := logger.New(cfg.Env, cfg.LogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("initialize logger: %w", err)
+	}
+
+	pool, err := infrastructure.NewPostgresPool(ctx, cfg.PostgresDSN, 2, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var redisClient *redis.Client
+	if cfg.RedisURL != "" {
+		redisClient, err = infrastructure.NewRedisClient(ctx, cfg.RedisURL, 2)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+	}
+
+	var mailer services.Mailer
+	if cfg.SMTPHost != "" {
+		mailer = &services.SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			TLS:      cfg.SMTPTLS,
+		}
+	}
+
+	return &env{
+		cfg:   cfg,
+		log:   log,
+		pool:  pool,
+		redis: redisClient,
+		service: services.NewAppService(pool, services.Options{
+			Redis:                    redisClient,
+			AppURL:                   cfg.AppBaseURL,
+			APIURL:                   cfg.APIBaseURL,
+			AllowPrivateCalendarURLs: cfg.CalendarImportAllowPrivate,
+			Mailer:                   mailer,
+			Logger:                   log,
+		}),
+	}, nil
+}
+
+func (e *env) Close() {
+	if e.redis != nil {
+		_ = e.redis.Close()
+	}
+	e.pool.Close()
+}
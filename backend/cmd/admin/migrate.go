@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/migrate"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+)
+
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate requires a subcommand: up, down or status")
+	}
+
+	switch args[0] {
+	case "up":
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		if err := migrate.Run(ctx, e.pool); err != nil {
+			return err
+		}
+		version, err := migrate.CurrentVersion(ctx, e.pool)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("schema is at version %04d\n", version)
+		return nil
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		if err := migrate.Down(ctx, e.pool, *steps); err != nil {
+			return err
+		}
+		version, err := migrate.CurrentVersion(ctx, e.pool)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("schema is at version %04d\n", version)
+		return nil
+
+	case "status":
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		statuses, err := migrate.List(ctx, e.pool)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED\tDOWN")
+		for _, s := range statuses {
+			fmt.Fprintf(w, "%04d\t%s\t%t\t%t\n", s.Version, s.Name, s.Applied, s.HasDown)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+}
+
+func runSeed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	e, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	if e.cfg.Env == "production" {
+		return fmt.Errorf("refusing to seed demo data with APP_ENV=production")
+	}
+	return services.Seed(ctx, e.pool, e.log)
+}
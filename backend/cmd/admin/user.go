@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runUser(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("user requires a subcommand: create, reset-password, disable or unlock")
+	}
+
+	fs := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	email := fs.String("email", "", "account email")
+	var name, plan *string
+	if args[0] == "create" {
+		name = fs.String("name", "", "display name")
+		plan = fs.String("plan", "free", "plan tier")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*email) == "" {
+		return fmt.Errorf("-email is required")
+	}
+
+	switch args[0] {
+	case "create":
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		userID, err := e.service.CreateUser(ctx, *name, *email, password, *plan)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created user %s\n", userID)
+		return nil
+
+	case "reset-password":
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		if err := e.service.ResetPassword(ctx, *email, password); err != nil {
+			return err
+		}
+		fmt.Println("password updated and sessions revoked")
+		return nil
+
+	case "disable":
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		if err := e.service.DisableUser(ctx, *email); err != nil {
+			return err
+		}
+		fmt.Println("user disabled and sessions revoked")
+		return nil
+
+	case "unlock":
+		e, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		defer e.Close()
+
+		if err := e.service.UnlockAccount(ctx, *email); err != nil {
+			return err
+		}
+		fmt.Println("login lockout cleared")
+		return nil
+
+	default:
+		return fmt.Errorf("unknown user subcommand %q", args[0])
+	}
+}
+
+func runSessions(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "revoke-all" {
+		return fmt.Errorf("sessions requires a subcommand: revoke-all")
+	}
+
+	fs := flag.NewFlagSet("sessions revoke-all", flag.ContinueOnError)
+	email := fs.String("email", "", "only revoke sessions of this account")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	e, err := connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	revoked, err := e.service.RevokeAllSessions(ctx, *email)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("revoked %d sessions\n", revoked)
+	return nil
+}
+
+// readPassword reads a single line from stdin, prompting when it is a terminal
+func readPassword() (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, "password: ")
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password from stdin: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
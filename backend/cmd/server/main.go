@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -10,6 +13,7 @@ import (
 	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/server"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/tracing"
 	"github.com/joho/godotenv"
 )
 
@@ -22,33 +26,51 @@ func main() {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
-	logr := logger.New(cfg.Env)
+	logr, err := logger.New(cfg.Env, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("failed to initialize logger: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingExporter, cfg.Env, cfg.TracingSampleRatio)
+	if err != nil {
+		logr.Error("failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
 
 	httpServer, err := server.New(cfg, logr)
 	if err != nil {
-		logr.Fatalf("failed to initialize server: %v", err)
+		logr.Error("failed to initialize server", "error", err)
+		os.Exit(1)
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
 	go func() {
-		if err := httpServer.Start(); err != nil {
-			logr.Fatalf("server error: %v", err)
+		if err := httpServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logr.Error("server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
 	<-ctx.Done()
 	stop()
 
-	logr.Println("shutting down server")
+	logr.Info("shutting down server")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// The drain delay runs inside Stop, so it extends the shutdown budget
+	shutdownTimeout := 10*time.Second + time.Duration(cfg.ShutdownDrainSec)*time.Second
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := httpServer.Stop(shutdownCtx); err != nil {
-		logr.Fatalf("failed to shutdown gracefully: %v", err)
+		logr.Error("failed to shutdown gracefully", "error", err)
+		os.Exit(1)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logr.Error("failed to flush traces", "error", err)
 	}
 
-	logr.Println("server exited")
+	logr.Info("server exited")
 }
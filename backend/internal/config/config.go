@@ -1,17 +1,27 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
 )
 
 type Config struct {
 	Env                  string
 	LogLevel             string
 	HTTPPort             int
+	MetricsPort          int
 	ReadTimeoutSec       int
 	WriteTimeoutSec      int
+	ShutdownDrainSec     int
 	PostgresDSN          string
 	PostgresMaxOpenConns int
 	PostgresMaxIdleConns int
@@ -29,59 +39,454 @@ type Config struct {
 	LiveKitURL           string
 	LiveKitAPIKey        string
 	LiveKitAPISecret     string
+	TrustedProxies       []netip.Prefix
+	ClientIPHeader       string
+	CORSAllowedOrigins   []string
+	CookieDomain         string
+	CookieSecure         bool
+	CookieSameSite       http.SameSite
+	AccessCookieName     string
+	RefreshCookieName    string
+	TracingExporter      string
+	TracingSampleRatio   float64
+	AppBaseURL           string
+	APIBaseURL           string
+	// CalendarImportAllowPrivate lets calendar imports fetch URLs on private
+	// and loopback addresses, for local development only
+	CalendarImportAllowPrivate bool
+	// SchedulerEnabled runs the meeting lifecycle worker on this replica
+	SchedulerEnabled     bool
+	SchedulerIntervalSec int
+	// MeetingMissedAfterMin is the grace after a meeting's scheduled end
+	// before one that never started is closed
+	MeetingMissedAfterMin int
+	// MeetingAutoEndAfterMin is how far past its duration a running meeting
+	// is ended; 0 disables auto-ending
+	MeetingAutoEndAfterMin int
+	// MeetingReminderMin is how long before the start reminders are sent;
+	// 0 disables reminders
+	MeetingReminderMin int
+	// MeetingEventRetentionHours is how long meeting events are kept for
+	// streams to resume from; 0 keeps them forever
+	MeetingEventRetentionHours int
+	// SMTPHost enables email delivery; without it email is only logged,
+	// which production does not allow
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// SMTPTLS is starttls, tls (implicit, usually port 465) or none
+	SMTPTLS string
 }
 
+// Load builds the configuration from the optional file named by CONFIG_FILE,
+// overridden by environment variables. Malformed values are errors rather
+// than silently falling back to defaults, and every problem is reported at once.
 func Load() (*Config, error) {
+	src := &source{}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		src.file = values
+	}
+
+	env := src.getString("APP_ENV", "development")
+	// Production defaults to a cross-site deployment (frontend and API on
+	// different sites), which needs SameSite=None and therefore Secure.
+	defaultSameSite := "lax"
+	if env == "production" {
+		defaultSameSite = "none"
+	}
+
 	cfg := &Config{
-		Env:                  getString("APP_ENV", "development"),
-		LogLevel:             getString("LOG_LEVEL", "info"),
-		HTTPPort:             getInt("HTTP_PORT", 8080),
-		ReadTimeoutSec:       getInt("HTTP_READ_TIMEOUT_SEC", 15),
-		WriteTimeoutSec:      getInt("HTTP_WRITE_TIMEOUT_SEC", 15),
-		PostgresDSN:          getString("POSTGRES_DSN", ""),
-		PostgresMaxOpenConns: getInt("POSTGRES_MAX_OPEN_CONNS", 20),
-		PostgresMaxIdleConns: getInt("POSTGRES_MAX_IDLE_CONNS", 10),
-		RedisURL:             getString("REDIS_URL", ""),
-		RedisPoolSize:        getInt("REDIS_POOL_SIZE", 20),
-		JWTSecret:            getString("JWT_SECRET", ""),
-		OpenAIAPIKey:         getString("OPENAI_API_KEY", ""),
-		OpenAIRealtimeModel:  getString("OPENAI_REALTIME_MODEL", "gpt-4o-realtime-preview"),
-		ElevenLabsAPIKey:     getString("ELEVENLABS_API_KEY", ""),
-		ElevenLabsVoiceID:    getString("ELEVENLABS_VOICE_ID", ""),
-		WebrtcSFUURL:         getString("WEBRTC_SFU_URL", ""),
-		WebrtcTURNURL:        getString("WEBRTC_TURN_URL", ""),
-		WebrtcTURNUsername:   getString("WEBRTC_TURN_USERNAME", ""),
-		WebrtcTURNPassword:   getString("WEBRTC_TURN_PASSWORD", ""),
-		LiveKitURL:           getString("LIVEKIT_URL", ""),
-		LiveKitAPIKey:        getString("LIVEKIT_API_KEY", ""),
-		LiveKitAPISecret:     getString("LIVEKIT_API_SECRET", ""),
-	}
-
-	if cfg.HTTPPort <= 0 {
-		return nil, fmt.Errorf("invalid HTTP_PORT: %d", cfg.HTTPPort)
-	}
-
-	if err := validateSecret(cfg.JWTSecret); err != nil {
-		return nil, err
+		Env:                  env,
+		LogLevel:             src.getString("LOG_LEVEL", "info"),
+		HTTPPort:             src.getInt("HTTP_PORT", 8080),
+		MetricsPort:          src.getInt("METRICS_PORT", 0),
+		ReadTimeoutSec:       src.getInt("HTTP_READ_TIMEOUT_SEC", 15),
+		WriteTimeoutSec:      src.getInt("HTTP_WRITE_TIMEOUT_SEC", 15),
+		ShutdownDrainSec:     src.getInt("SHUTDOWN_DRAIN_SEC", 0),
+		PostgresDSN:          src.getString("POSTGRES_DSN", ""),
+		PostgresMaxOpenConns: src.getInt("POSTGRES_MAX_OPEN_CONNS", 20),
+		PostgresMaxIdleConns: src.getInt("POSTGRES_MAX_IDLE_CONNS", 10),
+		RedisURL:             src.getString("REDIS_URL", ""),
+		RedisPoolSize:        src.getInt("REDIS_POOL_SIZE", 20),
+		JWTSecret:            src.getString("JWT_SECRET", ""),
+		OpenAIAPIKey:         src.getString("OPENAI_API_KEY", ""),
+		OpenAIRealtimeModel:  src.getString("OPENAI_REALTIME_MODEL", "gpt-4o-realtime-preview"),
+		ElevenLabsAPIKey:     src.getString("ELEVENLABS_API_KEY", ""),
+		ElevenLabsVoiceID:    src.getString("ELEVENLABS_VOICE_ID", ""),
+		WebrtcSFUURL:         src.getString("WEBRTC_SFU_URL", ""),
+		WebrtcTURNURL:        src.getString("WEBRTC_TURN_URL", ""),
+		WebrtcTURNUsername:   src.getString("WEBRTC_TURN_USERNAME", ""),
+		WebrtcTURNPassword:   src.getString("WEBRTC_TURN_PASSWORD", ""),
+		LiveKitURL:           src.getString("LIVEKIT_URL", ""),
+		LiveKitAPIKey:        src.getString("LIVEKIT_API_KEY", ""),
+		LiveKitAPISecret:     src.getString("LIVEKIT_API_SECRET", ""),
+		TrustedProxies:       src.getPrefixes("TRUSTED_PROXIES"),
+		ClientIPHeader:       http.CanonicalHeaderKey(strings.TrimSpace(src.getString("CLIENT_IP_HEADER", "X-Forwarded-For"))),
+		CORSAllowedOrigins:   src.getList("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000", "http://localhost:5173"}),
+		CookieDomain:         src.getString("COOKIE_DOMAIN", ""),
+		CookieSecure:         src.getBool("COOKIE_SECURE", env == "production"),
+		CookieSameSite:       src.getSameSite("COOKIE_SAME_SITE", defaultSameSite),
+		AccessCookieName:     src.getString("ACCESS_COOKIE_NAME", "nl_access"),
+		RefreshCookieName:    src.getString("REFRESH_COOKIE_NAME", "nl_refresh"),
+		AppBaseURL:           src.getString("APP_BASE_URL", "http://localhost:3000"),
+		APIBaseURL:           src.getString("API_BASE_URL", "http://localhost:8080"),
+		TracingExporter:      src.getString("TRACING_EXPORTER", "none"),
+		TracingSampleRatio:   src.getFloat("TRACING_SAMPLE_RATIO", 1.0),
+
+		CalendarImportAllowPrivate: src.getBool("CALENDAR_IMPORT_ALLOW_PRIVATE", false),
+		SchedulerEnabled:           src.getBool("SCHEDULER_ENABLED", true),
+		SchedulerIntervalSec:       src.getInt("SCHEDULER_INTERVAL_SEC", 60),
+		MeetingMissedAfterMin:      src.getInt("MEETING_MISSED_AFTER_MIN", 30),
+		MeetingAutoEndAfterMin:     src.getInt("MEETING_AUTO_END_AFTER_MIN", 120),
+		MeetingReminderMin:         src.getInt("MEETING_REMINDER_MIN", 15),
+		MeetingEventRetentionHours: src.getInt("MEETING_EVENT_RETENTION_HOURS", 168),
+
+		SMTPHost:     src.getString("SMTP_HOST", ""),
+		SMTPPort:     src.getInt("SMTP_PORT", 587),
+		SMTPUsername: src.getString("SMTP_USERNAME", ""),
+		SMTPPassword: src.getString("SMTP_PASSWORD", ""),
+		SMTPFrom:     src.getString("SMTP_FROM", ""),
+		SMTPTLS:      strings.ToLower(src.getString("SMTP_TLS", "starttls")),
+	}
+
+	errs := src.errs
+	errs = append(errs, src.unknownFileKeys()...)
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
 	return cfg, nil
 }
 
-func getString(key, fallback string) string {
+// validate checks individual values and the rules that span several fields
+func (c *Config) validate() []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.Env {
+	case "development", "test", "staging", "production":
+	default:
+		fail("invalid APP_ENV: %q (expected development, test, staging or production)", c.Env)
+	}
+	if _, err := logger.ParseLevel(c.LogLevel); err != nil {
+		fail("invalid LOG_LEVEL: %q", c.LogLevel)
+	}
+
+	if c.HTTPPort <= 0 || c.HTTPPort > 65535 {
+		fail("invalid HTTP_PORT: %d", c.HTTPPort)
+	}
+	if c.MetricsPort < 0 || c.MetricsPort > 65535 || (c.MetricsPort != 0 && c.MetricsPort == c.HTTPPort) {
+		fail("invalid METRICS_PORT: %d", c.MetricsPort)
+	}
+	if c.ReadTimeoutSec <= 0 {
+		fail("invalid HTTP_READ_TIMEOUT_SEC: %d", c.ReadTimeoutSec)
+	}
+	if c.WriteTimeoutSec <= 0 {
+		fail("invalid HTTP_WRITE_TIMEOUT_SEC: %d", c.WriteTimeoutSec)
+	}
+	if c.ShutdownDrainSec < 0 {
+		fail("invalid SHUTDOWN_DRAIN_SEC: %d", c.ShutdownDrainSec)
+	}
+	if c.PostgresMaxOpenConns <= 0 {
+		fail("invalid POSTGRES_MAX_OPEN_CONNS: %d", c.PostgresMaxOpenConns)
+	}
+	if c.PostgresMaxIdleConns < 0 || c.PostgresMaxIdleConns > c.PostgresMaxOpenConns {
+		fail("invalid POSTGRES_MAX_IDLE_CONNS: %d (must be between 0 and POSTGRES_MAX_OPEN_CONNS)", c.PostgresMaxIdleConns)
+	}
+	if c.RedisPoolSize <= 0 {
+		fail("invalid REDIS_POOL_SIZE: %d", c.RedisPoolSize)
+	}
+	if c.SchedulerIntervalSec <= 0 {
+		fail("invalid SCHEDULER_INTERVAL_SEC: %d", c.SchedulerIntervalSec)
+	}
+	if c.MeetingMissedAfterMin < 0 {
+		fail("invalid MEETING_MISSED_AFTER_MIN: %d", c.MeetingMissedAfterMin)
+	}
+	if c.MeetingAutoEndAfterMin < 0 {
+		fail("invalid MEETING_AUTO_END_AFTER_MIN: %d", c.MeetingAutoEndAfterMin)
+	}
+	if c.MeetingReminderMin < 0 {
+		fail("invalid MEETING_REMINDER_MIN: %d", c.MeetingReminderMin)
+	}
+	if c.MeetingEventRetentionHours < 0 {
+		fail("invalid MEETING_EVENT_RETENTION_HOURS: %d", c.MeetingEventRetentionHours)
+	}
+
+	switch c.TracingExporter {
+	case "none", "stdout", "otlp":
+	default:
+		fail("invalid TRACING_EXPORTER: %q (expected none, stdout or otlp)", c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		fail("invalid TRACING_SAMPLE_RATIO: %v", c.TracingSampleRatio)
+	}
+
+	if err := validateSecret(c.JWTSecret); err != nil {
+		errs = append(errs, err)
+	}
+
+	if (c.LiveKitAPIKey == "") != (c.LiveKitAPISecret == "") {
+		fail("LIVEKIT_API_KEY and LIVEKIT_API_SECRET must be set together")
+	}
+	if c.LiveKitAPIKey != "" && c.LiveKitURL == "" {
+		fail("LIVEKIT_URL is required when LiveKit credentials are set")
+	}
+
+	turnSet := 0
+	for _, v := range []string{c.WebrtcTURNURL, c.WebrtcTURNUsername, c.WebrtcTURNPassword} {
+		if v != "" {
+			turnSet++
+		}
+	}
+	if turnSet != 0 && turnSet != 3 {
+		fail("WEBRTC_TURN_URL, WEBRTC_TURN_USERNAME and WEBRTC_TURN_PASSWORD must be set together")
+	}
+
+	if _, err := parseHTTPURL(c.AppBaseURL); err != nil {
+		fail("invalid APP_BASE_URL: %v", err)
+	}
+	if _, err := parseHTTPURL(c.APIBaseURL); err != nil {
+		fail("invalid API_BASE_URL: %v", err)
+	}
+	for _, origin := range c.CORSAllowedOrigins {
+		u, err := parseHTTPURL(origin)
+		if err != nil {
+			fail("invalid CORS_ALLOWED_ORIGINS entry %q: %v", origin, err)
+			continue
+		}
+		if u.Path != "" && u.Path != "/" {
+			fail("invalid CORS_ALLOWED_ORIGINS entry %q: origins cannot have a path", origin)
+		}
+		if c.Env == "production" && isLoopbackHost(u.Hostname()) {
+			fail("CORS_ALLOWED_ORIGINS must not contain a localhost origin in production: %q", origin)
+		}
+	}
+
+	switch c.ClientIPHeader {
+	case "X-Forwarded-For", "Forwarded", "X-Real-Ip":
+	default:
+		fail("invalid CLIENT_IP_HEADER: %q (expected X-Forwarded-For, Forwarded or X-Real-IP)", c.ClientIPHeader)
+	}
+
+	if c.SMTPHost == "" {
+		if c.Env == "production" {
+			fail("SMTP_HOST is required in production")
+		}
+	} else {
+		if c.SMTPPort <= 0 || c.SMTPPort > 65535 {
+			fail("invalid SMTP_PORT: %d", c.SMTPPort)
+		}
+		if _, err := mail.ParseAddress(c.SMTPFrom); err != nil {
+			fail("invalid SMTP_FROM: %q", c.SMTPFrom)
+		}
+		if (c.SMTPUsername == "") != (c.SMTPPassword == "") {
+			fail("SMTP_USERNAME and SMTP_PASSWORD must be set together")
+		}
+	}
+	switch c.SMTPTLS {
+	case "starttls", "tls", "none":
+	default:
+		fail("invalid SMTP_TLS: %q (expected starttls, tls or none)", c.SMTPTLS)
+	}
+
+	if c.Env == "production" && c.CalendarImportAllowPrivate {
+		fail("CALENDAR_IMPORT_ALLOW_PRIVATE must not be enabled in production")
+	}
+
+	if c.CookieSameSite == http.SameSiteNoneMode && !c.CookieSecure {
+		fail("COOKIE_SAME_SITE=none requires COOKIE_SECURE=true")
+	}
+	for key, name := range map[string]string{"ACCESS_COOKIE_NAME": c.AccessCookieName, "REFRESH_COOKIE_NAME": c.RefreshCookieName} {
+		if !isCookieName(name) {
+			fail("invalid %s: %q", key, name)
+		}
+	}
+	if c.AccessCookieName == c.RefreshCookieName {
+		fail("ACCESS_COOKIE_NAME and REFRESH_COOKIE_NAME must differ")
+	}
+	if strings.ContainsAny(c.CookieDomain, " ;,/:") {
+		fail("invalid COOKIE_DOMAIN: %q", c.CookieDomain)
+	}
+
+	return errs
+}
+
+// isCookieName reports whether name is a valid RFC 6265 cookie name token
+func isCookieName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`()<>@,;:\"/[]?={}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseHTTPURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("host is required")
+	}
+	return u, nil
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && addr.IsLoopback()
+}
+
+// source resolves settings from the environment first, then the config file,
+// and collects parse errors instead of falling back to defaults
+type source struct {
+	file map[string]string
+	used map[string]bool
+	errs []error
+}
+
+func (s *source) lookup(key string) (string, bool) {
+	if s.used == nil {
+		s.used = map[string]bool{}
+	}
+	s.used[key] = true
+
 	if val := os.Getenv(key); val != "" {
+		return val, true
+	}
+	val, ok := s.file[key]
+	return val, ok && val != ""
+}
+
+func (s *source) getString(key, fallback string) string {
+	if val, ok := s.lookup(key); ok {
 		return val
 	}
 	return fallback
 }
 
-func getInt(key string, fallback int) int {
-	if val := os.Getenv(key); val != "" {
-		if parsed, err := strconv.Atoi(val); err == nil {
-			return parsed
+func (s *source) getInt(key string, fallback int) int {
+	val, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("invalid %s: %q is not an integer", key, val))
+		return fallback
+	}
+	return parsed
+}
+
+func (s *source) getBool(key string, fallback bool) bool {
+	val, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(val))
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("invalid %s: %q is not a boolean", key, val))
+		return fallback
+	}
+	return parsed
+}
+
+func (s *source) getSameSite(key, fallback string) http.SameSite {
+	val := s.getString(key, fallback)
+	switch strings.ToLower(strings.TrimSpace(val)) {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		s.errs = append(s.errs, fmt.Errorf("invalid %s: %q (expected lax, strict or none)", key, val))
+		return http.SameSiteLaxMode
+	}
+}
+
+func (s *source) getFloat(key string, fallback float64) float64 {
+	val, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+	if err != nil {
+		s.errs = append(s.errs, fmt.Errorf("invalid %s: %q is not a number", key, val))
+		return fallback
+	}
+	return parsed
+}
+
+// getList parses a comma-separated list, ignoring empty entries
+func (s *source) getList(key string, fallback []string) []string {
+	val, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	var items []string
+	for _, part := range strings.Split(val, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
 		}
 	}
-	return fallback
+	return items
+}
+
+// getPrefixes parses a comma-separated list of CIDRs or bare IPs.
+// Bare IPs are treated as single-host prefixes (/32 or /128).
+func (s *source) getPrefixes(key string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, part := range s.getList(key, nil) {
+		if strings.Contains(part, "/") {
+			prefix, err := netip.ParsePrefix(part)
+			if err != nil {
+				s.errs = append(s.errs, fmt.Errorf("invalid %s entry %q: %w", key, part, err))
+				continue
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(part)
+		if err != nil {
+			s.errs = append(s.errs, fmt.Errorf("invalid %s entry %q: %w", key, part, err))
+			continue
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes
+}
+
+// unknownFileKeys reports config file keys that no setting reads, which are
+// almost always typos
+func (s *source) unknownFileKeys() []error {
+	var errs []error
+	for key := range s.file {
+		if !s.used[key] {
+			errs = append(errs, fmt.Errorf("unknown config file key %q", key))
+		}
+	}
+	return errs
 }
 
 func validateSecret(secret string) error {
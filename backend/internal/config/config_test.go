@@ -0,0 +1,150 @@
+package config
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// setEnv gives Load a known environment: a valid development setup with the
+// cookie settings unset, overridden by env
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	base := map[string]string{
+		"CONFIG_FILE":          "",
+		"APP_ENV":              "development",
+		"JWT_SECRET":           "test-secret-0123456789",
+		"COOKIE_DOMAIN":        "",
+		"COOKIE_SECURE":        "",
+		"COOKIE_SAME_SITE":     "",
+		"CORS_ALLOWED_ORIGINS": "",
+		"APP_BASE_URL":         "",
+		"API_BASE_URL":         "",
+		"SMTP_HOST":            "",
+		"SMTP_FROM":            "",
+	}
+	if env["APP_ENV"] == "production" {
+		base["CORS_ALLOWED_ORIGINS"] = "https://app.example.com"
+		base["APP_BASE_URL"] = "https://app.example.com"
+		base["API_BASE_URL"] = "https://api.example.com"
+		base["SMTP_HOST"] = "smtp.example.com"
+		base["SMTP_FROM"] = "no-reply@example.com"
+	}
+	for key, value := range env {
+		base[key] = value
+	}
+	for key, value := range base {
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoadCookieSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantSameSite http.SameSite
+		wantSecure   bool
+		wantDomain   string
+	}{
+		{
+			name:         "development defaults to Lax",
+			env:          map[string]string{},
+			wantSameSite: http.SameSiteLaxMode,
+		},
+		{
+			name:         "production defaults to None and Secure",
+			env:          map[string]string{"APP_ENV": "production"},
+			wantSameSite: http.SameSiteNoneMode,
+			wantSecure:   true,
+		},
+		{
+			name:         "strict",
+			env:          map[string]string{"COOKIE_SAME_SITE": "Strict"},
+			wantSameSite: http.SameSiteStrictMode,
+		},
+		{
+			name:         "production same-site deployment",
+			env:          map[string]string{"APP_ENV": "production", "COOKIE_SAME_SITE": "lax"},
+			wantSameSite: http.SameSiteLaxMode,
+			wantSecure:   true,
+		},
+		{
+			name:         "None with Secure",
+			env:          map[string]string{"COOKIE_SAME_SITE": "none", "COOKIE_SECURE": "true"},
+			wantSameSite: http.SameSiteNoneMode,
+			wantSecure:   true,
+		},
+		{
+			name:         "domain",
+			env:          map[string]string{"COOKIE_DOMAIN": ".example.com"},
+			wantSameSite: http.SameSiteLaxMode,
+			wantDomain:   ".example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.CookieSameSite != tt.wantSameSite {
+				t.Errorf("CookieSameSite = %v, want %v", cfg.CookieSameSite, tt.wantSameSite)
+			}
+			if cfg.CookieSecure != tt.wantSecure {
+				t.Errorf("CookieSecure = %v, want %v", cfg.CookieSecure, tt.wantSecure)
+			}
+			if cfg.CookieDomain != tt.wantDomain {
+				t.Errorf("CookieDomain = %q, want %q", cfg.CookieDomain, tt.wantDomain)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsCookieSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "None without Secure",
+			env:     map[string]string{"COOKIE_SAME_SITE": "none"},
+			wantErr: "COOKIE_SAME_SITE=none requires COOKIE_SECURE=true",
+		},
+		{
+			name:    "production default without Secure",
+			env:     map[string]string{"APP_ENV": "production", "COOKIE_SECURE": "false"},
+			wantErr: "COOKIE_SAME_SITE=none requires COOKIE_SECURE=true",
+		},
+		{
+			name:    "unknown SameSite",
+			env:     map[string]string{"COOKIE_SAME_SITE": "sideways"},
+			wantErr: `invalid COOKIE_SAME_SITE: "sideways"`,
+		},
+		{
+			name:    "domain with a path",
+			env:     map[string]string{"COOKIE_DOMAIN": "example.com/app"},
+			wantErr: `invalid COOKIE_DOMAIN: "example.com/app"`,
+		},
+		{
+			name:    "domain with a port",
+			env:     map[string]string{"COOKIE_DOMAIN": "example.com:8080"},
+			wantErr: `invalid COOKIE_DOMAIN: "example.com:8080"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			_, err := Load()
+			if err == nil {
+				t.Fatal("Load() succeeded")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
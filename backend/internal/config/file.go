@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readFile loads a YAML config file. Keys are the environment variable names
+// (see config.example.yaml); values may be scalars or, for list settings such
+// as TRUSTED_PROXIES, sequences of scalars.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, val := range raw {
+		switch v := val.(type) {
+		case nil:
+			values[key] = ""
+		case []any:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if _, nested := item.(map[string]any); nested {
+					return nil, fmt.Errorf("config file key %q: list items must be scalars", key)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			values[key] = strings.Join(items, ",")
+		case map[string]any:
+			return nil, fmt.Errorf("config file key %q: nested objects are not supported", key)
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
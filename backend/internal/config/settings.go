@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Setting is one effective configuration value as printed by `admin config print`
+type Setting struct {
+	Key   string
+	Value string
+}
+
+const redactedValue = "<redacted>"
+
+// Settings returns the effective configuration keyed by environment variable
+// name, in a stable order. With redact set, secrets are masked and passwords
+// embedded in connection URLs are removed.
+func (c *Config) Settings(redact bool) []Setting {
+	secret := func(v string) string {
+		if redact && v != "" {
+			return redactedValue
+		}
+		return v
+	}
+	connURL := func(v string) string {
+		if redact {
+			return redactURL(v)
+		}
+		return v
+	}
+	prefixes := make([]string, 0, len(c.TrustedProxies))
+	for _, p := range c.TrustedProxies {
+		prefixes = append(prefixes, p.String())
+	}
+
+	return []Setting{
+		{"APP_ENV", c.Env},
+		{"LOG_LEVEL", c.LogLevel},
+		{"HTTP_PORT", fmt.Sprint(c.HTTPPort)},
+		{"METRICS_PORT", fmt.Sprint(c.MetricsPort)},
+		{"HTTP_READ_TIMEOUT_SEC", fmt.Sprint(c.ReadTimeoutSec)},
+		{"HTTP_WRITE_TIMEOUT_SEC", fmt.Sprint(c.WriteTimeoutSec)},
+		{"SHUTDOWN_DRAIN_SEC", fmt.Sprint(c.ShutdownDrainSec)},
+		{"POSTGRES_DSN", connURL(c.PostgresDSN)},
+		{"POSTGRES_MAX_OPEN_CONNS", fmt.Sprint(c.PostgresMaxOpenConns)},
+		{"POSTGRES_MAX_IDLE_CONNS", fmt.Sprint(c.PostgresMaxIdleConns)},
+		{"REDIS_URL", connURL(c.RedisURL)},
+		{"REDIS_POOL_SIZE", fmt.Sprint(c.RedisPoolSize)},
+		{"JWT_SECRET", secret(c.JWTSecret)},
+		{"OPENAI_API_KEY", secret(c.OpenAIAPIKey)},
+		{"OPENAI_REALTIME_MODEL", c.OpenAIRealtimeModel},
+		{"ELEVENLABS_API_KEY", secret(c.ElevenLabsAPIKey)},
+		{"ELEVENLABS_VOICE_ID", c.ElevenLabsVoiceID},
+		{"WEBRTC_SFU_URL", c.WebrtcSFUURL},
+		{"WEBRTC_TURN_URL", c.WebrtcTURNURL},
+		{"WEBRTC_TURN_USERNAME", c.WebrtcTURNUsername},
+		{"WEBRTC_TURN_PASSWORD", secret(c.WebrtcTURNPassword)},
+		{"LIVEKIT_URL", c.LiveKitURL},
+		{"LIVEKIT_API_KEY", c.LiveKitAPIKey},
+		{"LIVEKIT_API_SECRET", secret(c.LiveKitAPISecret)},
+		{"TRUSTED_PROXIES", strings.Join(prefixes, ",")},
+		{"CLIENT_IP_HEADER", c.ClientIPHeader},
+		{"CORS_ALLOWED_ORIGINS", strings.Join(c.CORSAllowedOrigins, ",")},
+		{"COOKIE_DOMAIN", c.CookieDomain},
+		{"COOKIE_SECURE", fmt.Sprint(c.CookieSecure)},
+		{"COOKIE_SAME_SITE", sameSiteName(c.CookieSameSite)},
+		{"ACCESS_COOKIE_NAME", c.AccessCookieName},
+		{"REFRESH_COOKIE_NAME", c.RefreshCookieName},
+		{"APP_BASE_URL", c.AppBaseURL},
+		{"API_BASE_URL", c.APIBaseURL},
+		{"TRACING_EXPORTER", c.TracingExporter},
+		{"TRACING_SAMPLE_RATIO", fmt.Sprint(c.TracingSampleRatio)},
+		{"CALENDAR_IMPORT_ALLOW_PRIVATE", fmt.Sprint(c.CalendarImportAllowPrivate)},
+		{"SCHEDULER_ENABLED", fmt.Sprint(c.SchedulerEnabled)},
+		{"SCHEDULER_INTERVAL_SEC", fmt.Sprint(c.SchedulerIntervalSec)},
+		{"MEETING_MISSED_AFTER_MIN", fmt.Sprint(c.MeetingMissedAfterMin)},
+		{"MEETING_AUTO_END_AFTER_MIN", fmt.Sprint(c.MeetingAutoEndAfterMin)},
+		{"MEETING_REMINDER_MIN", fmt.Sprint(c.MeetingReminderMin)},
+		{"MEETING_EVENT_RETENTION_HOURS", fmt.Sprint(c.MeetingEventRetentionHours)},
+		{"SMTP_HOST", c.SMTPHost},
+		{"SMTP_PORT", fmt.Sprint(c.SMTPPort)},
+		{"SMTP_USERNAME", c.SMTPUsername},
+		{"SMTP_PASSWORD", secret(c.SMTPPassword)},
+		{"SMTP_FROM", c.SMTPFrom},
+		{"SMTP_TLS", c.SMTPTLS},
+	}
+}
+
+func sameSiteName(mode http.SameSite) string {
+	switch mode {
+	case http.SameSiteStrictMode:
+		return "strict"
+	case http.SameSiteNoneMode:
+		return "none"
+	default:
+		return "lax"
+	}
+}
+
+// redactURL masks the password of a URL-style DSN. Keyword/value DSNs
+// ("host=... password=...") are masked entirely since they cannot be parsed
+// reliably.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return redactedValue
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
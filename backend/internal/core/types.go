@@ -1,6 +1,9 @@
 package core
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type UserProfile struct {
 	ID        string `json:"id"`
@@ -60,6 +63,22 @@ type AuthLogoutResponse struct {
 	Message string `json:"message"`
 }
 
+type AuthCSRFResponse struct {
+	CSRFToken string `json:"csrfToken"`
+}
+
+type AuthUnlockRequest struct {
+	Email string `json:"email"`
+}
+
+type AuthUnlockConfirmRequest struct {
+	Token string `json:"token"`
+}
+
+type AuthUnlockResponse struct {
+	Message string `json:"message"`
+}
+
 type DashboardSpotlight struct {
 	Quote        string `json:"quote"`
 	Conversation string `json:"conversation"`
@@ -115,6 +134,7 @@ type MeetingSummary struct {
 	Status          string    `json:"status"`
 	Visibility      string    `json:"visibility,omitempty"`
 	HostUserID      string    `json:"hostUserId,omitempty"`
+	SeriesID        string    `json:"seriesId,omitempty"` // set on occurrences of a recurring meeting
 }
 
 type MeetingParticipant struct {
@@ -129,6 +149,37 @@ type AgendaItem struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Duration    int    `json:"durationMinutes"`
+	// Live progress, set while and after the meeting runs
+	Status        string     `json:"status,omitempty"` // pending | current | completed
+	StartedAt     *time.Time `json:"startedAt,omitempty"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+	ActualMinutes int        `json:"actualMinutes,omitempty"` // time spent so far for the current item
+	RanOver       bool       `json:"ranOver,omitempty"`
+}
+
+type MeetingAgendaResponse struct {
+	Agenda []AgendaItem `json:"agenda"`
+}
+
+type CreateAgendaItemRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Duration    int    `json:"durationMinutes"`
+	Position    int    `json:"position,omitempty"` // 1-based; appended when zero
+}
+
+type UpdateAgendaItemRequest struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Duration    *int    `json:"durationMinutes,omitempty"`
+}
+
+type ReorderAgendaRequest struct {
+	ItemIDs []string `json:"itemIds"` // every item of the agenda in the new order
+}
+
+type AgendaProgressRequest struct {
+	Status string `json:"status"` // pending | current | completed
 }
 
 type AiPersona struct {
@@ -138,6 +189,35 @@ type AiPersona struct {
 	Tone        string `json:"tone"`
 	Energy      string `json:"energy"`
 	Description string `json:"description"`
+	// The fields below are only returned by the persona API
+	VoicePresetID string `json:"voicePresetId,omitempty"`
+	Instructions  string `json:"instructions,omitempty"` // system prompt for the AI co-host
+	Custom        bool   `json:"custom,omitempty"`       // owned by the caller rather than built in
+	IsDefault     bool   `json:"isDefault,omitempty"`
+}
+
+type AiPersonasResponse struct {
+	Personas []AiPersona `json:"personas"`
+}
+
+type CreateAiPersonaRequest struct {
+	Name          string `json:"name"`
+	Tone          string `json:"tone"`
+	Energy        string `json:"energy"`
+	VoicePresetID string `json:"voicePresetId"`
+	Description   string `json:"description"`
+	Instructions  string `json:"instructions"`
+	IsDefault     bool   `json:"isDefault"`
+}
+
+type UpdateAiPersonaRequest struct {
+	Name          *string `json:"name,omitempty"`
+	Tone          *string `json:"tone,omitempty"`
+	Energy        *string `json:"energy,omitempty"`
+	VoicePresetID *string `json:"voicePresetId,omitempty"` // empty string unlinks
+	Description   *string `json:"description,omitempty"`
+	Instructions  *string `json:"instructions,omitempty"`
+	IsDefault     *bool   `json:"isDefault,omitempty"`
 }
 
 type MeetingDetail struct {
@@ -147,14 +227,43 @@ type MeetingDetail struct {
 	AiPersona    AiPersona            `json:"aiPersona"`
 	Resources    []ResourceLink       `json:"resources"`
 	Notes        string               `json:"notes"`
+	Recurrence   *MeetingRecurrence   `json:"recurrence,omitempty"` // set on the series itself
+}
+
+// MeetingRecurrence describes how a meeting repeats
+type MeetingRecurrence struct {
+	RRule      string      `json:"rrule"`                // RFC 5545, e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=10
+	TimeZone   string      `json:"timeZone,omitempty"`   // IANA zone the rule is expanded in; defaults to UTC
+	Exceptions []time.Time `json:"exceptions,omitempty"` // cancelled occurrence start times (EXDATE)
 }
 
 type ResourceLink struct {
+	ID    string `json:"id,omitempty"`
 	Title string `json:"title"`
 	URL   string `json:"url"`
 	Type  string `json:"type"`
 }
 
+type MeetingResourcesResponse struct {
+	Resources []ResourceLink `json:"resources"`
+}
+
+type UpdateMeetingResourceRequest struct {
+	Title *string `json:"title,omitempty"`
+	URL   *string `json:"url,omitempty"`
+	Type  *string `json:"type,omitempty"`
+}
+
+type MeetingNotes struct {
+	Content   string     `json:"content"`
+	UpdatedBy string     `json:"updatedBy,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type UpdateMeetingNotesRequest struct {
+	Content string `json:"content"`
+}
+
 type SessionHealthMetrics struct {
 	ConversationBalance string  `json:"conversationBalance"`
 	AverageLatencyMs    int     `json:"averageLatencyMs"`
@@ -163,38 +272,88 @@ type SessionHealthMetrics struct {
 }
 
 type QuickStartTemplate struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Badge       string `json:"badge"`
+	ID              string `json:"id"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	Badge           string `json:"badge"`
+	DurationMinutes int    `json:"durationMinutes,omitempty"`
 }
 
-type MeetingsResponse struct {
-	Scheduled           []MeetingSummary     `json:"scheduled"`
-	QuickStartTemplates []QuickStartTemplate `json:"quickStartTemplates"`
-	SessionHealth       SessionHealthMetrics `json:"sessionHealth"`
+type MeetingTemplate struct {
+	ID              string       `json:"id"`
+	Title           string       `json:"title"`
+	Description     string       `json:"description"`
+	Badge           string       `json:"badge"`
+	DurationMinutes int          `json:"durationMinutes"`
+	AiPersonaID     string       `json:"aiPersonaId,omitempty"`
+	VoiceProfile    string       `json:"voiceProfile,omitempty"`
+	Agenda          []AgendaItem `json:"agenda"`
+	Visibility      string       `json:"visibility"`            // private | all_users (every account)
+	OwnerUserID     string       `json:"ownerUserId,omitempty"` // empty for built-in templates
+	Owned           bool         `json:"owned"`                 // the caller can edit and delete it
 }
 
-type MeetingCreateRequest struct {
+type MeetingTemplatesResponse struct {
+	Templates []MeetingTemplate `json:"templates"`
+}
+
+// CreateMeetingTemplateRequest creates a template from scratch or, when
+// FromMeetingID is set, from an existing meeting; other fields then override
+// what is copied from the meeting.
+type CreateMeetingTemplateRequest struct {
+	FromMeetingID   string       `json:"fromMeetingId,omitempty"`
 	Title           string       `json:"title"`
 	Description     string       `json:"description"`
-	StartTime       time.Time    `json:"startTime"`
 	DurationMinutes int          `json:"durationMinutes"`
-	VoiceProfile    string       `json:"voiceProfile"`
 	AiPersonaID     string       `json:"aiPersonaId"`
+	VoiceProfile    string       `json:"voiceProfile"`
 	Agenda          []AgendaItem `json:"agenda"`
-	IsInstant       bool         `json:"isInstant,omitempty"`
-	Visibility      string       `json:"visibility,omitempty"` // private | public
+	Visibility      string       `json:"visibility"`
+}
+
+type UpdateMeetingTemplateRequest struct {
+	Title           *string       `json:"title,omitempty"`
+	Description     *string       `json:"description,omitempty"`
+	DurationMinutes *int          `json:"durationMinutes,omitempty"`
+	AiPersonaID     *string       `json:"aiPersonaId,omitempty"`
+	VoiceProfile    *string       `json:"voiceProfile,omitempty"`
+	Agenda          *[]AgendaItem `json:"agenda,omitempty"` // replaces the whole agenda
+	Visibility      *string       `json:"visibility,omitempty"`
+}
+
+type MeetingsResponse struct {
+	Scheduled           []MeetingSummary     `json:"scheduled"`
+	QuickStartTemplates []QuickStartTemplate `json:"quickStartTemplates"`
+	SessionHealth       SessionHealthMetrics `json:"sessionHealth"`
+}
+
+type MeetingCreateRequest struct {
+	Title           string             `json:"title"`
+	Description     string             `json:"description"`
+	StartTime       time.Time          `json:"startTime"`
+	DurationMinutes int                `json:"durationMinutes"`
+	VoiceProfile    string             `json:"voiceProfile"`
+	AiPersonaID     string             `json:"aiPersonaId"`
+	Agenda          []AgendaItem       `json:"agenda"`
+	IsInstant       bool               `json:"isInstant,omitempty"`
+	Visibility      string             `json:"visibility,omitempty"` // private | public
+	Recurrence      *MeetingRecurrence `json:"recurrence,omitempty"`
+	Resources       []ResourceLink     `json:"resources,omitempty"`
+	Notes           string             `json:"notes,omitempty"`
+	LobbyEnabled    bool               `json:"lobbyEnabled,omitempty"`
 }
 
 type MeetingUpdateRequest struct {
-	Title           *string      `json:"title,omitempty"`
-	Description     *string      `json:"description,omitempty"`
-	StartTime       *time.Time   `json:"startTime,omitempty"`
-	DurationMinutes *int         `json:"durationMinutes,omitempty"`
-	VoiceProfile    *string      `json:"voiceProfile,omitempty"`
-	AiPersonaID     *string      `json:"aiPersonaId,omitempty"`
-	Agenda          []AgendaItem `json:"agenda,omitempty"`
+	Title           *string            `json:"title,omitempty"`
+	Description     *string            `json:"description,omitempty"`
+	StartTime       *time.Time         `json:"startTime,omitempty"`
+	DurationMinutes *int               `json:"durationMinutes,omitempty"`
+	VoiceProfile    *string            `json:"voiceProfile,omitempty"`
+	AiPersonaID     *string            `json:"aiPersonaId,omitempty"`
+	Agenda          []AgendaItem       `json:"agenda,omitempty"`
+	Scope           string             `json:"scope,omitempty"` // occurrence | following | series (recurring meetings)
+	Recurrence      *MeetingRecurrence `json:"recurrence,omitempty"`
+	Visibility      *string            `json:"visibility,omitempty"` // private | public
 }
 
 type MeetingDetailResponse struct {
@@ -212,6 +371,10 @@ type MeetingJoinResponse struct {
 	// Future: LiveKit integration
 	LiveKitToken string `json:"livekitToken,omitempty"`
 	LiveKitURL   string `json:"livekitUrl,omitempty"`
+	// Status is "waiting" while the participant is held in the lobby; tokens
+	// are only issued once the host admits them. PollURL reports the decision.
+	Status  string `json:"status,omitempty"` // joined | waiting
+	PollURL string `json:"pollUrl,omitempty"`
 }
 
 type MeetingInvite struct {
@@ -231,6 +394,234 @@ type MeetingInvitesResponse struct {
 	Invites []MeetingInvite `json:"invites"`
 }
 
+// CalendarImportRequest imports meetings from iCalendar data, given either
+// inline (the contents of an uploaded .ics file) or as a URL to fetch
+type CalendarImportRequest struct {
+	ICS          string `json:"ics,omitempty"`
+	URL          string `json:"url,omitempty"` // http(s) or webcal
+	AiPersonaID  string `json:"aiPersonaId,omitempty"`
+	VoiceProfile string `json:"voiceProfile,omitempty"`
+}
+
+type CalendarImportResult struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title,omitempty"`
+	Status    string `json:"status"` // created | updated | unchanged | cancelled | skipped | failed
+	MeetingID string `json:"meetingId,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type CalendarImportResponse struct {
+	Results []CalendarImportResult `json:"results"`
+}
+
+// CalendarFeedResponse carries the secret subscription URL of a user's
+// calendar feed; it is only shown when the token is issued
+type CalendarFeedResponse struct {
+	URL string `json:"url"`
+}
+
+// MeetingAccessSettings are the host's controls over who can enter a meeting
+type MeetingAccessSettings struct {
+	Visibility           string `json:"visibility"` // private | public
+	Locked               bool   `json:"locked"`     // nobody but the host can join
+	RequireGuestApproval bool   `json:"requireGuestApproval"`
+	GuestLinkActive      bool   `json:"guestLinkActive"`
+	LobbyEnabled         bool   `json:"lobbyEnabled"` // participants wait for the host to admit them
+}
+
+type UpdateMeetingAccessRequest struct {
+	Visibility           *string `json:"visibility,omitempty"`
+	Locked               *bool   `json:"locked,omitempty"`
+	RequireGuestApproval *bool   `json:"requireGuestApproval,omitempty"`
+	LobbyEnabled         *bool   `json:"lobbyEnabled,omitempty"`
+}
+
+// MeetingPermissions is the caller's role in a meeting and what it allows
+type MeetingPermissions struct {
+	Role        string   `json:"role"` // host | co_host | presenter | viewer; empty without access
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateParticipantRoleRequest struct {
+	Role string `json:"role"` // co_host | presenter | viewer
+}
+
+// TransferMeetingRequest hands a meeting to another user; the previous host
+// stays on as co-host
+type TransferMeetingRequest struct {
+	UserID string `json:"userId"`
+}
+
+// LobbyEntry is a participant's admission request and the host's decision
+type LobbyEntry struct {
+	UserID      string     `json:"userId"`
+	Name        string     `json:"name"`
+	AvatarURL   string     `json:"avatarUrl"`
+	Status      string     `json:"status"` // waiting | admitted | denied
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+}
+
+type MeetingLobbyResponse struct {
+	Enabled bool         `json:"enabled"`
+	Entries []LobbyEntry `json:"entries"`
+}
+
+type UpdateLobbyEntryRequest struct {
+	Status string `json:"status"` // admitted | denied
+}
+
+// LobbyStatusResponse is what a participant polls while waiting
+type LobbyStatusResponse struct {
+	Status      string     `json:"status"` // none | waiting | admitted | denied
+	RequestedAt *time.Time `json:"requestedAt,omitempty"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+}
+
+// MeetingEvent is one entry of a meeting's event stream. Ids increase within
+// a meeting; Data depends on Type.
+type MeetingEvent struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	MeetingID string          `json:"meetingId"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// MeetingStatusEvent is the data of meeting.started and meeting.ended
+type MeetingStatusEvent struct {
+	Status string `json:"status"`
+	UserID string `json:"userId,omitempty"`
+}
+
+// ParticipantEvent is the data of participant.joined and participant.left
+type ParticipantEvent struct {
+	ParticipantID string `json:"participantId"`
+	Name          string `json:"name"`
+	Guest         bool   `json:"guest,omitempty"`
+}
+
+// AgendaProgressEvent is the data of agenda.progress
+type AgendaProgressEvent struct {
+	ItemID string       `json:"itemId"`
+	Status string       `json:"status"`
+	Agenda []AgendaItem `json:"agenda"`
+}
+
+// LobbyEvent is the data of lobby.requested and lobby.decided
+type LobbyEvent struct {
+	ParticipantID string `json:"participantId"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	Guest         bool   `json:"guest,omitempty"`
+}
+
+// Notification is an entry of a user's in-app inbox
+type Notification struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Link      string     `json:"link,omitempty"`
+	MeetingID string     `json:"meetingId,omitempty"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unreadCount"`
+}
+
+type UpdateNotificationRequest struct {
+	Read *bool `json:"read"`
+}
+
+// NotificationPreference lists which channels deliver one notification type
+type NotificationPreference struct {
+	Type     string          `json:"type"`
+	Channels map[string]bool `json:"channels"`
+}
+
+// NotificationPreferencesResponse carries a user's choices per notification
+// type. Enabled mirrors the notifications switch of the user's settings,
+// which overrides them all.
+type NotificationPreferencesResponse struct {
+	Enabled     bool                     `json:"enabled"`
+	Channels    []string                 `json:"channels"`
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+// NotificationWebhook is the endpoint receiving a user's webhook
+// notifications. The signing secret is only shown when the webhook is saved.
+type NotificationWebhook struct {
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type UpdateNotificationWebhookRequest struct {
+	URL string `json:"url"`
+}
+
+// GuestLinkResponse carries the shareable join link of a public meeting; it
+// is only shown when the link is issued
+type GuestLinkResponse struct {
+	URL string `json:"url"`
+}
+
+type MeetingGuest struct {
+	ID          string     `json:"id"`
+	DisplayName string     `json:"displayName"`
+	Status      string     `json:"status"` // waiting | admitted | denied
+	CreatedAt   time.Time  `json:"createdAt"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+}
+
+type MeetingGuestsResponse struct {
+	Guests []MeetingGuest `json:"guests"`
+}
+
+type UpdateMeetingGuestRequest struct {
+	Status string `json:"status"` // admitted | denied
+}
+
+// GuestMeetingPreview is what a guest link reveals before joining
+type GuestMeetingPreview struct {
+	Title                string    `json:"title"`
+	HostName             string    `json:"hostName"`
+	StartTime            time.Time `json:"startTime"`
+	DurationMinutes      int       `json:"durationMinutes"`
+	Status               string    `json:"status"`
+	Locked               bool      `json:"locked"`
+	RequireGuestApproval bool      `json:"requireGuestApproval"`
+}
+
+type GuestJoinRequest struct {
+	DisplayName string `json:"displayName"`
+}
+
+// GuestJoinResponse describes a guest identity. GuestToken is only returned
+// when the identity is created and must be sent as X-Guest-Token to poll its
+// status; Join is set once the guest is admitted and the meeting is running.
+type GuestJoinResponse struct {
+	GuestID       string               `json:"guestId"`
+	GuestToken    string               `json:"guestToken,omitempty"`
+	DisplayName   string               `json:"displayName"`
+	Status        string               `json:"status"`
+	MeetingStatus string               `json:"meetingStatus"`
+	ExpiresAt     time.Time            `json:"expiresAt"`
+	Join          *MeetingJoinResponse `json:"join,omitempty"`
+}
+
 type TurnCredentials struct {
 	URL      string `json:"url"`
 	Username string `json:"username"`
@@ -3,13 +3,15 @@ package httpapi
 import (
 	"encoding/json"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
-	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
 	httpapimiddleware "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/middleware"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
 	v1 "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/v1"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/metrics"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -26,19 +28,25 @@ type Dependencies struct {
 
 type API struct {
 	cfg         *config.Config
-	logger      contracts.Logger
+	logger      logger.Logger
 	service     *services.AppService
 	rateLimiter *RateLimiter
+	postgres    *pgxpool.Pool
+	redis       *redis.Client
+	// draining is set once shutdown starts so readiness fails first
+	draining atomic.Bool
 }
 
-func New(cfg *config.Config, logger contracts.Logger, deps Dependencies) *API {
-	rateLimiter := NewRateLimiter(logger)
+func New(cfg *config.Config, log logger.Logger, deps Dependencies) *API {
+	rateLimiter := NewRateLimiter(log)
 
 	return &API{
 		cfg:         cfg,
-		logger:      logger,
+		logger:      log,
 		service:     deps.Service,
 		rateLimiter: rateLimiter,
+		postgres:    deps.Postgres,
+		redis:       deps.Redis,
 	}
 }
 
@@ -47,7 +55,12 @@ func (api *API) Routes() http.Handler {
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
-	router.Use(middleware.RealIP)
+	// Resolve the client IP once, honouring forwarding headers only from trusted proxies
+	router.Use(httpapimiddleware.ClientIP(api.cfg.TrustedProxies, api.cfg.ClientIPHeader))
+	// Request-scoped log fields and one access log line per request
+	router.Use(httpapimiddleware.AccessLog(api.logger))
+	router.Use(httpapimiddleware.Metrics)
+	router.Use(httpapimiddleware.Tracing)
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.Timeout(60 * time.Second))
 
@@ -60,17 +73,27 @@ func (api *API) Routes() http.Handler {
 	router.Use(api.rateLimiter.Middleware())
 
 	router.Use(cors.Handler(cors.Options{
-		// In development, allow the Vite/React dev server origins.
-		// In production, this should be restricted to your real frontend origin.
-		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:5173"},
+		// Defaults to the Vite/React dev server origins; production must list
+		// the real frontend origins in CORS_ALLOWED_ORIGINS.
+		// traceparent/tracestate carry W3C trace context from the frontend;
+		// guests poll their status with X-Guest-Token.
+		AllowedOrigins:   api.cfg.CORSAllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Guest-Token", "traceparent", "tracestate"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	// Health check (no versioning)
+	// Health checks (no versioning). /healthz is kept as an alias of /livez
+	// for existing deployments.
 	router.Get("/healthz", api.HandleHealth)
+	router.Get("/livez", api.HandleLiveness)
+	router.Get("/readyz", api.HandleReadiness)
+
+	// Metrics are only served on the public router when no dedicated port is configured
+	if api.cfg.MetricsPort == 0 {
+		router.Handle("/metrics", metrics.Handler())
+	}
 
 	// Versioned API routes
 	router.Route("/api/v1", func(r chi.Router) {
@@ -94,7 +117,7 @@ func (api *API) RespondJSON(w http.ResponseWriter, status int, payload any) {
 
 // RespondError writes an error response
 func (api *API) RespondError(w http.ResponseWriter, status int, msg string) {
-	api.RespondJSON(w, status, APIError{Message: msg})
+	response.Error(w, status, msg)
 }
 
 // Service returns the app service
@@ -103,7 +126,7 @@ func (api *API) Service() *services.AppService {
 }
 
 // Logger returns the logger
-func (api *API) Logger() contracts.Logger {
+func (api *API) Logger() logger.Logger {
 	return api.logger
 }
 
@@ -122,24 +145,21 @@ func (api *API) EnsureService(w http.ResponseWriter) bool {
 }
 
 // RespondServiceError handles service errors with appropriate status codes
-func (api *API) RespondServiceError(w http.ResponseWriter, err error) {
+func (api *API) RespondServiceError(w http.ResponseWriter, r *http.Request, err error) {
 	if err == nil {
 		return
 	}
 
-	status := response.StatusFromError(err)
+	status, body := response.FromError(err)
 	if status >= http.StatusInternalServerError {
-		api.logger.Printf("service error: %v", err)
-		api.RespondError(w, status, "internal server error")
-		return
+		api.logger.ErrorContext(r.Context(), "service error", "error", err)
 	}
-
-	api.RespondError(w, status, err.Error())
+	response.JSON(w, status, body)
 }
 
 // HandleHealth handles GET /healthz
 func (api *API) HandleHealth(w http.ResponseWriter, r *http.Request) {
-	api.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	api.HandleLiveness(w, r)
 }
 
 // Stop stops background processes (rate limiter cleanup)
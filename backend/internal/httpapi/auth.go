@@ -2,84 +2,53 @@ package httpapi
 
 import (
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 const accessTokenTTL = 15 * time.Minute // Increased to 30 minutes for better UX
 
-// Cookie helper functions
-// SetAccessTokenCookie sets the access token cookie
-func (api *API) SetAccessTokenCookie(w http.ResponseWriter, token string) {
-	// Secure flag should only be true in production (HTTPS required)
-	// In development, we need Secure=false for localhost
-	secure := false
-	Samesite := http.SameSiteLaxMode
-
-	if api.cfg.Env == "production" {
-		Samesite = http.SameSiteNoneMode
-		secure = true
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     "nl_access",
-		Value:    token,
+// authCookie builds an auth cookie using the configured domain, Secure and
+// SameSite attributes so that set and clear always match
+func (api *API) authCookie(name, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
 		Path:     "/",
+		Domain:   api.cfg.CookieDomain,
 		HttpOnly: true,
-		Secure:   secure,
-		SameSite: Samesite, // Cross-site for localhost:3000 -> localhost:8080
-		MaxAge:   int(accessTokenTTL.Seconds()),
-	})
+		Secure:   api.cfg.CookieSecure,
+		SameSite: api.cfg.CookieSameSite,
+	}
+}
+
+// SetAccessTokenCookie sets the access token cookie
+func (api *API) SetAccessTokenCookie(w http.ResponseWriter, token string) {
+	cookie := api.authCookie(api.cfg.AccessCookieName, token)
+	cookie.MaxAge = int(accessTokenTTL.Seconds())
+	http.SetCookie(w, cookie)
 }
 
 // SetRefreshTokenCookie sets the refresh token cookie
 func (api *API) SetRefreshTokenCookie(w http.ResponseWriter, token string, expiresAt time.Time) {
-	// Secure flag should only be true in production (HTTPS required)
-	secure := false
-	Samesite := http.SameSiteLaxMode
-
-	if api.cfg.Env == "production" {
-		Samesite = http.SameSiteNoneMode
-		secure = true
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     "nl_refresh",
-		Value:    token,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   secure,
-		SameSite: Samesite,
-		Expires:  expiresAt,
-	})
+	cookie := api.authCookie(api.cfg.RefreshCookieName, token)
+	cookie.Expires = expiresAt
+	http.SetCookie(w, cookie)
 }
 
 // ClearAuthCookies clears both access and refresh token cookies
 func (api *API) ClearAuthCookies(w http.ResponseWriter) {
-	secure := api.cfg.Env == "production"
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     "nl_access",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   secure,
-		SameSite: http.SameSiteNoneMode,
-		MaxAge:   -1,
-	})
-	http.SetCookie(w, &http.Cookie{
-		Name:     "nl_refresh",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   secure,
-		SameSite: http.SameSiteNoneMode,
-		MaxAge:   -1,
-	})
+	for _, name := range []string{api.cfg.AccessCookieName, api.cfg.RefreshCookieName} {
+		cookie := api.authCookie(name, "")
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+	}
 }
 
 // AuthMiddleware provides authentication middleware for protected routes
@@ -88,7 +57,7 @@ func (api *API) AuthMiddleware(next http.Handler) http.Handler {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Read access token from cookie (not header)
-		cookie, err := r.Cookie("nl_access")
+		cookie, err := r.Cookie(api.cfg.AccessCookieName)
 		if err != nil {
 			// Return error with code for frontend to distinguish
 			api.RespondJSON(w, http.StatusUnauthorized, map[string]any{
@@ -175,6 +144,7 @@ func (api *API) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		logger.AddAttrs(r.Context(), slog.String("user_id", userID))
 		ctx := httpapicontext.WithAuthContext(r.Context(), userID, sessionID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
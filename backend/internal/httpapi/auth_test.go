@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
+)
+
+func TestAuthCookieAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		domain   string
+		secure   bool
+		sameSite http.SameSite
+	}{
+		{name: "development defaults", sameSite: http.SameSiteLaxMode},
+		{name: "strict", secure: true, sameSite: http.SameSiteStrictMode},
+		{name: "cross-site with domain", domain: "example.com", secure: true, sameSite: http.SameSiteNoneMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &API{cfg: &config.Config{
+				CookieDomain:      tt.domain,
+				CookieSecure:      tt.secure,
+				CookieSameSite:    tt.sameSite,
+				AccessCookieName:  "test_access",
+				RefreshCookieName: "test_refresh",
+			}}
+
+			rec := httptest.NewRecorder()
+			api.SetAccessTokenCookie(rec, "access")
+			api.SetRefreshTokenCookie(rec, "refresh", time.Now().Add(time.Hour))
+			api.ClearAuthCookies(rec)
+
+			cookies := rec.Result().Cookies()
+			if len(cookies) != 4 {
+				t.Fatalf("got %d cookies, want 4", len(cookies))
+			}
+			for i, c := range cookies {
+				wantName := "test_access"
+				if i%2 == 1 {
+					wantName = "test_refresh"
+				}
+				if c.Name != wantName {
+					t.Errorf("cookie %d name = %q, want %q", i, c.Name, wantName)
+				}
+				if c.Domain != tt.domain {
+					t.Errorf("%s domain = %q, want %q", c.Name, c.Domain, tt.domain)
+				}
+				if c.Secure != tt.secure {
+					t.Errorf("%s secure = %v, want %v", c.Name, c.Secure, tt.secure)
+				}
+				if c.SameSite != tt.sameSite {
+					t.Errorf("%s SameSite = %v, want %v", c.Name, c.SameSite, tt.sameSite)
+				}
+				if !c.HttpOnly || c.Path != "/" {
+					t.Errorf("%s HttpOnly = %v, Path = %q", c.Name, c.HttpOnly, c.Path)
+				}
+			}
+			// The clearing cookies come last and must expire the others
+			for _, c := range cookies[2:] {
+				if c.MaxAge >= 0 {
+					t.Errorf("cleared %s MaxAge = %d, want < 0", c.Name, c.MaxAge)
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ type contextKey string
 var (
 	userIDContextKey    contextKey = "userID"
 	sessionIDContextKey contextKey = "sessionID"
+	clientIPContextKey  contextKey = "clientIP"
 )
 
 // UserIDFromContext extracts user ID from request context
@@ -50,3 +51,18 @@ func WithAuthContext(ctx context.Context, userID, sessionID string) context.Cont
 	return ctx
 }
 
+// ClientIPFromContext extracts the resolved client IP from request context
+func ClientIPFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if val, ok := ctx.Value(clientIPContextKey).(string); ok {
+		return val
+	}
+	return ""
+}
+
+// WithClientIP adds the resolved client IP to the context
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey, ip)
+}
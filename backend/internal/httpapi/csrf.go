@@ -0,0 +1,102 @@
+package httpapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+)
+
+// CSRFHeader carries the token returned by GET /api/v1/auth/csrf
+const CSRFHeader = "X-CSRF-Token"
+
+// CSRFToken derives the CSRF token for a session. The token is an HMAC of the
+// session id, so it needs no storage, survives refresh-token rotation and is
+// invalidated by logging out. It is returned in a response body rather than a
+// cookie so it also works when the frontend is on a different site.
+func (api *API) CSRFToken(sessionID string) string {
+	mac := hmac.New(sha256.New, []byte(api.cfg.JWTSecret))
+	mac.Write([]byte("csrf:" + sessionID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CSRFMiddleware requires a valid X-CSRF-Token on state-changing requests.
+// It must run after AuthMiddleware, which provides the session id.
+func (api *API) CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sessionID := httpapicontext.SessionIDFromContext(r.Context())
+		provided := strings.TrimSpace(r.Header.Get(CSRFHeader))
+		expected := api.CSRFToken(sessionID)
+		if sessionID == "" || provided == "" || !hmac.Equal([]byte(provided), []byte(expected)) {
+			api.RespondJSON(w, http.StatusForbidden, map[string]any{
+				"message": "invalid or missing CSRF token",
+				"code":    "csrf_invalid",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TrustedOriginMiddleware protects the public auth routes, which cannot carry a
+// session-bound token because they run before a session exists (login,
+// register, unlock) or authenticate with the refresh cookie alone (refresh).
+// Browsers always send Origin on cross-origin POSTs, so requests from origins
+// outside CORS_ALLOWED_ORIGINS are rejected; Referer is checked instead when a
+// request has no Origin. Requests with neither (non-browser clients) are
+// allowed since they cannot ride on a victim's cookies.
+func (api *API) TrustedOriginMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := requestOrigin(r)
+		if isSafeMethod(r.Method) || origin == "" || api.isAllowedOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		api.RespondJSON(w, http.StatusForbidden, map[string]any{
+			"message": "origin not allowed",
+			"code":    "csrf_invalid",
+		})
+	})
+}
+
+// requestOrigin returns the Origin header, or the origin of the Referer when
+// there is none. A Referer that is not an absolute URL is returned as is, so
+// it never matches an allowed origin.
+func requestOrigin(r *http.Request) string {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		return origin
+	}
+	referer := r.Header.Get("Referer")
+	if u, err := url.Parse(referer); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return referer
+}
+
+func (api *API) isAllowedOrigin(origin string) bool {
+	for _, allowed := range api.cfg.CORSAllowedOrigins {
+		if strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
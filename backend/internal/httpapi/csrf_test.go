@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+)
+
+func newCSRFTestAPI() *API {
+	return &API{cfg: &config.Config{
+		JWTSecret:          "csrf-test-secret",
+		CORSAllowedOrigins: []string{"https://app.example.com/", "http://localhost:5173"},
+	}}
+}
+
+// serve runs a request through the middleware and reports whether it reached
+// the handler, along with the response status
+func serve(middleware func(http.Handler) http.Handler, r *http.Request) (bool, int) {
+	reached := false
+	h := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return reached, rec.Code
+}
+
+func TestCSRFMiddleware(t *testing.T) {
+	api := newCSRFTestAPI()
+	token := api.CSRFToken("session-1")
+
+	tests := []struct {
+		name      string
+		method    string
+		sessionID string
+		headers   map[string]string
+		wantPass  bool
+	}{
+		{name: "valid token", method: http.MethodPost, sessionID: "session-1", headers: map[string]string{CSRFHeader: token}, wantPass: true},
+		{name: "token with surrounding space", method: http.MethodDelete, sessionID: "session-1", headers: map[string]string{CSRFHeader: " " + token + " "}, wantPass: true},
+		{name: "missing token", method: http.MethodPost, sessionID: "session-1"},
+		{name: "token of another session", method: http.MethodPut, sessionID: "session-2", headers: map[string]string{CSRFHeader: token}},
+		{name: "tampered token", method: http.MethodPatch, sessionID: "session-1", headers: map[string]string{CSRFHeader: token + "x"}},
+		{name: "no session", method: http.MethodPost, headers: map[string]string{CSRFHeader: api.CSRFToken("")}},
+		{name: "bearer header does not bypass the check", method: http.MethodPost, sessionID: "session-1", headers: map[string]string{"Authorization": "Bearer " + token}},
+		{name: "GET needs no token", method: http.MethodGet, sessionID: "session-1", wantPass: true},
+		{name: "HEAD needs no token", method: http.MethodHead, wantPass: true},
+		{name: "OPTIONS needs no token", method: http.MethodOptions, wantPass: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/v1/meetings", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if tt.sessionID != "" {
+				r = r.WithContext(httpapicontext.WithAuthContext(r.Context(), "user-1", tt.sessionID))
+			}
+
+			reached, status := serve(api.CSRFMiddleware, r)
+			if reached != tt.wantPass {
+				t.Fatalf("handler reached = %v, want %v (status %d)", reached, tt.wantPass, status)
+			}
+			if !tt.wantPass && status != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", status, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestTrustedOriginMiddleware(t *testing.T) {
+	api := newCSRFTestAPI()
+
+	tests := []struct {
+		name     string
+		method   string
+		origin   string
+		referer  string
+		wantPass bool
+	}{
+		{name: "allowed origin", method: http.MethodPost, origin: "https://app.example.com", wantPass: true},
+		{name: "allowed origin in another case", method: http.MethodPost, origin: "HTTPS://APP.EXAMPLE.COM", wantPass: true},
+		{name: "second allowed origin", method: http.MethodPost, origin: "http://localhost:5173", wantPass: true},
+		{name: "foreign origin", method: http.MethodPost, origin: "https://evil.example.net"},
+		{name: "allowed host on another scheme", method: http.MethodPost, origin: "http://app.example.com"},
+		{name: "allowed host on another port", method: http.MethodPost, origin: "http://localhost:3000"},
+		{name: "opaque origin", method: http.MethodPost, origin: "null"},
+		{name: "origin wins over referer", method: http.MethodPost, origin: "https://evil.example.net", referer: "https://app.example.com/login"},
+		{name: "allowed referer", method: http.MethodPost, referer: "https://app.example.com/login?next=/", wantPass: true},
+		{name: "foreign referer", method: http.MethodPost, referer: "https://evil.example.net/app.example.com"},
+		{name: "relative referer", method: http.MethodPost, referer: "/login"},
+		{name: "no origin or referer", method: http.MethodPost, wantPass: true},
+		{name: "safe method from foreign origin", method: http.MethodGet, origin: "https://evil.example.net", wantPass: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/v1/auth/login", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.referer != "" {
+				r.Header.Set("Referer", tt.referer)
+			}
+
+			reached, status := serve(api.TrustedOriginMiddleware, r)
+			if reached != tt.wantPass {
+				t.Fatalf("handler reached = %v, want %v (status %d)", reached, tt.wantPass, status)
+			}
+			if !tt.wantPass && status != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", status, http.StatusForbidden)
+			}
+		})
+	}
+}
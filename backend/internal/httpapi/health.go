@@ -0,0 +1,125 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/migrate"
+)
+
+// readinessCheckTimeout bounds each dependency check so a hung dependency
+// cannot stall the probe past the load balancer's own timeout
+const readinessCheckTimeout = 2 * time.Second
+
+const (
+	checkStatusOK       = "ok"
+	checkStatusFailed   = "failed"
+	checkStatusDisabled = "disabled"
+)
+
+// DependencyCheck is the result of probing a single dependency
+type DependencyCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessResponse is returned by GET /readyz
+type ReadinessResponse struct {
+	Status string                     `json:"status"`
+	Checks map[string]DependencyCheck `json:"checks"`
+}
+
+// HandleLiveness handles GET /livez. It only reports that the process is
+// serving requests and never touches dependencies, so a database outage does
+// not get the pod restarted.
+func (api *API) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	api.RespondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleReadiness handles GET /readyz. It returns 503 when Postgres is
+// unreachable, Redis is configured but unreachable, migrations are behind,
+// or the server is draining for shutdown.
+func (api *API) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	ready := true
+	checks := make(map[string]DependencyCheck, 3)
+
+	if api.draining.Load() {
+		ready = false
+		checks["shutdown"] = DependencyCheck{Status: checkStatusFailed, Error: "server is shutting down"}
+	}
+
+	checks["postgres"] = api.runCheck(r.Context(), func(ctx context.Context) error {
+		if api.postgres == nil {
+			return fmt.Errorf("postgres is not configured")
+		}
+		return api.postgres.Ping(ctx)
+	})
+
+	checks["migrations"] = api.runCheck(r.Context(), func(ctx context.Context) error {
+		if api.postgres == nil {
+			return fmt.Errorf("postgres is not configured")
+		}
+		latest, err := migrate.LatestVersion()
+		if err != nil {
+			return err
+		}
+		current, err := migrate.CurrentVersion(ctx, api.postgres)
+		if err != nil {
+			return err
+		}
+		if current < latest {
+			return fmt.Errorf("schema at version %d, expected %d", current, latest)
+		}
+		return nil
+	})
+
+	if api.redis != nil {
+		checks["redis"] = api.runCheck(r.Context(), func(ctx context.Context) error {
+			return api.redis.Ping(ctx).Err()
+		})
+	} else {
+		// Redis is optional: rate limiting and login throttling fall back to
+		// in-process and Postgres storage
+		checks["redis"] = DependencyCheck{Status: checkStatusDisabled}
+	}
+
+	for _, check := range checks {
+		if check.Status == checkStatusFailed {
+			ready = false
+		}
+	}
+
+	resp := ReadinessResponse{Status: "ready", Checks: checks}
+	status := http.StatusOK
+	if !ready {
+		resp.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+	}
+	api.RespondJSON(w, status, resp)
+}
+
+func (api *API) runCheck(ctx context.Context, check func(context.Context) error) DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := DependencyCheck{
+		Status:    checkStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = checkStatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// BeginShutdown marks the API as not ready so /readyz fails while in-flight
+// requests finish and the load balancer stops routing new traffic here
+func (api *API) BeginShutdown() {
+	api.draining.Store(true)
+}
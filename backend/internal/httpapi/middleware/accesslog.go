@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessLog installs request-scoped log attributes (request id, client ip, method,
+// path) and writes one access log line per request with route, status and latency.
+// It must run after chi's RequestID and ClientIP middleware.
+func AccessLog(log logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ctx := logger.WithAttrs(r.Context(),
+				slog.String("request_id", chimiddleware.GetReqID(r.Context())),
+				slog.String("client_ip", httpapicontext.ClientIPFromContext(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+			)
+			r = r.WithContext(ctx)
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			route := ""
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				route = rctx.RoutePattern()
+			}
+
+			attrs := []any{
+				slog.String("route", route),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("latency", time.Since(start)),
+			}
+
+			switch {
+			case isProbePath(r.URL.Path):
+				// Probes hit every few seconds; a failing readiness probe is
+				// expected while draining and must not flood error logs
+				log.DebugContext(ctx, "http request", attrs...)
+			case status >= http.StatusInternalServerError:
+				log.ErrorContext(ctx, "http request", attrs...)
+			default:
+				log.InfoContext(ctx, "http request", attrs...)
+			}
+		})
+	}
+}
+
+func isProbePath(path string) bool {
+	switch path {
+	case "/healthz", "/livez", "/readyz":
+		return true
+	}
+	return false
+}
@@ -39,7 +39,7 @@ func ContentTypeJSON(next http.Handler) http.Handler {
 				if r.ContentLength > 0 {
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusUnsupportedMediaType)
-					_, _ = io.WriteString(w, `{"message":"Content-Type must be application/json"}`)
+					_, _ = io.WriteString(w, `{"message":"Content-Type must be application/json","code":"unsupported_media_type"}`)
 					return
 				}
 			}
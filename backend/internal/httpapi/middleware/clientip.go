@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+)
+
+// ClientIP resolves the real client IP once per request and stores it in the
+// request context. Forwarding is only honoured when the direct peer is one of
+// the trusted proxies, and only through the one header those proxies set;
+// clients can send the others too. The chain is walked from the right,
+// skipping trusted hops, so a client cannot spoof its address by prepending
+// entries.
+func ClientIP(trusted []netip.Prefix, header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ResolveClientIP(r, trusted, header)
+			ctx := httpapicontext.WithClientIP(r.Context(), ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ResolveClientIP returns the client IP for a request given the trusted proxy
+// list and the forwarding header they set: X-Forwarded-For, Forwarded or
+// X-Real-IP
+func ResolveClientIP(r *http.Request, trusted []netip.Prefix, header string) string {
+	remote, ok := parseHost(r.RemoteAddr)
+	if !ok {
+		return remoteHost(r.RemoteAddr)
+	}
+	if !isTrusted(remote, trusted) {
+		return remote.String()
+	}
+
+	var hops []string
+	switch http.CanonicalHeaderKey(header) {
+	case "Forwarded":
+		hops = forwardedFor(r.Header.Values("Forwarded"))
+	case "X-Real-Ip":
+		// The proxy overwrites X-Real-IP, so the last value is its own
+		values := r.Header.Values("X-Real-IP")
+		if len(values) > 0 {
+			hops = values[len(values)-1:]
+		}
+	default:
+		hops = xForwardedFor(r.Header.Values("X-Forwarded-For"))
+	}
+	if len(hops) == 0 {
+		return remote.String()
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseHost(hops[i])
+		if !ok {
+			// Unparseable entry (e.g. "unknown" or an obfuscated identifier):
+			// everything to its left is untrustworthy, so stop at the last good hop.
+			break
+		}
+		client = hop
+		if !isTrusted(hop, trusted) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// xForwardedFor flattens all X-Forwarded-For header values into a list of hops
+func xForwardedFor(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				hops = append(hops, part)
+			}
+		}
+	}
+	return hops
+}
+
+// forwardedFor extracts the for= parameters from Forwarded header values.
+// Example: `for=192.0.2.60;proto=http, for="[2001:db8::1]:4711"`
+func forwardedFor(values []string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, val, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if !found || !strings.EqualFold(key, "for") {
+					continue
+				}
+				hops = append(hops, strings.Trim(strings.TrimSpace(val), `"`))
+			}
+		}
+	}
+	return hops
+}
+
+// parseHost parses an IP with an optional port and optional IPv6 brackets
+func parseHost(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return netip.Addr{}, false
+	}
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func remoteHost(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name    string
+		remote  string
+		header  string
+		headers map[string][]string
+		want    string
+	}{
+		{
+			name:   "untrusted peer ignores forwarding headers",
+			remote: "203.0.113.7:5000",
+			header: "X-Forwarded-For",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"1.2.3.4"},
+				"Forwarded":       {"for=1.2.3.4"},
+				"X-Real-IP":       {"1.2.3.4"},
+			},
+			want: "203.0.113.7",
+		},
+		{
+			name:    "trusted proxy appends to X-Forwarded-For",
+			remote:  "10.0.0.2:5000",
+			header:  "X-Forwarded-For",
+			headers: map[string][]string{"X-Forwarded-For": {"198.51.100.9"}},
+			want:    "198.51.100.9",
+		},
+		{
+			name:    "prepended X-Forwarded-For entries are skipped",
+			remote:  "10.0.0.2:5000",
+			header:  "X-Forwarded-For",
+			headers: map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.9"}},
+			want:    "198.51.100.9",
+		},
+		{
+			name:   "client Forwarded header does not beat X-Forwarded-For",
+			remote: "10.0.0.2:5000",
+			header: "X-Forwarded-For",
+			headers: map[string][]string{
+				"Forwarded":       {"for=1.2.3.4"},
+				"X-Forwarded-For": {"198.51.100.9"},
+			},
+			want: "198.51.100.9",
+		},
+		{
+			name:    "client X-Real-IP is ignored when the proxy sets X-Forwarded-For",
+			remote:  "10.0.0.2:5000",
+			header:  "X-Forwarded-For",
+			headers: map[string][]string{"X-Real-IP": {"1.2.3.4"}},
+			want:    "10.0.0.2",
+		},
+		{
+			name:   "client X-Forwarded-For is ignored when the proxy sets Forwarded",
+			remote: "10.0.0.2:5000",
+			header: "Forwarded",
+			headers: map[string][]string{
+				"Forwarded":       {`for="[2001:db8::1]:4711"`},
+				"X-Forwarded-For": {"1.2.3.4"},
+			},
+			want: "2001:db8::1",
+		},
+		{
+			name:    "X-Real-IP set by the proxy",
+			remote:  "10.0.0.2:5000",
+			header:  "X-Real-IP",
+			headers: map[string][]string{"X-Real-IP": {"1.2.3.4", "198.51.100.9"}, "X-Forwarded-For": {"5.6.7.8"}},
+			want:    "198.51.100.9",
+		},
+		{
+			name:    "unparseable hop stops the walk",
+			remote:  "10.0.0.2:5000",
+			header:  "X-Forwarded-For",
+			headers: map[string][]string{"X-Forwarded-For": {"1.2.3.4, unknown, 10.0.0.3"}},
+			want:    "10.0.0.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for key, values := range tt.headers {
+				for _, v := range values {
+					r.Header.Add(key, v)
+				}
+			}
+			if got := ResolveClientIP(r, trusted, tt.header); got != tt.want {
+				t.Errorf("ResolveClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
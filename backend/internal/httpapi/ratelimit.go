@@ -1,6 +1,7 @@
 package httpapi
 
 import (
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,7 +9,8 @@ import (
 	"time"
 
 	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
-	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/metrics"
 	"golang.org/x/time/rate"
 )
 
@@ -29,7 +31,7 @@ type LimiterEntry struct {
 
 // RateLimiter handles rate limiting using Redis
 type RateLimiter struct {
-	logger contracts.Logger
+	logger logger.Logger
 	config map[string]RateLimitConfig
 
 	limiters map[string]*LimiterEntry
@@ -40,9 +42,9 @@ type RateLimiter struct {
 }
 
 // NewRateLimiter creates a new rate limiter instance
-func NewRateLimiter(logger contracts.Logger) *RateLimiter {
+func NewRateLimiter(log logger.Logger) *RateLimiter {
 	rl := &RateLimiter{
-		logger:   logger,
+		logger:   log,
 		limiters: make(map[string]*LimiterEntry),
 		config: map[string]RateLimitConfig{
 			"POST:/api/v1/auth/register": {
@@ -54,6 +56,12 @@ func NewRateLimiter(logger contracts.Logger) *RateLimiter {
 			"POST:/api/v1/auth/refresh": {
 				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "ip",
 			},
+			"POST:/api/v1/auth/unlock/request": {
+				Limit: 5, Window: 1 * time.Hour, Burst: 2, Strategy: "ip",
+			},
+			"POST:/api/v1/auth/unlock": {
+				Limit: 10, Window: 15 * time.Minute, Burst: 3, Strategy: "ip",
+			},
 			"POST:/api/v1/auth/logout": {
 				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
 			},
@@ -73,6 +81,13 @@ func NewRateLimiter(logger contracts.Logger) *RateLimiter {
 			"POST:/api/v1/meetings": {
 				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
 			},
+			// Imports may fetch a remote URL and create many meetings
+			"POST:/api/v1/meetings/import": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/from-template/{templateID}": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
 			"PATCH:/api/v1/meetings/{meetingID}": {
 				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
 			},
@@ -85,6 +100,172 @@ func NewRateLimiter(logger contracts.Logger) *RateLimiter {
 			"POST:/api/v1/meetings/{meetingID}/join": {
 				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
 			},
+			"GET:/api/v1/meetings/{meetingID}/calendar.ics": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"GET:/api/v1/meetings/{meetingID}/agenda": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/{meetingID}/agenda": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/agenda/order": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/agenda/{itemID}": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"DELETE:/api/v1/meetings/{meetingID}/agenda/{itemID}": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/agenda/{itemID}/progress": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"GET:/api/v1/meetings/{meetingID}/resources": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/{meetingID}/resources": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/resources/{resourceID}": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"DELETE:/api/v1/meetings/{meetingID}/resources/{resourceID}": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"GET:/api/v1/meetings/{meetingID}/notes": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			// Clients may autosave notes while typing
+			"PUT:/api/v1/meetings/{meetingID}/notes": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			"DELETE:/api/v1/meetings/{meetingID}/notes": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
+			"GET:/api/v1/meetings/{meetingID}/access": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/access": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/{meetingID}/guest-link": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			"DELETE:/api/v1/meetings/{meetingID}/guest-link": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			// Hosts poll the guest list while people wait
+			"GET:/api/v1/meetings/{meetingID}/guests": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/guests/{guestID}": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/{meetingID}/leave": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			// Event streams reconnect about once a minute, more often on
+			// flaky networks
+			"GET:/api/v1/meetings/{meetingID}/events": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/{meetingID}/end": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
+			"POST:/api/v1/meetings/{meetingID}/transfer": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			"GET:/api/v1/meetings/{meetingID}/permissions": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/participants/{userID}/role": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"GET:/api/v1/meetings/{meetingID}/lobby": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			// Waiting participants poll for the host's decision
+			"GET:/api/v1/meetings/{meetingID}/lobby/status": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			"PUT:/api/v1/meetings/{meetingID}/lobby/{userID}": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			// Guests have no account, so their endpoints are limited per IP
+			"GET:/api/v1/guest/{linkToken}": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "ip",
+			},
+			"POST:/api/v1/guest/{linkToken}/join": {
+				Limit: 10, Window: 15 * time.Minute, Burst: 3, Strategy: "ip",
+			},
+			"GET:/api/v1/guest/{linkToken}/status": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "ip",
+			},
+			"POST:/api/v1/calendar/feed": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			"DELETE:/api/v1/calendar/feed": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			// Feed tokens are unguessable; this only caps polling and enumeration
+			"GET:/api/v1/calendar/feed/{token}": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "ip",
+			},
+			"GET:/api/v1/notifications": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			"PATCH:/api/v1/notifications/{notificationID}": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 20, Strategy: "user",
+			},
+			"POST:/api/v1/notifications/read-all": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"GET:/api/v1/notifications/preferences": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"PUT:/api/v1/notifications/preferences": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"GET:/api/v1/notifications/webhook": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"PUT:/api/v1/notifications/webhook": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			"DELETE:/api/v1/notifications/webhook": {
+				Limit: 5, Window: 1 * time.Minute, Burst: 2, Strategy: "user",
+			},
+			"GET:/api/v1/personas": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"POST:/api/v1/personas": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
+			"GET:/api/v1/personas/{personaID}": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"PUT:/api/v1/personas/{personaID}": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"DELETE:/api/v1/personas/{personaID}": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
+			"GET:/api/v1/templates": {
+				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
+			},
+			"POST:/api/v1/templates": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
+			"GET:/api/v1/templates/{templateID}": {
+				Limit: 60, Window: 1 * time.Minute, Burst: 15, Strategy: "user",
+			},
+			"PUT:/api/v1/templates/{templateID}": {
+				Limit: 20, Window: 1 * time.Minute, Burst: 5, Strategy: "user",
+			},
+			"DELETE:/api/v1/templates/{templateID}": {
+				Limit: 10, Window: 1 * time.Minute, Burst: 3, Strategy: "user",
+			},
 			"GET:/api/v1/history": {
 				Limit: 30, Window: 1 * time.Minute, Burst: 10, Strategy: "user",
 			},
@@ -207,13 +388,14 @@ func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 			limiter := rl.getLimiter(limiterkey, config)
 
 			if !limiter.Allow() {
+				metrics.RateLimitRejections.WithLabelValues(endpointKey, config.Strategy).Inc()
 				w.Header().Set("Retry-After", strconv.FormatInt(int64(config.Window.Seconds()), 10))
 				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(config.Limit))
 				w.Header().Set("X-RateLimit-Remaining", "0")
 				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(config.Window).Unix(), 10))
 
 				w.WriteHeader(http.StatusTooManyRequests)
-				w.Write([]byte(`{"message":"rate limit exceeded"}`))
+				w.Write([]byte(`{"message":"rate limit exceeded","code":"rate_limited"}`))
 				return
 			}
 
@@ -249,29 +431,19 @@ func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 	}
 }
 
-// getClientIP extracts the client IP from the request
-// Handles proxies and load balancers
+// getClientIP returns the client IP resolved by the ClientIP middleware.
+// Forwarding headers are never read here; they are only trusted when the
+// request came through a configured proxy (see middleware.ClientIP).
 func (rl *RateLimiter) getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (from proxy/load balancer)
-	// Note: In production, you should only trust this from your own proxy
-	if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
-		// X-Forwarded-For can contain multiple IPs, take the first one
-		ips := strings.Split(ip, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// Check X-Real-IP header (alternative proxy header)
-	if ip := r.Header.Get("X-Real-IP"); ip != "" {
-		return strings.TrimSpace(ip)
+	if ip := httpapicontext.ClientIPFromContext(r.Context()); ip != "" {
+		return ip
 	}
 
 	// Fallback to RemoteAddr
 	// Remove port if present (e.g., "192.168.1.1:12345" -> "192.168.1.1")
 	addr := r.RemoteAddr
-	if idx := strings.LastIndex(addr, ":"); idx != -1 {
-		addr = addr[:idx]
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
 	}
 	return addr
 }
@@ -283,10 +455,79 @@ func (rl *RateLimiter) normalizePath(method, path string) string {
 	// In a more sophisticated implementation, you'd match against Chi route patterns
 
 	// Check for common patterns
+	if path == "/api/v1/meetings/import" {
+		return method + ":" + path
+	}
+	if strings.HasPrefix(path, "/api/v1/meetings/from-template/") {
+		// Matches /api/v1/meetings/from-template/{templateID}
+		return method + ":/api/v1/meetings/from-template/{templateID}"
+	}
 	if strings.Contains(path, "/meetings/") && len(strings.Split(path, "/")) == 5 {
 		// Matches /api/v1/meetings/{id}
 		return method + ":/api/v1/meetings/{meetingID}"
 	}
+	if parts := strings.Split(path, "/"); strings.Contains(path, "/meetings/") && len(parts) == 6 {
+		// Matches /api/v1/meetings/{id}/{action}
+		return method + ":/api/v1/meetings/{meetingID}/" + parts[5]
+	}
+	if parts := strings.Split(path, "/"); strings.Contains(path, "/meetings/") && len(parts) >= 7 && parts[5] == "agenda" {
+		// Matches /api/v1/meetings/{id}/agenda/order, .../agenda/{itemID} and .../agenda/{itemID}/progress
+		switch {
+		case len(parts) == 7 && parts[6] == "order":
+			return method + ":/api/v1/meetings/{meetingID}/agenda/order"
+		case len(parts) == 7:
+			return method + ":/api/v1/meetings/{meetingID}/agenda/{itemID}"
+		case len(parts) == 8:
+			return method + ":/api/v1/meetings/{meetingID}/agenda/{itemID}/" + parts[7]
+		}
+	}
+	if parts := strings.Split(path, "/"); strings.Contains(path, "/meetings/") && len(parts) == 7 && parts[5] == "resources" {
+		// Matches /api/v1/meetings/{id}/resources/{resourceID}
+		return method + ":/api/v1/meetings/{meetingID}/resources/{resourceID}"
+	}
+	if parts := strings.Split(path, "/"); strings.Contains(path, "/meetings/") && len(parts) == 8 && parts[5] == "participants" {
+		// Matches /api/v1/meetings/{id}/participants/{userID}/role
+		return method + ":/api/v1/meetings/{meetingID}/participants/{userID}/" + parts[7]
+	}
+	if parts := strings.Split(path, "/"); strings.Contains(path, "/meetings/") && len(parts) == 7 && parts[5] == "lobby" {
+		// Matches /api/v1/meetings/{id}/lobby/status and /api/v1/meetings/{id}/lobby/{userID}
+		if parts[6] == "status" {
+			return method + ":/api/v1/meetings/{meetingID}/lobby/status"
+		}
+		return method + ":/api/v1/meetings/{meetingID}/lobby/{userID}"
+	}
+	if parts := strings.Split(path, "/"); strings.Contains(path, "/meetings/") && len(parts) == 7 && parts[5] == "guests" {
+		// Matches /api/v1/meetings/{id}/guests/{guestID}
+		return method + ":/api/v1/meetings/{meetingID}/guests/{guestID}"
+	}
+	if parts := strings.Split(path, "/"); strings.HasPrefix(path, "/api/v1/guest/") && len(parts) >= 5 {
+		// Matches /api/v1/guest/{linkToken} and /api/v1/guest/{linkToken}/{action}
+		if len(parts) == 6 {
+			return method + ":/api/v1/guest/{linkToken}/" + parts[5]
+		}
+		return method + ":/api/v1/guest/{linkToken}"
+	}
+	if strings.HasPrefix(path, "/api/v1/calendar/feed/") {
+		// Matches /api/v1/calendar/feed/{token}.ics
+		return method + ":/api/v1/calendar/feed/{token}"
+	}
+	if parts := strings.Split(path, "/"); strings.HasPrefix(path, "/api/v1/notifications/") && len(parts) == 5 {
+		// Matches /api/v1/notifications/{id}; read-all, preferences and
+		// webhook are exact paths
+		switch parts[4] {
+		case "read-all", "preferences", "webhook":
+			return method + ":" + path
+		}
+		return method + ":/api/v1/notifications/{notificationID}"
+	}
+	if strings.Contains(path, "/personas/") && len(strings.Split(path, "/")) == 5 {
+		// Matches /api/v1/personas/{id}
+		return method + ":/api/v1/personas/{personaID}"
+	}
+	if strings.Contains(path, "/templates/") && len(strings.Split(path, "/")) == 5 {
+		// Matches /api/v1/templates/{id}
+		return method + ":/api/v1/templates/{templateID}"
+	}
 	if strings.Contains(path, "/history/") && len(strings.Split(path, "/")) == 5 {
 		// Matches /api/v1/history/{id}
 		return method + ":/api/v1/history/{transcriptID}"
@@ -1,33 +1,84 @@
 package response
 
 import (
+	"errors"
 	"net/http"
-	"strings"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+)
+
+// Stable error codes for responses that are not produced by a domain error
+const (
+	CodeBadRequest         = "bad_request"
+	CodeInvalidRequest     = "invalid_request"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeRateLimited        = "rate_limited"
+	CodeValidationFailed   = "validation_failed"
+	CodeInternal           = "internal_error"
+	CodeServiceUnavailable = "service_unavailable"
 )
 
-// StatusFromError determines the appropriate HTTP status code from an error message
-func StatusFromError(err error) int {
-	if err == nil {
-		return http.StatusOK
+// FromError maps a service error to an HTTP status and response body.
+// Domain errors keep their message and code; anything else is an internal
+// error whose details must not reach the client.
+func FromError(err error) (int, ErrorResponse) {
+	var verr *services.ValidationError
+	if errors.As(err, &verr) {
+		return http.StatusBadRequest, ErrorResponse{
+			Message: verr.Error(),
+			Code:    CodeValidationFailed,
+			Fields:  verr.Fields,
+		}
 	}
 
-	lower := strings.ToLower(err.Error())
+	var derr *services.Error
+	if errors.As(err, &derr) {
+		return statusForKind(derr.Kind), ErrorResponse{Message: derr.Message, Code: derr.Code}
+	}
+
+	return http.StatusInternalServerError, ErrorResponse{Message: "internal server error", Code: CodeInternal}
+}
+
+func statusForKind(kind error) int {
 	switch {
-	case strings.Contains(lower, "credentials"), strings.Contains(lower, "unauthorized"):
-		return http.StatusUnauthorized
-	case strings.Contains(lower, "refresh token"):
+	case errors.Is(kind, services.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(kind, services.ErrUnauthorized):
 		return http.StatusUnauthorized
-	case strings.Contains(lower, "not found"):
+	case errors.Is(kind, services.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(kind, services.ErrNotFound):
 		return http.StatusNotFound
-	case strings.Contains(lower, "conflict"),
-		strings.Contains(lower, "already"):
+	case errors.Is(kind, services.ErrConflict):
 		return http.StatusConflict
-	case strings.Contains(lower, "required"),
-		strings.Contains(lower, "invalid"),
-		strings.Contains(lower, "must"):
-		return http.StatusBadRequest
+	case errors.Is(kind, services.ErrUnavailable):
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
+// codeForStatus is the default code for errors written without an explicit one
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternal
+	}
+}
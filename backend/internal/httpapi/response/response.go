@@ -3,6 +3,8 @@ package response
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
 )
 
 // JSON writes a JSON response with the given status code and payload
@@ -15,13 +17,19 @@ func JSON(w http.ResponseWriter, status int, payload any) {
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
-// Error writes a JSON error response
+// Error writes a JSON error response with the default code for the status
 func Error(w http.ResponseWriter, status int, msg string) {
-	JSON(w, status, ErrorResponse{Message: msg})
+	ErrorCode(w, status, codeForStatus(status), msg)
+}
+
+// ErrorCode writes a JSON error response with an explicit machine-readable code
+func ErrorCode(w http.ResponseWriter, status int, code, msg string) {
+	JSON(w, status, ErrorResponse{Message: msg, Code: code})
 }
 
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
-	Message string `json:"message"`
+	Message string                `json:"message"`
+	Code    string                `json:"code"`
+	Fields  []services.FieldError `json:"fields,omitempty"`
 }
-
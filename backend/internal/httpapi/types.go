@@ -1,6 +1,9 @@
 package httpapi
 
-import "github.com/aicomp/ai-virtual-chat/backend/internal/core"
+import (
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+)
 
 type (
 	UserProfile             = core.UserProfile
@@ -48,11 +51,13 @@ type (
 	AuthRefreshRequest       = core.AuthRefreshRequest
 	AuthRefreshResponse      = core.AuthRefreshResponse
 	AuthLogoutResponse       = core.AuthLogoutResponse
+	AuthCSRFResponse         = core.AuthCSRFResponse
+	AuthUnlockRequest        = core.AuthUnlockRequest
+	AuthUnlockConfirmRequest = core.AuthUnlockConfirmRequest
+	AuthUnlockResponse       = core.AuthUnlockResponse
 	MeetingInvite            = core.MeetingInvite
 	CreateMeetingInvitesRequest = core.CreateMeetingInvitesRequest
 	MeetingInvitesResponse   = core.MeetingInvitesResponse
 )
 
-type APIError struct {
-	Message string `json:"message"`
-}
+type APIError = response.ErrorResponse
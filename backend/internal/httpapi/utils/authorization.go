@@ -5,22 +5,6 @@ import (
 	"fmt"
 )
 
-// CheckMeetingOwnership verifies that a user owns a meeting
-// Returns the host user ID and an error if not owned
-func CheckMeetingOwnership(hostUserID, requestingUserID string) error {
-	if hostUserID == "" {
-		// If no host is set, allow access (legacy meetings)
-		return nil
-	}
-	if requestingUserID == "" {
-		return errors.New("user ID is required")
-	}
-	if hostUserID != requestingUserID {
-		return fmt.Errorf("unauthorized: user does not own this meeting")
-	}
-	return nil
-}
-
 // CheckPresetOwnership verifies that a user owns a voice preset
 func CheckPresetOwnership(presetUserID, requestingUserID string) error {
 	if presetUserID == "" {
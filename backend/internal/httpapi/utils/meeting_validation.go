@@ -9,11 +9,17 @@ import (
 // IsMeetingActionable checks if a meeting can have actions performed on it
 // Returns true if meeting is actionable, false if it's ended or past
 func IsMeetingActionable(status string, startTime time.Time, durationMinutes int) bool {
-	// If meeting is explicitly ended, it's not actionable
-	if status == "ended" {
+	// Meetings that ended, were missed or expired are not actionable
+	switch status {
+	case "ended", "missed", "expired":
 		return false
 	}
 
+	// A recurring meeting's series stays editable after its first occurrence
+	if status == "series" {
+		return true
+	}
+
 	// Calculate meeting end time
 	endTime := startTime.Add(time.Duration(durationMinutes) * time.Minute)
 	now := time.Now()
@@ -30,8 +36,13 @@ func IsMeetingActionable(status string, startTime time.Time, durationMinutes int
 func ValidateMeetingAction(status string, startTime time.Time, durationMinutes int) error {
 	if !IsMeetingActionable(status, startTime, durationMinutes) {
 		endTime := startTime.Add(time.Duration(durationMinutes) * time.Minute)
-		if status == "ended" {
+		switch status {
+		case "ended":
 			return errors.New("meeting has ended")
+		case "missed":
+			return errors.New("meeting was never started")
+		case "expired":
+			return errors.New("meeting has expired")
 		}
 		if time.Now().After(endTime) {
 			return fmt.Errorf("meeting finished at %s", endTime.Format(time.RFC3339))
@@ -8,17 +8,42 @@ import (
 func (api *API) Routes() chi.Router {
 	r := chi.NewRouter()
 
-	// Public auth routes
-	r.Post("/auth/register", handlers.HandleRegister(api))
-	r.Post("/auth/login", handlers.HandleLogin(api))
-	r.Post("/auth/refresh", handlers.HandleRefresh(api))
+	// Public auth routes. They are exempt from CSRF tokens (no session exists
+	// yet, or only the refresh cookie is used) and instead reject browser
+	// requests from untrusted origins.
+	r.Group(func(pub chi.Router) {
+		pub.Use(api.TrustedOriginMiddleware)
 
-	// Protected routes (require authentication)
+		pub.Post("/auth/register", handlers.HandleRegister(api))
+		pub.Post("/auth/login", handlers.HandleLogin(api))
+		pub.Post("/auth/refresh", handlers.HandleRefresh(api))
+		pub.Post("/auth/unlock/request", handlers.HandleRequestUnlock(api))
+		pub.Post("/auth/unlock", handlers.HandleConfirmUnlock(api))
+	})
+
+	// Guest links of public meetings. Guests have no session; the link token
+	// in the path, and the guest token once issued, authenticate them.
+	r.Group(func(pub chi.Router) {
+		pub.Use(api.TrustedOriginMiddleware)
+
+		pub.Get("/guest/{linkToken}", handlers.HandleGetGuestLink(api))
+		pub.Post("/guest/{linkToken}/join", handlers.HandleGuestJoin(api))
+		pub.Get("/guest/{linkToken}/status", handlers.HandleGuestStatus(api))
+	})
+
+	// Calendar feed subscriptions. Calendar clients send neither cookies nor an
+	// Origin; the secret token in the path authenticates the request.
+	r.Get("/calendar/feed/{token}.ics", handlers.HandleCalendarFeed(api))
+
+	// Protected routes (require authentication). Every POST/PUT/PATCH/DELETE
+	// must send the X-CSRF-Token issued by GET /auth/csrf.
 	r.Group(func(pr chi.Router) {
 		pr.Use(api.AuthMiddleware)
+		pr.Use(api.CSRFMiddleware)
 
 		// Auth
 		pr.Get("/auth/session", handlers.HandleGetSession(api))
+		pr.Get("/auth/csrf", handlers.HandleGetCSRFToken(api))
 		pr.Post("/auth/logout", handlers.HandleLogout(api))
 
 		// Dashboard
@@ -28,6 +53,8 @@ func (api *API) Routes() chi.Router {
 		pr.Route("/meetings", func(r chi.Router) {
 			r.Get("/", handlers.HandleListMeetings(api))
 			r.Post("/", handlers.HandleCreateMeeting(api))
+			r.Post("/import", handlers.HandleImportCalendar(api))
+			r.Post("/from-template/{templateID}", handlers.HandleCreateMeetingFromTemplate(api))
 
 			r.Route("/{meetingID}", func(r chi.Router) {
 				r.Get("/", handlers.HandleGetMeeting(api))
@@ -35,6 +62,73 @@ func (api *API) Routes() chi.Router {
 				r.Delete("/", handlers.HandleDeleteMeeting(api))
 				r.Post("/start", handlers.HandleStartMeeting(api))
 				r.Post("/join", handlers.HandleJoinMeeting(api))
+				r.Post("/leave", handlers.HandleLeaveMeeting(api))
+				r.Get("/events", handlers.HandleMeetingEvents(api))
+				r.Post("/end", handlers.HandleEndMeeting(api))
+				r.Post("/transfer", handlers.HandleTransferMeeting(api))
+				r.Get("/permissions", handlers.HandleGetMeetingPermissions(api))
+				r.Put("/participants/{userID}/role", handlers.HandleUpdateParticipantRole(api))
+				r.Get("/calendar.ics", handlers.HandleGetMeetingCalendar(api))
+				r.Get("/agenda", handlers.HandleGetMeetingAgenda(api))
+				r.Post("/agenda", handlers.HandleCreateAgendaItem(api))
+				r.Put("/agenda/order", handlers.HandleReorderAgenda(api))
+				r.Put("/agenda/{itemID}", handlers.HandleUpdateAgendaItem(api))
+				r.Delete("/agenda/{itemID}", handlers.HandleDeleteAgendaItem(api))
+				r.Put("/agenda/{itemID}/progress", handlers.HandleUpdateAgendaProgress(api))
+				r.Get("/resources", handlers.HandleListMeetingResources(api))
+				r.Post("/resources", handlers.HandleCreateMeetingResource(api))
+				r.Put("/resources/{resourceID}", handlers.HandleUpdateMeetingResource(api))
+				r.Delete("/resources/{resourceID}", handlers.HandleDeleteMeetingResource(api))
+				r.Get("/notes", handlers.HandleGetMeetingNotes(api))
+				r.Put("/notes", handlers.HandleUpdateMeetingNotes(api))
+				r.Delete("/notes", handlers.HandleDeleteMeetingNotes(api))
+				r.Get("/access", handlers.HandleGetMeetingAccess(api))
+				r.Put("/access", handlers.HandleUpdateMeetingAccess(api))
+				r.Post("/guest-link", handlers.HandleRotateGuestLink(api))
+				r.Delete("/guest-link", handlers.HandleRevokeGuestLink(api))
+				r.Get("/guests", handlers.HandleListMeetingGuests(api))
+				r.Put("/guests/{guestID}", handlers.HandleUpdateMeetingGuest(api))
+				r.Get("/lobby", handlers.HandleGetMeetingLobby(api))
+				r.Get("/lobby/status", handlers.HandleGetLobbyStatus(api))
+				r.Put("/lobby/{userID}", handlers.HandleUpdateLobbyEntry(api))
+			})
+		})
+
+		// Calendar feed
+		pr.Post("/calendar/feed", handlers.HandleRotateCalendarFeed(api))
+		pr.Delete("/calendar/feed", handlers.HandleRevokeCalendarFeed(api))
+
+		// Notifications
+		pr.Route("/notifications", func(r chi.Router) {
+			r.Get("/", handlers.HandleListNotifications(api))
+			r.Post("/read-all", handlers.HandleReadAllNotifications(api))
+			r.Get("/preferences", handlers.HandleGetNotificationPreferences(api))
+			r.Put("/preferences", handlers.HandleUpdateNotificationPreferences(api))
+			r.Get("/webhook", handlers.HandleGetNotificationWebhook(api))
+			r.Put("/webhook", handlers.HandleUpdateNotificationWebhook(api))
+			r.Delete("/webhook", handlers.HandleDeleteNotificationWebhook(api))
+			r.Patch("/{notificationID}", handlers.HandleUpdateNotification(api))
+		})
+
+		// AI personas
+		pr.Route("/personas", func(r chi.Router) {
+			r.Get("/", handlers.HandleListPersonas(api))
+			r.Post("/", handlers.HandleCreatePersona(api))
+			r.Route("/{personaID}", func(r chi.Router) {
+				r.Get("/", handlers.HandleGetPersona(api))
+				r.Put("/", handlers.HandleUpdatePersona(api))
+				r.Delete("/", handlers.HandleDeletePersona(api))
+			})
+		})
+
+		// Meeting templates
+		pr.Route("/templates", func(r chi.Router) {
+			r.Get("/", handlers.HandleListTemplates(api))
+			r.Post("/", handlers.HandleCreateTemplate(api))
+			r.Route("/{templateID}", func(r chi.Router) {
+				r.Get("/", handlers.HandleGetTemplate(api))
+				r.Put("/", handlers.HandleUpdateTemplate(api))
+				r.Delete("/", handlers.HandleDeleteTemplate(api))
 			})
 		})
 
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+)
+
+// checkMeetingAccess applies the meeting access rules and writes the error
+// response when they fail. An empty permission asks for read access: anyone
+// may read public meetings, only participants with a role private ones.
+// Otherwise the caller's role must grant the permission. Whether a meeting
+// that has ended can still change is up to the caller.
+func checkMeetingAccess(api contracts.V1APIInterface, w http.ResponseWriter, r *http.Request, meetingID string, permission string) (*core.MeetingDetail, bool) {
+	userID := httpapicontext.UserIDFromContext(r.Context())
+
+	detail, err := api.Service().GetMeeting(r.Context(), meetingID)
+	if err != nil {
+		api.RespondServiceError(w, r, err)
+		return nil, false
+	}
+	if permission == "" && detail.Summary.Visibility != services.MeetingVisibilityPrivate {
+		return detail, true
+	}
+
+	role, err := api.Service().MeetingRole(r.Context(), meetingID, userID)
+	if err != nil {
+		api.RespondServiceError(w, r, err)
+		return nil, false
+	}
+	if permission == "" {
+		if role == "" {
+			response.Error(w, http.StatusForbidden, "unauthorized: you do not have access to this meeting")
+			return nil, false
+		}
+	} else if !services.RoleAllows(role, permission) {
+		response.Error(w, http.StatusForbidden, "unauthorized: your role in this meeting does not allow this action")
+		return nil, false
+	}
+	return detail, true
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleGetMeetingAgenda handles GET /api/v1/meetings/{meetingID}/agenda
+func HandleGetMeetingAgenda(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, ""); !ok {
+			return
+		}
+
+		agenda, err := api.Service().GetMeetingAgenda(r.Context(), meetingID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, agenda)
+	}
+}
+
+// HandleCreateAgendaItem handles POST /api/v1/meetings/{meetingID}/agenda
+func HandleCreateAgendaItem(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.CreateAgendaItemRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		existing, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit)
+		if !ok {
+			return
+		}
+		// Validate meeting action (ended/past meetings cannot be updated)
+		if err := utils.ValidateMeetingAction(existing.Summary.Status, existing.Summary.StartTime, existing.Summary.DurationMinutes); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, "meeting_not_actionable", err.Error())
+			return
+		}
+
+		item, err := api.Service().AddAgendaItem(r.Context(), meetingID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, item)
+	}
+}
+
+// HandleReorderAgenda handles PUT /api/v1/meetings/{meetingID}/agenda/order
+func HandleReorderAgenda(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.ReorderAgendaRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		existing, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit)
+		if !ok {
+			return
+		}
+		// Validate meeting action (ended/past meetings cannot be updated)
+		if err := utils.ValidateMeetingAction(existing.Summary.Status, existing.Summary.StartTime, existing.Summary.DurationMinutes); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, "meeting_not_actionable", err.Error())
+			return
+		}
+
+		agenda, err := api.Service().ReorderAgenda(r.Context(), meetingID, req.ItemIDs)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, agenda)
+	}
+}
+
+// HandleUpdateAgendaItem handles PUT /api/v1/meetings/{meetingID}/agenda/{itemID}
+func HandleUpdateAgendaItem(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		itemID := chi.URLParam(r, "itemID")
+		if err := utils.ValidateID(itemID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateAgendaItemRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		existing, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit)
+		if !ok {
+			return
+		}
+		// Validate meeting action (ended/past meetings cannot be updated)
+		if err := utils.ValidateMeetingAction(existing.Summary.Status, existing.Summary.StartTime, existing.Summary.DurationMinutes); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, "meeting_not_actionable", err.Error())
+			return
+		}
+
+		item, err := api.Service().UpdateAgendaItem(r.Context(), meetingID, itemID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, item)
+	}
+}
+
+// HandleDeleteAgendaItem handles DELETE /api/v1/meetings/{meetingID}/agenda/{itemID}
+func HandleDeleteAgendaItem(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		itemID := chi.URLParam(r, "itemID")
+		if err := utils.ValidateID(itemID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		existing, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit)
+		if !ok {
+			return
+		}
+		// Validate meeting action (ended/past meetings cannot be updated)
+		if err := utils.ValidateMeetingAction(existing.Summary.Status, existing.Summary.StartTime, existing.Summary.DurationMinutes); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, "meeting_not_actionable", err.Error())
+			return
+		}
+
+		if err := api.Service().DeleteAgendaItem(r.Context(), meetingID, itemID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// HandleUpdateAgendaProgress handles PUT /api/v1/meetings/{meetingID}/agenda/{itemID}/progress
+// The host marks items current or completed while the meeting is active; the
+// service rejects changes at other times.
+func HandleUpdateAgendaProgress(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		itemID := chi.URLParam(r, "itemID")
+		if err := utils.ValidateID(itemID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.AgendaProgressRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit); !ok {
+			return
+		}
+
+		agenda, err := api.Service().UpdateAgendaProgress(r.Context(), meetingID, itemID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, agenda)
+	}
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
@@ -9,6 +12,7 @@ import (
 	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
 )
 
 // HandleLogin handles POST /api/v1/auth/login
@@ -16,7 +20,7 @@ func HandleLogin(api contracts.V1APIInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req core.AuthLoginRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -25,17 +29,27 @@ func HandleLogin(api contracts.V1APIInterface) http.HandlerFunc {
 		}
 
 		if strings.TrimSpace(req.Email) == "" {
-			response.Error(w, http.StatusBadRequest, "email is required")
+			api.RespondServiceError(w, r, services.InvalidField("email", "email is required"))
 			return
 		}
 		if strings.TrimSpace(req.Password) == "" {
-			response.Error(w, http.StatusBadRequest, "password is required")
+			api.RespondServiceError(w, r, services.InvalidField("password", "password is required"))
 			return
 		}
 
 		session, err := api.Service().AuthenticateUser(r.Context(), req.Email, req.Password)
 		if err != nil {
-			response.Error(w, http.StatusUnauthorized, "invalid credentials")
+			var locked *services.LoginLockedError
+			if errors.As(err, &locked) {
+				// Same response whether or not the account exists
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+				response.JSON(w, http.StatusTooManyRequests, map[string]any{
+					"message": "too many failed login attempts, try again later",
+					"code":    "login_locked",
+				})
+				return
+			}
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -47,7 +61,7 @@ func HandleLogin(api contracts.V1APIInterface) http.HandlerFunc {
 
 		accessToken, _, err := api.SignAccessToken(session.User.ID, sessionID)
 		if err != nil {
-			api.Logger().Printf("jwt signing error: %v", err)
+			api.Logger().ErrorContext(r.Context(), "jwt signing error", "error", err)
 			response.Error(w, http.StatusInternalServerError, "failed to issue access token")
 			return
 		}
@@ -67,7 +81,7 @@ func HandleRegister(api contracts.V1APIInterface) http.HandlerFunc {
 
 		var req core.AuthRegisterRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -77,7 +91,7 @@ func HandleRegister(api contracts.V1APIInterface) http.HandlerFunc {
 
 		session, err := api.Service().RegisterUser(r.Context(), req)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -89,7 +103,7 @@ func HandleRegister(api contracts.V1APIInterface) http.HandlerFunc {
 
 		accessToken, _, err := api.SignAccessToken(session.User.ID, sessionID)
 		if err != nil {
-			api.Logger().Printf("jwt signing error: %v", err)
+			api.Logger().ErrorContext(r.Context(), "jwt signing error", "error", err)
 			response.Error(w, http.StatusInternalServerError, "failed to issue access token")
 			return
 		}
@@ -111,7 +125,7 @@ func HandleRefresh(api contracts.V1APIInterface) http.HandlerFunc {
 			return
 		}
 
-		cookie, err := r.Cookie("nl_refresh")
+		cookie, err := r.Cookie(api.Cfg().RefreshCookieName)
 		if err != nil {
 			response.Error(w, http.StatusUnauthorized, "refresh token required")
 			return
@@ -125,13 +139,13 @@ func HandleRefresh(api contracts.V1APIInterface) http.HandlerFunc {
 
 		sessionID, newRefreshToken, newExpiresAt, userID, err := api.Service().RefreshSession(r.Context(), refreshToken)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
 		accessToken, _, err := api.SignAccessToken(userID, sessionID)
 		if err != nil {
-			api.Logger().Printf("jwt signing error: %v", err)
+			api.Logger().ErrorContext(r.Context(), "jwt signing error", "error", err)
 			response.Error(w, http.StatusInternalServerError, "failed to issue access token")
 			return
 		}
@@ -141,7 +155,7 @@ func HandleRefresh(api contracts.V1APIInterface) http.HandlerFunc {
 
 		session, err := api.Service().GetAuthSession(r.Context(), userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -151,6 +165,57 @@ func HandleRefresh(api contracts.V1APIInterface) http.HandlerFunc {
 	}
 }
 
+// HandleRequestUnlock handles POST /api/v1/auth/unlock/request
+func HandleRequestUnlock(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req core.AuthUnlockRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if strings.TrimSpace(req.Email) == "" {
+			api.RespondServiceError(w, r, services.InvalidField("email", "email is required"))
+			return
+		}
+
+		if err := api.Service().RequestAccountUnlock(r.Context(), req.Email); err != nil {
+			// Do not surface failures: the response must not reveal whether the account exists
+			api.Logger().ErrorContext(r.Context(), "unlock request service error", "error", err)
+		}
+
+		response.JSON(w, http.StatusAccepted, core.AuthUnlockResponse{
+			Message: "if the account is locked, an unlock link has been sent",
+		})
+	}
+}
+
+// HandleConfirmUnlock handles POST /api/v1/auth/unlock
+func HandleConfirmUnlock(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req core.AuthUnlockConfirmRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if err := api.Service().ConfirmAccountUnlock(r.Context(), req.Token); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, core.AuthUnlockResponse{Message: "account unlocked"})
+	}
+}
+
 // HandleLogout handles POST /api/v1/auth/logout
 func HandleLogout(api contracts.V1APIInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -165,7 +230,7 @@ func HandleLogout(api contracts.V1APIInterface) http.HandlerFunc {
 		}
 
 		if err := api.Service().RevokeSession(r.Context(), sessionID); err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -175,6 +240,20 @@ func HandleLogout(api contracts.V1APIInterface) http.HandlerFunc {
 	}
 }
 
+// HandleGetCSRFToken handles GET /api/v1/auth/csrf
+func HandleGetCSRFToken(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sessionID := httpapicontext.SessionIDFromContext(r.Context())
+		if sessionID == "" {
+			response.Error(w, http.StatusUnauthorized, "invalid session")
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		response.JSON(w, http.StatusOK, core.AuthCSRFResponse{CSRFToken: api.CSRFToken(sessionID)})
+	}
+}
+
 // HandleGetSession handles GET /api/v1/auth/session
 func HandleGetSession(api contracts.V1APIInterface) http.HandlerFunc {
 
@@ -188,7 +267,7 @@ func HandleGetSession(api contracts.V1APIInterface) http.HandlerFunc {
 
 		session, err := api.Service().GetAuthSession(r.Context(), userID)
 		if err != nil {
-			api.Logger().Printf("auth session service error: %v", err)
+			api.Logger().ErrorContext(r.Context(), "auth session service error", "error", err)
 			response.Error(w, http.StatusInternalServerError, "failed to load session")
 			return
 		}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/ical"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleRotateCalendarFeed handles POST /api/v1/calendar/feed
+// Issues a new feed URL; any previously issued URL stops working.
+func HandleRotateCalendarFeed(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		resp, err := api.Service().RotateCalendarFeedToken(r.Context(), userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, resp)
+	}
+}
+
+// HandleRevokeCalendarFeed handles DELETE /api/v1/calendar/feed
+func HandleRevokeCalendarFeed(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		if err := api.Service().RevokeCalendarFeedToken(r.Context(), userID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// HandleCalendarFeed handles GET /api/v1/calendar/feed/{token}.ics
+// Calendar clients cannot send cookies, so the secret token in the URL is the
+// only credential.
+func HandleCalendarFeed(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		cal, err := api.Service().CalendarFeed(r.Context(), chi.URLParam(r, "token"))
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Cache-Control", "private, no-cache")
+		w.WriteHeader(http.StatusOK)
+		_, _ = cal.WriteTo(w)
+	}
+}
+
+// HandleGetMeetingCalendar handles GET /api/v1/meetings/{meetingID}/calendar.ics
+func HandleGetMeetingCalendar(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		// Same access rule as GET /meetings/{meetingID}
+		detail, ok := checkMeetingAccess(api, w, r, meetingID, "")
+		if !ok {
+			return
+		}
+
+		cal, err := api.Service().MeetingCalendar(r.Context(), detail)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", ical.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", detail.Summary.ID+".ics"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = cal.WriteTo(w)
+	}
+}
+
+// HandleImportCalendar handles POST /api/v1/meetings/import
+// The body carries either the contents of an .ics file or a URL to fetch it
+// from; the response lists the outcome for every event.
+func HandleImportCalendar(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req core.CalendarImportRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		resp, err := api.Service().ImportCalendar(r.Context(), userID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, resp)
+	}
+}
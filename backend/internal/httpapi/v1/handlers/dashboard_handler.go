@@ -20,7 +20,7 @@ func HandleGetDashboardOverview(api contracts.V1APIInterface) http.HandlerFunc {
 
 		overview, err := api.Service().GetDashboardOverview(r.Context(), userID)
 		if err != nil {
-			api.Logger().Printf("dashboard overview service error: %v", err)
+			api.Logger().ErrorContext(r.Context(), "dashboard overview service error", "error", err)
 			response.Error(w, http.StatusInternalServerError, "failed to load dashboard")
 			return
 		}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// meetingEventStreamDuration ends each stream before the router's request
+	// timeout. EventSource reconnects by itself and resumes via Last-Event-ID.
+	meetingEventStreamDuration = 50 * time.Second
+	meetingEventRetryMs        = 1000
+)
+
+// HandleMeetingEvents handles GET /api/v1/meetings/{meetingID}/events
+// It streams the meeting's events as Server-Sent Events. Clients resume with
+// the Last-Event-ID header, or the lastEventId query parameter when they
+// reconnect by hand.
+func HandleMeetingEvents(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		lastEventID := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+		if lastEventID == "" {
+			lastEventID = strings.TrimSpace(r.URL.Query().Get("lastEventId"))
+		}
+		var after int64
+		if lastEventID != "" {
+			id, err := strconv.ParseInt(lastEventID, 10, 64)
+			if err != nil || id < 0 {
+				response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, "invalid last event id")
+				return
+			}
+			after = id
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		stream, err := api.Service().SubscribeMeetingEvents(r.Context(), meetingID, userID, after)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+		defer stream.Close()
+
+		ctx, cancel := context.WithTimeout(r.Context(), meetingEventStreamDuration)
+		defer cancel()
+
+		rc := http.NewResponseController(w)
+		// The server's write timeout is meant for ordinary requests
+		_ = rc.SetWriteDeadline(time.Now().Add(meetingEventStreamDuration + 5*time.Second))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", meetingEventRetryMs)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		for {
+			events, err := stream.Next(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					api.Logger().ErrorContext(r.Context(), "meeting event stream error", "error", err)
+				}
+				return
+			}
+
+			if len(events) == 0 {
+				_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			for _, ev := range events {
+				payload, _ := json.Marshal(ev)
+				if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, payload); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
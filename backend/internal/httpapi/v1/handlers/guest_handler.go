@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// guestTokenHeader carries the token of a guest identity
+const guestTokenHeader = "X-Guest-Token"
+
+// HandleGetMeetingAccess handles GET /api/v1/meetings/{meetingID}/access
+func HandleGetMeetingAccess(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionAdmit); !ok {
+			return
+		}
+
+		access, err := api.Service().GetMeetingAccess(r.Context(), meetingID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, access)
+	}
+}
+
+// HandleUpdateMeetingAccess handles PUT /api/v1/meetings/{meetingID}/access
+func HandleUpdateMeetingAccess(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateMeetingAccessRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionAdmit); !ok {
+			return
+		}
+
+		access, err := api.Service().UpdateMeetingAccess(r.Context(), meetingID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, access)
+	}
+}
+
+// HandleRotateGuestLink handles POST /api/v1/meetings/{meetingID}/guest-link
+// It returns a new shareable link and invalidates the previous one.
+func HandleRotateGuestLink(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionInvite); !ok {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		link, err := api.Service().RotateGuestLink(r.Context(), meetingID, userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, link)
+	}
+}
+
+// HandleRevokeGuestLink handles DELETE /api/v1/meetings/{meetingID}/guest-link
+func HandleRevokeGuestLink(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionInvite); !ok {
+			return
+		}
+
+		if err := api.Service().RevokeGuestLink(r.Context(), meetingID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// HandleListMeetingGuests handles GET /api/v1/meetings/{meetingID}/guests
+func HandleListMeetingGuests(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionAdmit); !ok {
+			return
+		}
+
+		guests, err := api.Service().ListMeetingGuests(r.Context(), meetingID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, guests)
+	}
+}
+
+// HandleUpdateMeetingGuest handles PUT /api/v1/meetings/{meetingID}/guests/{guestID}
+// The host admits or denies a guest.
+func HandleUpdateMeetingGuest(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		guestID := chi.URLParam(r, "guestID")
+		if err := utils.ValidateUUID(guestID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateMeetingGuestRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionAdmit); !ok {
+			return
+		}
+
+		guest, err := api.Service().DecideMeetingGuest(r.Context(), meetingID, guestID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, guest)
+	}
+}
+
+// HandleGetGuestLink handles GET /api/v1/guest/{linkToken}
+// It previews the meeting behind a guest link.
+func HandleGetGuestLink(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		preview, err := api.Service().GuestMeetingPreview(r.Context(), chi.URLParam(r, "linkToken"))
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, preview)
+	}
+}
+
+// HandleGuestJoin handles POST /api/v1/guest/{linkToken}/join
+// It creates a guest identity; join credentials are included when the guest
+// is admitted and the meeting is running.
+func HandleGuestJoin(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req core.GuestJoinRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		guest, err := api.Service().JoinAsGuest(r.Context(), chi.URLParam(r, "linkToken"), req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		if guest.Join != nil {
+			issueJoinCredentials(api, r, guest.Join, guest.Join.ParticipantID, guest.DisplayName, guest.Join.MeetingID, true)
+		}
+
+		response.JSON(w, http.StatusCreated, guest)
+	}
+}
+
+// HandleGuestStatus handles GET /api/v1/guest/{linkToken}/status
+// Guests poll it with their X-Guest-Token while waiting for the host or for
+// the meeting to start.
+func HandleGuestStatus(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		guest, err := api.Service().GuestStatus(r.Context(), chi.URLParam(r, "linkToken"), r.Header.Get(guestTokenHeader))
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		if guest.Join != nil {
+			issueJoinCredentials(api, r, guest.Join, guest.Join.ParticipantID, guest.DisplayName, guest.Join.MeetingID, true)
+		}
+
+		response.JSON(w, http.StatusOK, guest)
+	}
+}
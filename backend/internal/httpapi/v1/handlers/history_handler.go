@@ -20,7 +20,7 @@ func HandleListTranscripts(api contracts.V1APIInterface) http.HandlerFunc {
 
 		resp, err := api.Service().ListTranscripts(r.Context())
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -35,7 +35,7 @@ func HandleGetTranscript(api contracts.V1APIInterface) http.HandlerFunc {
 
 		transcriptID := chi.URLParam(r, "transcriptID")
 		if err := utils.ValidateID(transcriptID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -47,17 +47,13 @@ func HandleGetTranscript(api contracts.V1APIInterface) http.HandlerFunc {
 
 		// Check ownership before fetching transcript details
 		if err := api.Service().CheckTranscriptOwnership(r.Context(), transcriptID, userID); err != nil {
-			if err.Error() == "transcript not found" {
-				response.Error(w, http.StatusNotFound, err.Error())
-			} else {
-				response.Error(w, http.StatusForbidden, "unauthorized: you do not own this transcript")
-			}
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
 		detail, err := api.Service().GetTranscript(r.Context(), transcriptID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
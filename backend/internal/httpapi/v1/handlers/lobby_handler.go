@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleGetMeetingLobby handles GET /api/v1/meetings/{meetingID}/lobby
+func HandleGetMeetingLobby(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionAdmit); !ok {
+			return
+		}
+
+		lobby, err := api.Service().GetMeetingLobby(r.Context(), meetingID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, lobby)
+	}
+}
+
+// HandleGetLobbyStatus handles GET /api/v1/meetings/{meetingID}/lobby/status
+// Participants poll it while waiting; once admitted they join again to get
+// their tokens.
+func HandleGetLobbyStatus(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		status, err := api.Service().LobbyStatus(r.Context(), meetingID, userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, status)
+	}
+}
+
+// HandleUpdateLobbyEntry handles PUT /api/v1/meetings/{meetingID}/lobby/{userID}
+// The host admits or denies a participant.
+func HandleUpdateLobbyEntry(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		participantID := chi.URLParam(r, "userID")
+		if err := utils.ValidateUUID(participantID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateLobbyEntryRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionAdmit); !ok {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		entry, err := api.Service().DecideLobbyEntry(r.Context(), meetingID, participantID, userID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, entry)
+	}
+}
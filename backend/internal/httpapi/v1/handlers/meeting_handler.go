@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
@@ -13,20 +14,31 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-// HandleListMeetings handles GET /api/v1/meetings
+// HandleListMeetings handles GET /api/v1/meetings?from=&to=
+// The optional RFC 3339 window bounds which occurrences of recurring meetings are listed.
 func HandleListMeetings(api contracts.V1APIInterface) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
+		from, err := parseTimeParam(r, "from")
+		if err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		to, err := parseTimeParam(r, "to")
+		if err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
 		if !api.EnsureService(w) {
 			return
 		}
 
 		userID := httpapicontext.UserIDFromContext(r.Context())
 
-		resp, err := api.Service().ListMeetings(r.Context(), userID)
+		resp, err := api.Service().ListMeetings(r.Context(), userID, from, to)
 		if err != nil {
-			api.Logger().Printf("list meetings service error: %v", err)
-			response.Error(w, http.StatusInternalServerError, "failed to load meetings")
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -41,7 +53,7 @@ func HandleCreateMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		var req core.MeetingCreateRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -53,7 +65,7 @@ func HandleCreateMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		meeting, err := api.Service().CreateMeeting(r.Context(), req, userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -67,7 +79,7 @@ func HandleGetMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		meetingID := chi.URLParam(r, "meetingID")
 		if err := utils.ValidateID(meetingID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -75,23 +87,12 @@ func HandleGetMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 			return
 		}
 
-		userID := httpapicontext.UserIDFromContext(r.Context())
-
-		detail, err := api.Service().GetMeeting(r.Context(), meetingID)
-		if err != nil {
-			api.RespondServiceError(w, err)
+		// Anyone may read public meetings, only participants private ones
+		detail, ok := checkMeetingAccess(api, w, r, meetingID, "")
+		if !ok {
 			return
 		}
 
-		// Check ownership - only host can access private meetings
-		// For public meetings, we allow read access but ownership check still applies for modifications
-		if detail.Summary.Visibility == "private" {
-			if err := utils.CheckMeetingOwnership(detail.Summary.HostUserID, userID); err != nil {
-				response.Error(w, http.StatusForbidden, "unauthorized: you do not have access to this meeting")
-				return
-			}
-		}
-
 		response.JSON(w, http.StatusOK, core.MeetingDetailResponse{Meeting: *detail})
 	}
 }
@@ -102,13 +103,13 @@ func HandleUpdateMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		meetingID := chi.URLParam(r, "meetingID")
 		if err := utils.ValidateID(meetingID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
 		var req core.MeetingUpdateRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -116,27 +117,20 @@ func HandleUpdateMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 			return
 		}
 
-		userID := httpapicontext.UserIDFromContext(r.Context())
-
-		// Check ownership and if meeting is actionable before updating
-		existing, err := api.Service().GetMeeting(r.Context(), meetingID)
-		if err != nil {
-			api.RespondServiceError(w, err)
-			return
-		}
-		if err := utils.CheckMeetingOwnership(existing.Summary.HostUserID, userID); err != nil {
-			response.Error(w, http.StatusForbidden, "unauthorized: you do not own this meeting")
+		// Check permission and if meeting is actionable before updating
+		existing, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit)
+		if !ok {
 			return
 		}
 		// Validate meeting action (ended/past meetings cannot be updated)
 		if err := utils.ValidateMeetingAction(existing.Summary.Status, existing.Summary.StartTime, existing.Summary.DurationMinutes); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, "meeting_not_actionable", err.Error())
 			return
 		}
 
 		meeting, err := api.Service().UpdateMeeting(r.Context(), meetingID, req)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -151,7 +145,7 @@ func HandleDeleteMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		meetingID := chi.URLParam(r, "meetingID")
 		if err := utils.ValidateID(meetingID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -159,22 +153,14 @@ func HandleDeleteMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 			return
 		}
 
-		userID := httpapicontext.UserIDFromContext(r.Context())
-
-		// Check ownership before deleting (deletion allowed even for ended meetings)
-		existing, err := api.Service().GetMeeting(r.Context(), meetingID)
-		if err != nil {
-			api.RespondServiceError(w, err)
-			return
-		}
-		if err := utils.CheckMeetingOwnership(existing.Summary.HostUserID, userID); err != nil {
-			response.Error(w, http.StatusForbidden, "unauthorized: you do not own this meeting")
+		// Check permission before deleting (deletion allowed even for ended meetings)
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionDelete); !ok {
 			return
 		}
 		// Note: Deletion is allowed for ended/past meetings, so we don't validate action here
 
 		if err := api.Service().DeleteMeeting(r.Context(), meetingID); err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -188,7 +174,7 @@ func HandleStartMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		meetingID := chi.URLParam(r, "meetingID")
 		if err := utils.ValidateID(meetingID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -201,25 +187,24 @@ func HandleStartMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 		// Check if meeting exists and is actionable before attempting to start
 		existing, err := api.Service().GetMeeting(r.Context(), meetingID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
 		// Validate meeting action (check if ended or past)
 		if err := utils.ValidateMeetingAction(existing.Summary.Status, existing.Summary.StartTime, existing.Summary.DurationMinutes); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, "meeting_not_actionable", err.Error())
 			return
 		}
 
-		// Check ownership
-		if err := utils.CheckMeetingOwnership(existing.Summary.HostUserID, userID); err != nil {
-			response.Error(w, http.StatusForbidden, "unauthorized: you do not own this meeting")
+		// Check permission
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionStart); !ok {
 			return
 		}
 
 		detail, err := api.Service().StartMeeting(r.Context(), meetingID, userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -233,7 +218,7 @@ func HandleJoinMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 
 		meetingID := chi.URLParam(r, "meetingID")
 		if err := utils.ValidateID(meetingID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -246,48 +231,116 @@ func HandleJoinMeeting(api contracts.V1APIInterface) http.HandlerFunc {
 		// Get user info for LiveKit token
 		authSession, err := api.Service().GetAuthSession(r.Context(), userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
 		joinResp, err := api.Service().JoinMeeting(r.Context(), meetingID, userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		// Participants held in the lobby get no tokens until the host admits them
+		if joinResp.Status == services.JoinStatusWaiting {
+			response.JSON(w, http.StatusAccepted, joinResp)
+			return
+		}
+
+		issueJoinCredentials(api, r, joinResp, userID, authSession.User.Name, meetingID, false)
+
+		response.JSON(w, http.StatusOK, joinResp)
+	}
+}
+
+// HandleLeaveMeeting handles POST /api/v1/meetings/{meetingID}/leave
+func HandleLeaveMeeting(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		if err := api.Service().LeaveMeeting(r.Context(), meetingID, userID); err != nil {
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
-		// Generate LiveKit token if configured
-		cfg := api.Cfg()
-		if cfg.LiveKitURL != "" && cfg.LiveKitAPIKey != "" && cfg.LiveKitAPISecret != "" {
-			livekitToken, err := services.GenerateLiveKitToken(
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// issueJoinCredentials fills in the media credentials of a join response.
+// Guests get a LiveKit token with limited grants that lapses with their
+// identity, and no AI tokens.
+func issueJoinCredentials(api contracts.V1APIInterface, r *http.Request, joinResp *core.MeetingJoinResponse, identity, name, room string, guest bool) {
+	// Generate LiveKit token if configured
+	cfg := api.Cfg()
+	if cfg.LiveKitURL != "" && cfg.LiveKitAPIKey != "" && cfg.LiveKitAPISecret != "" {
+		var (
+			livekitToken string
+			err          error
+		)
+		if guest {
+			livekitToken, err = services.GenerateGuestLiveKitToken(
 				cfg.LiveKitAPIKey,
 				cfg.LiveKitAPISecret,
-				userID,
-				authSession.User.Name,
-				meetingID, // Use meetingID as room name
-				true,      // canPublish
-				true,      // canSubscribe
+				identity,
+				name,
+				room,
+				time.Until(joinResp.ExpiresAt),
 			)
-			if err != nil {
-				api.Logger().Printf("failed to generate LiveKit token: %v", err)
-				// Continue without LiveKit token if generation fails
-			} else {
-				joinResp.LiveKitToken = livekitToken
-				joinResp.LiveKitURL = cfg.LiveKitURL
-			}
+		} else {
+			livekitToken, err = services.GenerateLiveKitToken(
+				cfg.LiveKitAPIKey,
+				cfg.LiveKitAPISecret,
+				identity,
+				name,
+				room, // Use meetingID as room name
+				true, // canPublish
+				true, // canSubscribe
+			)
+		}
+		if err != nil {
+			api.Logger().WarnContext(r.Context(), "failed to generate LiveKit token", "error", err)
+			// Continue without LiveKit token if generation fails
+		} else {
+			joinResp.LiveKitToken = livekitToken
+			joinResp.LiveKitURL = cfg.LiveKitURL
 		}
+	}
 
-		// Legacy tokens (keep for backward compatibility)
-		joinResp.WebRTCToken = utils.GenerateToken(24)
+	// Legacy tokens (keep for backward compatibility)
+	joinResp.WebRTCToken = utils.GenerateToken(24)
+	if !guest {
 		joinResp.AIRealtimeToken = utils.GenerateToken(24)
 		joinResp.VoiceSynthToken = utils.GenerateToken(24)
 		joinResp.ExpiresAt = time.Now().Add(10 * time.Minute)
-		joinResp.TurnCredentials = core.TurnCredentials{
-			URL:      cfg.WebrtcTURNURL,
-			Username: cfg.WebrtcTURNUsername,
-			Password: cfg.WebrtcTURNPassword,
-		}
+	}
+	joinResp.TurnCredentials = core.TurnCredentials{
+		URL:      cfg.WebrtcTURNURL,
+		Username: cfg.WebrtcTURNUsername,
+		Password: cfg.WebrtcTURNPassword,
+	}
+}
 
-		response.JSON(w, http.StatusOK, joinResp)
+// parseTimeParam reads an optional RFC 3339 query parameter
+func parseTimeParam(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
 	}
+	return t, nil
 }
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleListNotifications handles GET /api/v1/notifications
+// It returns the caller's inbox, newest first. unreadOnly=true leaves out
+// read notifications; limit caps the number returned.
+func HandleListNotifications(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		query := r.URL.Query()
+		unreadOnly := false
+		if value := query.Get("unreadOnly"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, "unreadOnly must be true or false")
+				return
+			}
+			unreadOnly = parsed
+		}
+		limit := 0
+		if value := query.Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, "limit must be a positive integer")
+				return
+			}
+			limit = parsed
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		notifications, err := api.Service().ListNotifications(r.Context(), userID, unreadOnly, limit)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, notifications)
+	}
+}
+
+// HandleUpdateNotification handles PATCH /api/v1/notifications/{notificationID}
+// It marks a notification as read or unread.
+func HandleUpdateNotification(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		notificationID := chi.URLParam(r, "notificationID")
+		if err := utils.ValidateUUID(notificationID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateNotificationRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		notification, err := api.Service().MarkNotification(r.Context(), userID, notificationID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, notification)
+	}
+}
+
+// HandleReadAllNotifications handles POST /api/v1/notifications/read-all
+func HandleReadAllNotifications(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		if err := api.Service().MarkAllNotificationsRead(r.Context(), userID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// HandleGetNotificationPreferences handles GET /api/v1/notifications/preferences
+func HandleGetNotificationPreferences(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		prefs, err := api.Service().GetNotificationPreferences(r.Context(), userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, prefs)
+	}
+}
+
+// HandleUpdateNotificationPreferences handles PUT /api/v1/notifications/preferences
+func HandleUpdateNotificationPreferences(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req core.UpdateNotificationPreferencesRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		prefs, err := api.Service().UpdateNotificationPreferences(r.Context(), userID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, prefs)
+	}
+}
+
+// HandleGetNotificationWebhook handles GET /api/v1/notifications/webhook
+func HandleGetNotificationWebhook(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		hook, err := api.Service().GetNotificationWebhook(r.Context(), userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, hook)
+	}
+}
+
+// HandleUpdateNotificationWebhook handles PUT /api/v1/notifications/webhook
+// It returns a new signing secret, which is not shown again.
+func HandleUpdateNotificationWebhook(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req core.UpdateNotificationWebhookRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		hook, err := api.Service().SetNotificationWebhook(r.Context(), userID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, hook)
+	}
+}
+
+// HandleDeleteNotificationWebhook handles DELETE /api/v1/notifications/webhook
+func HandleDeleteNotificationWebhook(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		if err := api.Service().DeleteNotificationWebhook(r.Context(), userID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleListMeetingResources handles GET /api/v1/meetings/{meetingID}/resources
+func HandleListMeetingResources(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, ""); !ok {
+			return
+		}
+
+		resources, err := api.Service().ListMeetingResources(r.Context(), meetingID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, resources)
+	}
+}
+
+// HandleCreateMeetingResource handles POST /api/v1/meetings/{meetingID}/resources
+func HandleCreateMeetingResource(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.ResourceLink
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit); !ok {
+			return
+		}
+
+		resource, err := api.Service().AddMeetingResource(r.Context(), meetingID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, resource)
+	}
+}
+
+// HandleUpdateMeetingResource handles PUT /api/v1/meetings/{meetingID}/resources/{resourceID}
+func HandleUpdateMeetingResource(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		resourceID := chi.URLParam(r, "resourceID")
+		if err := utils.ValidateID(resourceID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateMeetingResourceRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit); !ok {
+			return
+		}
+
+		resource, err := api.Service().UpdateMeetingResource(r.Context(), meetingID, resourceID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, resource)
+	}
+}
+
+// HandleDeleteMeetingResource handles DELETE /api/v1/meetings/{meetingID}/resources/{resourceID}
+func HandleDeleteMeetingResource(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		resourceID := chi.URLParam(r, "resourceID")
+		if err := utils.ValidateID(resourceID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit); !ok {
+			return
+		}
+
+		if err := api.Service().DeleteMeetingResource(r.Context(), meetingID, resourceID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// HandleGetMeetingNotes handles GET /api/v1/meetings/{meetingID}/notes
+func HandleGetMeetingNotes(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, ""); !ok {
+			return
+		}
+
+		notes, err := api.Service().GetMeetingNotes(r.Context(), meetingID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, notes)
+	}
+}
+
+// HandleUpdateMeetingNotes handles PUT /api/v1/meetings/{meetingID}/notes
+func HandleUpdateMeetingNotes(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateMeetingNotesRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit); !ok {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		notes, err := api.Service().UpdateMeetingNotes(r.Context(), meetingID, userID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, notes)
+	}
+}
+
+// HandleDeleteMeetingNotes handles DELETE /api/v1/meetings/{meetingID}/notes
+func HandleDeleteMeetingNotes(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEdit); !ok {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		if _, err := api.Service().UpdateMeetingNotes(r.Context(), meetingID, userID, core.UpdateMeetingNotesRequest{}); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
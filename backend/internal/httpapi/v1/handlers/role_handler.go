@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleGetMeetingPermissions handles GET /api/v1/meetings/{meetingID}/permissions
+// It returns the caller's role and what it allows, so clients can show the
+// right controls.
+func HandleGetMeetingPermissions(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, ""); !ok {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		permissions, err := api.Service().MeetingPermissions(r.Context(), meetingID, userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, permissions)
+	}
+}
+
+// HandleUpdateParticipantRole handles PUT /api/v1/meetings/{meetingID}/participants/{userID}/role
+// The host promotes or demotes a participant.
+func HandleUpdateParticipantRole(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		participantID := chi.URLParam(r, "userID")
+		if err := utils.ValidateUUID(participantID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateParticipantRoleRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionManageRoles); !ok {
+			return
+		}
+
+		participant, err := api.Service().SetParticipantRole(r.Context(), meetingID, participantID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, participant)
+	}
+}
+
+// HandleTransferMeeting handles POST /api/v1/meetings/{meetingID}/transfer
+// The host hands the meeting to another user and stays on as co-host.
+func HandleTransferMeeting(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.TransferMeetingRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+		if err := utils.ValidateUUID(req.UserID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionManageRoles); !ok {
+			return
+		}
+
+		detail, err := api.Service().TransferMeetingOwnership(r.Context(), meetingID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, core.MeetingDetailResponse{Meeting: *detail})
+	}
+}
+
+// HandleEndMeeting handles POST /api/v1/meetings/{meetingID}/end
+func HandleEndMeeting(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		meetingID := chi.URLParam(r, "meetingID")
+		if err := utils.ValidateID(meetingID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		if _, ok := checkMeetingAccess(api, w, r, meetingID, services.PermissionEnd); !ok {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		detail, err := api.Service().EndMeeting(r.Context(), meetingID, userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, core.MeetingDetailResponse{Meeting: *detail})
+	}
+}
@@ -24,7 +24,7 @@ func HandleGetSettings(api contracts.V1APIInterface) http.HandlerFunc {
 
 		settings, err := api.Service().GetSettings(r.Context(), userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -39,7 +39,7 @@ func HandleUpdateSettings(api contracts.V1APIInterface) http.HandlerFunc {
 
 		var req core.SettingsUpdateRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -51,7 +51,7 @@ func HandleUpdateSettings(api contracts.V1APIInterface) http.HandlerFunc {
 
 		settings, err := api.Service().UpdateSettings(r.Context(), userID, req)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -71,7 +71,7 @@ func HandleListVoicePresets(api contracts.V1APIInterface) http.HandlerFunc {
 
 		presets, err := api.Service().ListVoicePresets(r.Context(), userID)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -85,7 +85,7 @@ func HandleCreateVoicePreset(api contracts.V1APIInterface) http.HandlerFunc {
 
 		var req core.CreateVoicePresetRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -97,7 +97,7 @@ func HandleCreateVoicePreset(api contracts.V1APIInterface) http.HandlerFunc {
 
 		preset, err := api.Service().CreateVoicePreset(r.Context(), userID, req)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -112,13 +112,13 @@ func HandleUpdateVoicePreset(api contracts.V1APIInterface) http.HandlerFunc {
 
 		presetID := chi.URLParam(r, "presetID")
 		if err := utils.ValidateID(presetID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
 		var req core.UpdateVoicePresetRequest
 		if err := utils.DecodeJSON(r.Body, &req); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -130,7 +130,7 @@ func HandleUpdateVoicePreset(api contracts.V1APIInterface) http.HandlerFunc {
 
 		preset, err := api.Service().UpdateVoicePreset(r.Context(), userID, presetID, req)
 		if err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -145,7 +145,7 @@ func HandleDeleteVoicePreset(api contracts.V1APIInterface) http.HandlerFunc {
 
 		presetID := chi.URLParam(r, "presetID")
 		if err := utils.ValidateID(presetID); err != nil {
-			response.Error(w, http.StatusBadRequest, err.Error())
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
 			return
 		}
 
@@ -156,7 +156,7 @@ func HandleDeleteVoicePreset(api contracts.V1APIInterface) http.HandlerFunc {
 		userID := httpapicontext.UserIDFromContext(r.Context())
 
 		if err := api.Service().DeleteVoicePreset(r.Context(), userID, presetID); err != nil {
-			api.RespondServiceError(w, err)
+			api.RespondServiceError(w, r, err)
 			return
 		}
 
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	httpapicontext "github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/context"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/response"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleListTemplates handles GET /api/v1/templates
+func HandleListTemplates(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		templates, err := api.Service().ListMeetingTemplates(r.Context(), userID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, templates)
+	}
+}
+
+// HandleCreateTemplate handles POST /api/v1/templates
+func HandleCreateTemplate(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var req core.CreateMeetingTemplateRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		template, err := api.Service().CreateMeetingTemplate(r.Context(), userID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, template)
+	}
+}
+
+// HandleGetTemplate handles GET /api/v1/templates/{templateID}
+func HandleGetTemplate(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		templateID := chi.URLParam(r, "templateID")
+		if err := utils.ValidateID(templateID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		template, err := api.Service().GetMeetingTemplate(r.Context(), userID, templateID)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, template)
+	}
+}
+
+// HandleUpdateTemplate handles PUT /api/v1/templates/{templateID}
+func HandleUpdateTemplate(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		templateID := chi.URLParam(r, "templateID")
+		if err := utils.ValidateID(templateID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.UpdateMeetingTemplateRequest
+		if err := utils.DecodeJSON(r.Body, &req); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		template, err := api.Service().UpdateMeetingTemplate(r.Context(), userID, templateID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusOK, template)
+	}
+}
+
+// HandleDeleteTemplate handles DELETE /api/v1/templates/{templateID}
+func HandleDeleteTemplate(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		templateID := chi.URLParam(r, "templateID")
+		if err := utils.ValidateID(templateID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		if err := api.Service().DeleteMeetingTemplate(r.Context(), userID, templateID); err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusNoContent, nil)
+	}
+}
+
+// HandleCreateMeetingFromTemplate handles POST /api/v1/meetings/from-template/{templateID}
+// The optional body uses the create-meeting shape; any fields it sets
+// override the template.
+func HandleCreateMeetingFromTemplate(api contracts.V1APIInterface) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		templateID := chi.URLParam(r, "templateID")
+		if err := utils.ValidateID(templateID); err != nil {
+			response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+			return
+		}
+
+		var req core.MeetingCreateRequest
+		if r.ContentLength != 0 {
+			if err := utils.DecodeJSON(r.Body, &req); err != nil {
+				response.ErrorCode(w, http.StatusBadRequest, response.CodeInvalidRequest, err.Error())
+				return
+			}
+		}
+
+		if !api.EnsureService(w) {
+			return
+		}
+
+		userID := httpapicontext.UserIDFromContext(r.Context())
+
+		meeting, err := api.Service().CreateMeetingFromTemplate(r.Context(), userID, templateID, req)
+		if err != nil {
+			api.RespondServiceError(w, r, err)
+			return
+		}
+
+		response.JSON(w, http.StatusCreated, core.MeetingDetailResponse{Meeting: *meeting})
+	}
+}
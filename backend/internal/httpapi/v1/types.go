@@ -6,6 +6,7 @@ import (
 
 	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi/contracts"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
 )
 
@@ -25,7 +26,7 @@ func (api *API) Service() *services.AppService {
 }
 
 // Logger returns the logger
-func (api *API) Logger() contracts.Logger {
+func (api *API) Logger() logger.Logger {
 	return api.parent.Logger()
 }
 
@@ -50,8 +51,8 @@ func (api *API) EnsureService(w http.ResponseWriter) bool {
 }
 
 // RespondServiceError handles service errors with appropriate status codes
-func (api *API) RespondServiceError(w http.ResponseWriter, err error) {
-	api.parent.RespondServiceError(w, err)
+func (api *API) RespondServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	api.parent.RespondServiceError(w, r, err)
 }
 
 // AuthMiddleware returns the authentication middleware
@@ -59,6 +60,21 @@ func (api *API) AuthMiddleware(next http.Handler) http.Handler {
 	return api.parent.AuthMiddleware(next)
 }
 
+// CSRFMiddleware returns the CSRF token middleware for protected routes
+func (api *API) CSRFMiddleware(next http.Handler) http.Handler {
+	return api.parent.CSRFMiddleware(next)
+}
+
+// TrustedOriginMiddleware returns the origin check for public auth routes
+func (api *API) TrustedOriginMiddleware(next http.Handler) http.Handler {
+	return api.parent.TrustedOriginMiddleware(next)
+}
+
+// CSRFToken derives the CSRF token for a session
+func (api *API) CSRFToken(sessionID string) string {
+	return api.parent.CSRFToken(sessionID)
+}
+
 // SignAccessToken signs a JWT access token
 func (api *API) SignAccessToken(userID, sessionID string) (string, time.Time, error) {
 	return api.parent.SignAccessToken(userID, sessionID)
@@ -0,0 +1,343 @@
+// Package ical reads and writes iCalendar (RFC 5545) calendars for meeting
+// feeds, downloads and imports.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+
+	dateTimeUTC   = "20060102T150405Z"
+	dateTimeLocal = "20060102T150405"
+
+	// maxLineOctets is the longest content line allowed before folding
+	maxLineOctets = 75
+	// timezoneYears is how far past the last event VTIMEZONE transitions are
+	// listed, so open-ended recurring meetings stay correct for a while
+	timezoneYears = 10
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string
+	// Name is the display name used by clients for subscribed feeds
+	Name   string
+	Method string
+	Events []Event
+}
+
+// Attendee is an ATTENDEE or ORGANIZER property
+type Attendee struct {
+	Email string
+	Name  string
+	// PartStat is the participation status, e.g. NEEDS-ACTION or ACCEPTED
+	PartStat string
+}
+
+// Event is a VEVENT. Start's location decides how times are written: UTC
+// times use the Z form, any other zone is written with a TZID and a matching
+// VTIMEZONE is added to the calendar.
+type Event struct {
+	UID          string
+	Sequence     int
+	Stamp        time.Time
+	LastModified time.Time
+	Start        time.Time
+	Duration     time.Duration
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string
+	RRule        string
+	ExDates      []time.Time
+	// RecurrenceID marks the event as an override of one occurrence of the
+	// recurring event with the same UID
+	RecurrenceID time.Time
+	// AllDay events have a DATE start and whole-day duration
+	AllDay    bool
+	Organizer *Attendee
+	Attendees []Attendee
+}
+
+// Encode renders the calendar
+func (c *Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	_, _ = c.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo writes the calendar with CRLF line endings and folded lines
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	e := &encoder{}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.prop("PRODID", c.ProdID)
+	e.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		e.prop("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		e.text("X-WR-CALNAME", c.Name)
+	}
+
+	for _, tz := range c.timezones() {
+		writeTimezone(e, tz.loc, tz.from, tz.to)
+	}
+	for _, ev := range c.Events {
+		writeEvent(e, ev)
+	}
+
+	e.line("END:VCALENDAR")
+	n, err := w.Write(e.buf.Bytes())
+	return int64(n), err
+}
+
+type timezoneRange struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// timezones returns the non-UTC zones used by events and the time span each
+// needs to cover
+func (c *Calendar) timezones() []timezoneRange {
+	byName := make(map[string]*timezoneRange)
+	for _, ev := range c.Events {
+		loc := ev.Start.Location()
+		if isUTC(loc) || ev.AllDay {
+			continue
+		}
+		tz, ok := byName[loc.String()]
+		if !ok {
+			tz = &timezoneRange{loc: loc, from: ev.Start, to: ev.Start}
+			byName[loc.String()] = tz
+		}
+		if ev.Start.Before(tz.from) {
+			tz.from = ev.Start
+		}
+		end := ev.Start
+		if ev.RRule != "" && time.Now().After(end) {
+			end = time.Now()
+		}
+		if end.After(tz.to) {
+			tz.to = end
+		}
+	}
+
+	out := make([]timezoneRange, 0, len(byName))
+	for _, tz := range byName {
+		out = append(out, *tz)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].loc.String() < out[j].loc.String() })
+	return out
+}
+
+func writeEvent(e *encoder, ev Event) {
+	e.line("BEGIN:VEVENT")
+	e.prop("UID", ev.UID)
+	e.prop("DTSTAMP", ev.Stamp.UTC().Format(dateTimeUTC))
+	if !ev.LastModified.IsZero() {
+		e.prop("LAST-MODIFIED", ev.LastModified.UTC().Format(dateTimeUTC))
+	}
+	e.prop("SEQUENCE", fmt.Sprint(ev.Sequence))
+	if ev.AllDay {
+		e.date("DTSTART", ev.Start)
+		e.date("DTEND", ev.Start.AddDate(0, 0, max(1, int(ev.Duration/(24*time.Hour)))))
+	} else {
+		e.dateTime("DTSTART", ev.Start)
+		if ev.Duration > 0 {
+			e.dateTime("DTEND", ev.Start.Add(ev.Duration))
+		}
+	}
+	if !ev.RecurrenceID.IsZero() {
+		e.dateTime("RECURRENCE-ID", ev.RecurrenceID.In(ev.Start.Location()))
+	}
+	if ev.RRule != "" {
+		e.prop("RRULE", ev.RRule)
+	}
+	for _, ex := range ev.ExDates {
+		e.dateTime("EXDATE", ex.In(ev.Start.Location()))
+	}
+	e.text("SUMMARY", ev.Summary)
+	if ev.Description != "" {
+		e.text("DESCRIPTION", ev.Description)
+	}
+	if ev.Location != "" {
+		e.text("LOCATION", ev.Location)
+	}
+	if ev.URL != "" {
+		e.prop("URL", ev.URL)
+	}
+	status := ev.Status
+	if status == "" {
+		status = StatusConfirmed
+	}
+	e.prop("STATUS", status)
+	if ev.Organizer != nil {
+		e.attendee("ORGANIZER", *ev.Organizer)
+	}
+	for _, a := range ev.Attendees {
+		e.attendee("ATTENDEE", a)
+	}
+	e.line("END:VEVENT")
+}
+
+// writeTimezone writes a VTIMEZONE listing every offset change of loc between
+// a year before from and timezoneYears after to. Each change is its own
+// observance, which keeps the output exact without deriving RRULEs.
+func writeTimezone(e *encoder, loc *time.Location, from, to time.Time) {
+	start := time.Date(from.In(loc).Year()-1, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to.In(loc).Year()+timezoneYears, time.January, 1, 0, 0, 0, 0, loc)
+
+	e.line("BEGIN:VTIMEZONE")
+	e.prop("TZID", loc.String())
+
+	// The period in effect at the start of the range, so events before the
+	// first listed change still resolve
+	name, offset := start.Zone()
+	observance(e, start.IsDST(), start.Format(dateTimeLocal), name, offset, offset)
+
+	for t := start; ; {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		_, fromOffset := t.Zone()
+		name, toOffset := next.Zone()
+		// DTSTART of an observance is local time in the offset being left
+		observance(e, next.IsDST(), next.UTC().Add(time.Duration(fromOffset)*time.Second).Format(dateTimeLocal), name, fromOffset, toOffset)
+		t = next
+	}
+
+	e.line("END:VTIMEZONE")
+}
+
+func observance(e *encoder, dst bool, start, name string, fromOffset, toOffset int) {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	e.line("BEGIN:" + kind)
+	e.prop("DTSTART", start)
+	e.prop("TZOFFSETFROM", formatOffset(fromOffset))
+	e.prop("TZOFFSETTO", formatOffset(toOffset))
+	if name != "" {
+		e.text("TZNAME", name)
+	}
+	e.line("END:" + kind)
+}
+
+func formatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	h, m, s := seconds/3600, seconds%3600/60, seconds%60
+	if s != 0 {
+		return fmt.Sprintf("%c%02d%02d%02d", sign, h, m, s)
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, h, m)
+}
+
+func isUTC(loc *time.Location) bool {
+	return loc == nil || loc == time.UTC || loc.String() == "UTC"
+}
+
+// encoder accumulates content lines
+type encoder struct {
+	buf bytes.Buffer
+}
+
+// line writes one content line, folded at 75 octets without splitting UTF-8
+// sequences (RFC 5545 section 3.1)
+func (e *encoder) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		e.buf.WriteString(s[:cut])
+		e.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	e.buf.WriteString(s)
+	e.buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func (e *encoder) prop(name, value string) {
+	e.line(name + ":" + value)
+}
+
+// text writes a TEXT property with escaping (RFC 5545 section 3.3.11)
+func (e *encoder) text(name, value string) {
+	e.line(name + ":" + escapeText(value))
+}
+
+func (e *encoder) date(name string, t time.Time) {
+	e.line(name + ";VALUE=DATE:" + t.Format(dateOnly))
+}
+
+func (e *encoder) dateTime(name string, t time.Time) {
+	if isUTC(t.Location()) {
+		e.line(name + ":" + t.UTC().Format(dateTimeUTC))
+		return
+	}
+	e.line(name + ";TZID=" + paramValue(t.Location().String()) + ":" + t.Format(dateTimeLocal))
+}
+
+func (e *encoder) attendee(name string, a Attendee) {
+	var params strings.Builder
+	if a.Name != "" {
+		params.WriteString(";CN=" + paramValue(a.Name))
+	}
+	if name == "ATTENDEE" {
+		params.WriteString(";ROLE=REQ-PARTICIPANT")
+		partStat := a.PartStat
+		if partStat == "" {
+			partStat = "NEEDS-ACTION"
+		}
+		params.WriteString(";PARTSTAT=" + partStat)
+	}
+	e.line(name + params.String() + ":mailto:" + a.Email)
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// paramValue quotes parameter values containing characters that are not
+// allowed unquoted; double quotes themselves cannot be represented and are dropped
+func paramValue(s string) string {
+	s = strings.ReplaceAll(s, `"`, "")
+	if strings.ContainsAny(s, ":;,") {
+		return `"` + s + `"`
+	}
+	return s
+}
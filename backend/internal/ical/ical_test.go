@@ -0,0 +1,383 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/recurrence"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q) error = %v", name, err)
+	}
+	return loc
+}
+
+// contentLines unfolds encoded output into logical content lines
+func contentLines(out []byte) []string {
+	unfolded := strings.ReplaceAll(string(out), "\r\n ", "")
+	return strings.Split(strings.TrimSuffix(unfolded, "\r\n"), "\r\n")
+}
+
+// componentLines returns the content lines of every component of the given
+// kind, e.g. VEVENT, in order
+func componentLines(lines []string, kind string) [][]string {
+	var (
+		out     [][]string
+		current []string
+		inside  bool
+	)
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:"+kind:
+			inside, current = true, nil
+		case line == "END:"+kind:
+			inside = false
+			out = append(out, current)
+		case inside:
+			current = append(current, line)
+		}
+	}
+	return out
+}
+
+func hasLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func baseEvent() Event {
+	return Event{
+		UID:     "m1@example.com",
+		Stamp:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Start:   time.Date(2026, 2, 3, 15, 0, 0, 0, time.UTC),
+		Summary: "Standup",
+	}
+}
+
+func TestEncodeLineEndingsAndFolding(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+	}{
+		{name: "short", summary: "Standup"},
+		{name: "ascii", summary: strings.Repeat("a", 200)},
+		{name: "two-byte runes", summary: strings.Repeat("é", 120)},
+		{name: "three-byte runes", summary: "x" + strings.Repeat("会", 90)},
+		{name: "four-byte runes", summary: "xy" + strings.Repeat("😀", 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := baseEvent()
+			ev.Summary = tt.summary
+			out := (&Calendar{ProdID: "-//test//EN", Events: []Event{ev}}).Encode()
+
+			if !bytes.HasSuffix(out, []byte("\r\n")) {
+				t.Fatal("output does not end with CRLF")
+			}
+			if bare := bytes.Count(out, []byte("\n")) - bytes.Count(out, []byte("\r\n")); bare != 0 {
+				t.Fatalf("found %d bare LF line endings", bare)
+			}
+
+			physical := strings.Split(strings.TrimSuffix(string(out), "\r\n"), "\r\n")
+			for i, line := range physical {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets: %q", i, len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+			}
+
+			if !hasLine(contentLines(out), "SUMMARY:"+tt.summary) {
+				t.Errorf("unfolded output has no SUMMARY:%s", tt.summary)
+			}
+		})
+	}
+}
+
+func TestEncodeTextEscaping(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "Weekly sync", want: "Weekly sync"},
+		{name: "comma and semicolon", in: "Plan; review, ship", want: `Plan\; review\, ship`},
+		{name: "backslash", in: `C:\temp`, want: `C:\\temp`},
+		{name: "newlines", in: "one\ntwo\r\nthree\rfour", want: `one\ntwo\nthree\nfour`},
+		{name: "colon is left alone", in: "Agenda: intro", want: "Agenda: intro"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := baseEvent()
+			ev.Summary = tt.in
+			ev.Description = tt.in
+			out := (&Calendar{ProdID: "-//test//EN", Name: tt.in, Events: []Event{ev}}).Encode()
+			lines := contentLines(out)
+
+			for _, prop := range []string{"SUMMARY", "DESCRIPTION", "X-WR-CALNAME"} {
+				if !hasLine(lines, prop+":"+tt.want) {
+					t.Errorf("missing %s:%s", prop, tt.want)
+				}
+			}
+
+			cal, err := Parse(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			want := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(tt.in)
+			if got := cal.Events[0].Summary; got != want {
+				t.Errorf("round trip SUMMARY = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestEncodeTimezoneAcrossDST(t *testing.T) {
+	tests := []struct {
+		zone        string
+		start       time.Time
+		wantStart   string
+		observances [][]string
+	}{
+		{
+			zone:      "America/New_York",
+			start:     time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+			wantStart: "DTSTART;TZID=America/New_York:20260302T090000",
+			observances: [][]string{
+				{"DTSTART:20260308T020000", "TZOFFSETFROM:-0500", "TZOFFSETTO:-0400", "TZNAME:EDT"},
+				{"DTSTART:20261101T020000", "TZOFFSETFROM:-0400", "TZOFFSETTO:-0500", "TZNAME:EST"},
+			},
+		},
+		{
+			zone:      "Europe/Berlin",
+			start:     time.Date(2026, 3, 23, 10, 30, 0, 0, time.UTC),
+			wantStart: "DTSTART;TZID=Europe/Berlin:20260323T103000",
+			observances: [][]string{
+				{"DTSTART:20260329T020000", "TZOFFSETFROM:+0100", "TZOFFSETTO:+0200", "TZNAME:CEST"},
+				{"DTSTART:20261025T030000", "TZOFFSETFROM:+0200", "TZOFFSETTO:+0100", "TZNAME:CET"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone, func(t *testing.T) {
+			loc := mustLoad(t, tt.zone)
+			ev := baseEvent()
+			// Wall-clock start in the zone
+			ev.Start = time.Date(tt.start.Year(), tt.start.Month(), tt.start.Day(), tt.start.Hour(), tt.start.Minute(), 0, 0, loc)
+			ev.Duration = time.Hour
+			ev.RRule = "FREQ=WEEKLY;COUNT=6"
+			_, before := ev.Start.Zone()
+			if _, later := ev.Start.AddDate(0, 0, 14).Zone(); before == later {
+				t.Fatalf("start %v does not precede a DST change", ev.Start)
+			}
+			out := (&Calendar{ProdID: "-//test//EN", Events: []Event{ev}}).Encode()
+			lines := contentLines(out)
+
+			if !hasLine(lines, tt.wantStart) {
+				t.Errorf("missing %s", tt.wantStart)
+			}
+			zones := componentLines(lines, "VTIMEZONE")
+			if len(zones) != 1 || !hasLine(zones[0], "TZID:"+tt.zone) {
+				t.Fatalf("want one VTIMEZONE for %s, got %v", tt.zone, zones)
+			}
+
+			var observances [][]string
+			for _, kind := range []string{"STANDARD", "DAYLIGHT"} {
+				observances = append(observances, componentLines(zones[0], kind)...)
+			}
+			for _, want := range tt.observances {
+				found := false
+				for _, got := range observances {
+					if strings.Join(got, "|") == strings.Join(want, "|") {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("missing observance %v", want)
+				}
+			}
+
+			// Reading the output back lands on the same instant
+			cal, err := Parse(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := cal.Events[0].Start; !got.Equal(ev.Start) {
+				t.Errorf("round trip start = %v, want %v", got, ev.Start)
+			}
+		})
+	}
+}
+
+func TestEncodeUntilInUTC(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	tests := []struct {
+		name string
+		rule func(t *testing.T) string
+		want string
+	}{
+		{
+			name: "parsed UTC until",
+			rule: func(t *testing.T) string {
+				r, err := recurrence.Parse("FREQ=WEEKLY;BYDAY=MO;UNTIL=20261231T235959Z")
+				if err != nil {
+					t.Fatalf("recurrence.Parse() error = %v", err)
+				}
+				return r.String()
+			},
+			want: "RRULE:FREQ=WEEKLY;UNTIL=20261231T235959Z;BYDAY=MO",
+		},
+		{
+			name: "local until is converted",
+			rule: func(t *testing.T) string {
+				return recurrence.Rule{
+					Freq:  recurrence.Weekly,
+					Until: time.Date(2026, 12, 31, 18, 0, 0, 0, ny),
+				}.String()
+			},
+			want: "RRULE:FREQ=WEEKLY;UNTIL=20261231T230000Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := baseEvent()
+			ev.Start = time.Date(2026, 3, 2, 9, 0, 0, 0, ny)
+			ev.RRule = tt.rule(t)
+			lines := contentLines((&Calendar{ProdID: "-//test//EN", Events: []Event{ev}}).Encode())
+
+			if !hasLine(lines, tt.want) {
+				t.Errorf("missing %s", tt.want)
+			}
+			if !hasLine(lines, "DTSTART;TZID=America/New_York:20260302T090000") {
+				t.Error("DTSTART is not written in the series zone")
+			}
+		})
+	}
+}
+
+func TestEncodeSequenceAndStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		sequence   int
+		status     string
+		wantSeq    string
+		wantStatus string
+	}{
+		{name: "new event", sequence: 0, wantSeq: "SEQUENCE:0", wantStatus: "STATUS:CONFIRMED"},
+		{name: "rescheduled", sequence: 2, status: StatusConfirmed, wantSeq: "SEQUENCE:2", wantStatus: "STATUS:CONFIRMED"},
+		{name: "cancelled tombstone", sequence: 3, status: StatusCancelled, wantSeq: "SEQUENCE:3", wantStatus: "STATUS:CANCELLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := baseEvent()
+			ev.Sequence = tt.sequence
+			ev.Status = tt.status
+			events := componentLines(contentLines((&Calendar{ProdID: "-//test//EN", Events: []Event{ev}}).Encode()), "VEVENT")
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			for _, want := range []string{"UID:m1@example.com", tt.wantSeq, tt.wantStatus} {
+				if !hasLine(events[0], want) {
+					t.Errorf("missing %s in %v", want, events[0])
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeRecurrenceOverrides(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	series := baseEvent()
+	series.UID = "series@example.com"
+	series.Start = time.Date(2026, 3, 2, 9, 0, 0, 0, ny)
+	series.Duration = 30 * time.Minute
+	series.RRule = "FREQ=WEEKLY;COUNT=4"
+	// Occurrence starts are stored in UTC and fall after the DST change on 8 March
+	series.ExDates = []time.Time{time.Date(2026, 3, 16, 13, 0, 0, 0, time.UTC)}
+
+	override := series
+	override.RRule = ""
+	override.ExDates = nil
+	override.Sequence = 1
+	override.RecurrenceID = time.Date(2026, 3, 9, 13, 0, 0, 0, time.UTC)
+	override.Start = time.Date(2026, 3, 9, 11, 0, 0, 0, ny)
+
+	tests := []struct {
+		name  string
+		event Event
+		want  []string
+		avoid []string
+	}{
+		{
+			name:  "series",
+			event: series,
+			want: []string{
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+				"EXDATE;TZID=America/New_York:20260316T090000",
+			},
+			avoid: []string{"RECURRENCE-ID"},
+		},
+		{
+			name:  "override",
+			event: override,
+			want: []string{
+				"UID:series@example.com",
+				"SEQUENCE:1",
+				"DTSTART;TZID=America/New_York:20260309T110000",
+				"RECURRENCE-ID;TZID=America/New_York:20260309T090000",
+			},
+			avoid: []string{"RRULE", "EXDATE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := componentLines(contentLines((&Calendar{ProdID: "-//test//EN", Events: []Event{tt.event}}).Encode()), "VEVENT")
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			for _, want := range tt.want {
+				if !hasLine(events[0], want) {
+					t.Errorf("missing %s in %v", want, events[0])
+				}
+			}
+			for _, line := range events[0] {
+				for _, prefix := range tt.avoid {
+					if strings.HasPrefix(line, prefix) {
+						t.Errorf("unexpected %s", line)
+					}
+				}
+			}
+		})
+	}
+
+	// Series and override share a UID and parse back to the same occurrence
+	cal, err := Parse(bytes.NewReader((&Calendar{ProdID: "-//test//EN", Events: []Event{series, override}}).Encode()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(cal.Events) != 2 {
+		t.Fatalf("got %d events, want 2", len(cal.Events))
+	}
+	if got := cal.Events[1].RecurrenceID; !got.Equal(override.RecurrenceID) {
+		t.Errorf("RECURRENCE-ID = %v, want %v", got, override.RecurrenceID)
+	}
+}
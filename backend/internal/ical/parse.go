@@ -0,0 +1,491 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateOnly = "20060102"
+
+// property is one unfolded content line
+type property struct {
+	line   int
+	name   string
+	params map[string]string
+	value  string
+}
+
+func (p property) param(name string) string {
+	return p.params[name]
+}
+
+// Parse reads an iCalendar stream and returns its events. Times with a TZID
+// are resolved through the IANA database, with Windows zone names mapped to
+// their IANA equivalent; other unknown TZIDs fall back to the standard offset
+// of the matching VTIMEZONE, and floating times are read as UTC. Components other than VEVENT and VTIMEZONE
+// are ignored.
+func Parse(r io.Reader) (*Calendar, error) {
+	props, err := readProperties(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{}
+	offsets := make(map[string]int)
+	var (
+		stack  []string
+		event  []property
+		tzid   string
+		events [][]property
+	)
+	for _, p := range props {
+		switch p.name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(p.value))
+			if len(stack) == 1 && stack[0] != "VCALENDAR" {
+				return nil, fmt.Errorf("ical: line %d: expected BEGIN:VCALENDAR", p.line)
+			}
+			if stack[len(stack)-1] == "VEVENT" {
+				event = nil
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("ical: line %d: unexpected END:%s", p.line, p.value)
+			}
+			switch stack[len(stack)-1] {
+			case "VEVENT":
+				events = append(events, event)
+			case "VTIMEZONE":
+				tzid = ""
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("ical: line %d: property outside VCALENDAR", p.line)
+		}
+
+		switch current := stack[len(stack)-1]; {
+		case current == "VCALENDAR":
+			switch p.name {
+			case "PRODID":
+				cal.ProdID = p.value
+			case "METHOD":
+				cal.Method = p.value
+			case "X-WR-CALNAME":
+				cal.Name = unescapeText(p.value)
+			}
+		case current == "VEVENT" && len(stack) == 2:
+			event = append(event, p)
+		case current == "VTIMEZONE" && p.name == "TZID":
+			tzid = normalizeTZID(p.value)
+		case current == "STANDARD" && p.name == "TZOFFSETTO" && tzid != "":
+			if _, seen := offsets[tzid]; !seen {
+				if offset, err := parseOffset(p.value); err == nil {
+					offsets[tzid] = offset
+				}
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("ical: missing END:%s", stack[len(stack)-1])
+	}
+	if len(props) == 0 {
+		return nil, errors.New("ical: empty calendar")
+	}
+
+	// Events are converted last because a VTIMEZONE may follow its events
+	zones := &zoneResolver{offsets: offsets}
+	for _, props := range events {
+		ev, err := parseEvent(props, zones)
+		if err != nil {
+			return nil, err
+		}
+		cal.Events = append(cal.Events, ev)
+	}
+	return cal, nil
+}
+
+func parseEvent(props []property, zones *zoneResolver) (Event, error) {
+	var (
+		ev       Event
+		end      time.Time
+		duration string
+		hasStart bool
+	)
+	for _, p := range props {
+		var err error
+		switch p.name {
+		case "UID":
+			ev.UID = strings.TrimSpace(p.value)
+		case "SEQUENCE":
+			ev.Sequence, _ = strconv.Atoi(strings.TrimSpace(p.value))
+		case "DTSTAMP":
+			ev.Stamp, _, err = zones.parseTime(p)
+		case "LAST-MODIFIED":
+			ev.LastModified, _, err = zones.parseTime(p)
+		case "DTSTART":
+			ev.Start, ev.AllDay, err = zones.parseTime(p)
+			hasStart = true
+		case "DTEND":
+			end, _, err = zones.parseTime(p)
+		case "DURATION":
+			duration = p.value
+		case "RECURRENCE-ID":
+			ev.RecurrenceID, _, err = zones.parseTime(p)
+		case "RRULE":
+			ev.RRule = strings.TrimSpace(p.value)
+		case "EXDATE":
+			for _, v := range strings.Split(p.value, ",") {
+				p.value = v
+				var ex time.Time
+				if ex, _, err = zones.parseTime(p); err != nil {
+					break
+				}
+				ev.ExDates = append(ev.ExDates, ex)
+			}
+		case "SUMMARY":
+			ev.Summary = unescapeText(p.value)
+		case "DESCRIPTION":
+			ev.Description = unescapeText(p.value)
+		case "LOCATION":
+			ev.Location = unescapeText(p.value)
+		case "URL":
+			ev.URL = strings.TrimSpace(p.value)
+		case "STATUS":
+			ev.Status = strings.ToUpper(strings.TrimSpace(p.value))
+		case "ORGANIZER":
+			if a, ok := parseAttendee(p); ok {
+				ev.Organizer = &a
+			}
+		case "ATTENDEE":
+			if a, ok := parseAttendee(p); ok {
+				ev.Attendees = append(ev.Attendees, a)
+			}
+		}
+		if err != nil {
+			return Event{}, fmt.Errorf("ical: line %d: %s: %w", p.line, p.name, err)
+		}
+	}
+
+	if !hasStart {
+		return ev, nil
+	}
+	switch {
+	case duration != "":
+		d, err := parseDuration(duration)
+		if err != nil {
+			return Event{}, fmt.Errorf("ical: event %q: DURATION: %w", ev.UID, err)
+		}
+		ev.Duration = d
+	case !end.IsZero():
+		ev.Duration = end.Sub(ev.Start)
+	case ev.AllDay:
+		ev.Duration = 24 * time.Hour
+	}
+	if ev.Duration < 0 {
+		return Event{}, fmt.Errorf("ical: event %q ends before it starts", ev.UID)
+	}
+	return ev, nil
+}
+
+// parseAttendee reads a mailto: calendar address; other address kinds are ignored
+func parseAttendee(p property) (Attendee, bool) {
+	value := strings.TrimSpace(p.value)
+	if len(value) < len("mailto:") || !strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return Attendee{}, false
+	}
+	email := strings.TrimSpace(value[len("mailto:"):])
+	if email == "" {
+		return Attendee{}, false
+	}
+	return Attendee{
+		Email:    email,
+		Name:     p.param("CN"),
+		PartStat: strings.ToUpper(p.param("PARTSTAT")),
+	}, true
+}
+
+// zoneResolver maps TZIDs to locations, caching lookups
+type zoneResolver struct {
+	offsets map[string]int
+	cache   map[string]*time.Location
+}
+
+func (z *zoneResolver) location(tzid string) (*time.Location, error) {
+	tzid = normalizeTZID(tzid)
+	if loc, ok := z.cache[tzid]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		if name, ok := windowsZones[tzid]; ok {
+			loc, err = time.LoadLocation(name)
+		}
+	}
+	if err != nil {
+		offset, ok := z.offsets[tzid]
+		if !ok {
+			return nil, fmt.Errorf("unknown time zone %q", tzid)
+		}
+		loc = time.FixedZone(tzid, offset)
+	}
+	if z.cache == nil {
+		z.cache = make(map[string]*time.Location)
+	}
+	z.cache[tzid] = loc
+	return loc, nil
+}
+
+// parseTime reads a DATE or DATE-TIME value and reports whether it was a DATE
+func (z *zoneResolver) parseTime(p property) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.value)
+	if strings.EqualFold(p.param("VALUE"), "DATE") || len(value) == len(dateOnly) {
+		t, err := time.ParseInLocation(dateOnly, value, time.UTC)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.ParseInLocation(dateTimeUTC, value, time.UTC)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := p.param("TZID"); tzid != "" {
+		var err error
+		if loc, err = z.location(tzid); err != nil {
+			return time.Time{}, false, err
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLocal, value, loc)
+	return t, false, err
+}
+
+// normalizeTZID strips the "/" prefix some producers use for globally unique TZIDs
+func normalizeTZID(tzid string) string {
+	tzid = strings.TrimSpace(tzid)
+	if strings.HasPrefix(tzid, "/") {
+		tzid = strings.TrimPrefix(tzid, "/")
+		// e.g. /mozilla.org/20050126_1/Europe/Berlin
+		if parts := strings.SplitN(tzid, "/", 3); len(parts) == 3 && strings.Contains(parts[0], ".") {
+			tzid = parts[2]
+		}
+	}
+	return tzid
+}
+
+// parseDuration reads an RFC 5545 duration such as PT1H30M, P1D or -PT15M
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var (
+		total  time.Duration
+		inTime bool
+		num    string
+	)
+	for _, r := range s[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			if inTime || num != "" {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		num = ""
+		var unit time.Duration
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n) * unit
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return sign * total, nil
+}
+
+// parseOffset reads a UTC offset such as +0100 or -043000 in seconds
+func parseOffset(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if (len(s) != 5 && len(s) != 7) || (s[0] != '+' && s[0] != '-') {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	var seconds int
+	if len(s) == 5 {
+		seconds = n/100*3600 + n%100*60
+	} else {
+		seconds = n/10000*3600 + n/100%100*60 + n%100
+	}
+	if s[0] == '-' {
+		seconds = -seconds
+	}
+	return seconds, nil
+}
+
+// readProperties unfolds content lines (RFC 5545 section 3.1) and splits them
+// into name, parameters and value
+func readProperties(r io.Reader) ([]property, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var (
+		props   []property
+		current strings.Builder
+		start   int
+		lineNo  int
+	)
+	flush := func() error {
+		if current.Len() == 0 {
+			return nil
+		}
+		p, err := parseProperty(current.String())
+		if err != nil {
+			return fmt.Errorf("ical: line %d: %w", start, err)
+		}
+		p.line = start
+		props = append(props, p)
+		current.Reset()
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			current.WriteString(line[1:])
+			continue
+		}
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		start = lineNo
+		current.WriteString(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func parseProperty(line string) (property, error) {
+	p := property{params: make(map[string]string)}
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, errors.New("malformed content line")
+	}
+	p.name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		rest := line[i+1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return p, errors.New("malformed parameter")
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				return p, errors.New("unterminated quoted parameter")
+			}
+			value = rest[1 : closing+1]
+			rest = rest[closing+2:]
+		} else {
+			end := strings.IndexAny(rest, ";:")
+			if end < 0 {
+				return p, errors.New("missing property value")
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+		if _, seen := p.params[name]; !seen {
+			p.params[name] = value
+		}
+
+		i = len(line) - len(rest)
+		if i >= len(line) {
+			return p, errors.New("missing property value")
+		}
+		if line[i] != ';' && line[i] != ':' {
+			// Additional comma-separated values of the same parameter are dropped
+			comma := strings.IndexAny(line[i:], ";:")
+			if comma < 0 {
+				return p, errors.New("missing property value")
+			}
+			i += comma
+		}
+	}
+
+	p.value = line[i+1:]
+	return p, nil
+}
+
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
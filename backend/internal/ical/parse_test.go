@@ -0,0 +1,210 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// outlookCalendar is shaped like an Exchange export: the TZID is a Windows
+// zone name and the VTIMEZONE carries both observances
+const outlookCalendar = `BEGIN:VCALENDAR
+PRODID:Microsoft Exchange Server 2010
+VERSION:2.0
+BEGIN:VTIMEZONE
+TZID:Pacific Standard Time
+BEGIN:STANDARD
+DTSTART:16010101T020000
+TZOFFSETFROM:-0700
+TZOFFSETTO:-0800
+RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=1SU;BYMONTH=11
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010101T020000
+TZOFFSETFROM:-0800
+TZOFFSETTO:-0700
+RRULE:FREQ=YEARLY;INTERVAL=1;BYDAY=2SU;BYMONTH=3
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:winter
+DTSTART;TZID=Pacific Standard Time:20260115T090000
+DTEND;TZID=Pacific Standard Time:20260115T100000
+SUMMARY:Winter
+END:VEVENT
+BEGIN:VEVENT
+UID:summer
+DTSTART;TZID=Pacific Standard Time:20260715T090000
+DTEND;TZID=Pacific Standard Time:20260715T100000
+SUMMARY:Summer
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParseWindowsTZID(t *testing.T) {
+	cal, err := Parse(strings.NewReader(strings.ReplaceAll(outlookCalendar, "\n", "\r\n")))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]time.Time{
+		"winter": time.Date(2026, 1, 15, 17, 0, 0, 0, time.UTC),
+		"summer": time.Date(2026, 7, 15, 16, 0, 0, 0, time.UTC),
+	}
+	if len(cal.Events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(cal.Events), len(want))
+	}
+	for _, ev := range cal.Events {
+		if !ev.Start.Equal(want[ev.UID]) {
+			t.Errorf("%s: start = %v, want %v", ev.UID, ev.Start.UTC(), want[ev.UID])
+		}
+		if got := ev.Start.Location().String(); got != "America/Los_Angeles" {
+			t.Errorf("%s: location = %q, want America/Los_Angeles", ev.UID, got)
+		}
+		if ev.Duration != time.Hour {
+			t.Errorf("%s: duration = %v, want 1h", ev.UID, ev.Duration)
+		}
+	}
+}
+
+func TestWindowsZonesLoad(t *testing.T) {
+	for windows, iana := range windowsZones {
+		if _, err := time.LoadLocation(iana); err != nil {
+			t.Errorf("%s: LoadLocation(%q) error = %v", windows, iana, err)
+		}
+	}
+}
+
+func parseEvents(t *testing.T, lines ...string) []Event {
+	t.Helper()
+	doc := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	cal, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return cal.Events
+}
+
+func TestParseUnfolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{name: "space continuation", lines: []string{"SUMMARY:Quarterly", "  planning"}, want: "Quarterly planning"},
+		{name: "tab continuation", lines: []string{"SUMMARY:Quarterly ", "\tplanning"}, want: "Quarterly planning"},
+		{name: "split inside a rune", lines: []string{"SUMMARY:caf\xc3", " \xa9 talk"}, want: "café talk"},
+		{name: "split inside the name", lines: []string{"SUMM", " ARY:Sync"}, want: "Sync"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VEVENT", "UID:u1", "DTSTART:20260302T090000Z"}, tt.lines...)
+			events := parseEvents(t, append(lines, "END:VEVENT")...)
+			if got := events[0].Summary; got != tt.want {
+				t.Errorf("Summary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Bare LF line endings are accepted too
+	cal, err := Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:u1\nSUMMARY:Long\n  title\nEND:VEVENT\nEND:VCALENDAR\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := cal.Events[0].Summary; got != "Long title" {
+		t.Errorf("Summary = %q, want %q", got, "Long title")
+	}
+}
+
+func TestParseQuotedParams(t *testing.T) {
+	events := parseEvents(t,
+		"BEGIN:VEVENT",
+		"UID:u1",
+		`DTSTART;TZID="America/New_York":20260302T090000`,
+		`ORGANIZER;CN="Lovelace, Ada";SENT-BY="mailto:x@example.com":mailto:ada@example.com`,
+		`ATTENDEE;CN="Hopper; Grace";PARTSTAT=accepted:MAILTO:grace@example.com`,
+		"END:VEVENT",
+	)
+	ev := events[0]
+
+	want := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	if !ev.Start.Equal(want) || ev.Start.Location().String() != "America/New_York" {
+		t.Errorf("Start = %v in %s, want %v in America/New_York", ev.Start, ev.Start.Location(), want)
+	}
+	if ev.Organizer == nil || ev.Organizer.Name != "Lovelace, Ada" || ev.Organizer.Email != "ada@example.com" {
+		t.Errorf("Organizer = %+v", ev.Organizer)
+	}
+	if len(ev.Attendees) != 1 {
+		t.Fatalf("got %d attendees, want 1", len(ev.Attendees))
+	}
+	if a := ev.Attendees[0]; a.Name != "Hopper; Grace" || a.Email != "grace@example.com" || a.PartStat != "ACCEPTED" {
+		t.Errorf("Attendee = %+v", a)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		allDay bool
+		want   time.Duration
+	}{
+		{name: "DTEND", lines: []string{"DTSTART:20260302T090000Z", "DTEND:20260302T103000Z"}, want: 90 * time.Minute},
+		{name: "DURATION", lines: []string{"DTSTART:20260302T090000Z", "DURATION:PT1H15M"}, want: 75 * time.Minute},
+		{name: "DURATION in days and weeks", lines: []string{"DTSTART:20260302T090000Z", "DURATION:P1W2D"}, want: 9 * 24 * time.Hour},
+		{name: "DURATION wins over DTEND", lines: []string{"DTSTART:20260302T090000Z", "DTEND:20260302T100000Z", "DURATION:PT30M"}, want: 30 * time.Minute},
+		{name: "DTEND across DST", lines: []string{"DTSTART;TZID=America/New_York:20260307T120000", "DTEND;TZID=America/New_York:20260308T120000"}, want: 23 * time.Hour},
+		{name: "all day without end", lines: []string{"DTSTART;VALUE=DATE:20260302"}, allDay: true, want: 24 * time.Hour},
+		{name: "all day with DTEND", lines: []string{"DTSTART;VALUE=DATE:20260302", "DTEND;VALUE=DATE:20260305"}, allDay: true, want: 72 * time.Hour},
+		{name: "no end", lines: []string{"DTSTART:20260302T090000Z"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"BEGIN:VEVENT", "UID:u1"}, tt.lines...)
+			ev := parseEvents(t, append(lines, "END:VEVENT")...)[0]
+			if ev.Duration != tt.want {
+				t.Errorf("Duration = %v, want %v", ev.Duration, tt.want)
+			}
+			if ev.AllDay != tt.allDay {
+				t.Errorf("AllDay = %v, want %v", ev.AllDay, tt.allDay)
+			}
+		})
+	}
+
+	doc := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:u1\r\nDTSTART:20260302T090000Z\r\nDTEND:20260302T080000Z\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	if _, err := Parse(strings.NewReader(doc)); err == nil {
+		t.Error("Parse() accepted an event that ends before it starts")
+	}
+}
+
+func TestParseExDates(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := parseEvents(t,
+		"BEGIN:VEVENT",
+		"UID:u1",
+		"DTSTART;TZID=America/New_York:20260302T090000",
+		"RRULE:FREQ=WEEKLY;COUNT=6",
+		"EXDATE;TZID=America/New_York:20260309T090000,20260316T090000",
+		"EXDATE:20260323T130000Z",
+		"END:VEVENT",
+	)
+	want := []time.Time{
+		time.Date(2026, 3, 9, 9, 0, 0, 0, ny),
+		time.Date(2026, 3, 16, 9, 0, 0, 0, ny),
+		time.Date(2026, 3, 23, 13, 0, 0, 0, time.UTC),
+	}
+	got := events[0].ExDates
+	if len(got) != len(want) {
+		t.Fatalf("got %d EXDATEs, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("EXDATE %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
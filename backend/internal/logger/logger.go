@@ -1,13 +1,115 @@
 package logger
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
 )
 
-func New(env string) *log.Logger {
-	flag := log.LstdFlags | log.Lmicroseconds
-	prefix := "[" + env + "] "
-	return log.New(os.Stdout, prefix, flag)
+// Logger is the logging interface shared by every package. *slog.Logger satisfies it.
+// Prefer the *Context variants inside request handling so log lines carry the
+// request correlation fields (request id, user id, ...).
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	DebugContext(ctx context.Context, msg string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
 }
 
+// New builds the application logger. Production emits JSON, other environments
+// emit human-readable text. Level is one of debug, info, warn or error.
+func New(env, level string) (*slog.Logger, error) {
+	return NewWithWriter(os.Stdout, env, level)
+}
+
+// NewWithWriter is New with an explicit output
+func NewWithWriter(w io.Writer, env, level string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	if env == "production" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}).With(slog.String("env", env)), nil
+}
+
+// ParseLevel converts a LOG_LEVEL value into a slog level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q", level)
+	}
+}
+
+type attrsKey struct{}
+
+// requestAttrs is a mutable bag of attributes shared by everything derived from
+// one request context. It is mutable so that inner middleware (e.g. auth) can
+// enrich lines logged by outer middleware (e.g. the access log) after the fact.
+type requestAttrs struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// WithAttrs installs a request-scoped attribute bag seeded with attrs
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, attrsKey{}, &requestAttrs{attrs: attrs})
+}
+
+// AddAttrs appends attributes to the request-scoped bag, if one is installed
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	bag, ok := ctx.Value(attrsKey{}).(*requestAttrs)
+	if !ok {
+		return
+	}
+	bag.mu.Lock()
+	bag.attrs = append(bag.attrs, attrs...)
+	bag.mu.Unlock()
+}
+
+// contextHandler adds the request-scoped attributes found in the context to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if bag, ok := ctx.Value(attrsKey{}).(*requestAttrs); ok {
+			bag.mu.Lock()
+			record.AddAttrs(bag.attrs...)
+			bag.mu.Unlock()
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
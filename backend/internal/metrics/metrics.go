@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "aicomp"
+
+// Registry holds every collector exposed on /metrics. A dedicated registry (rather
+// than the global default) keeps the output limited to what we register here.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration is labeled by chi route pattern, never the raw path, to bound cardinality
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	})
+
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejections_total",
+		Help:      "Requests rejected by the rate limiter by endpoint and strategy.",
+	}, []string{"endpoint", "strategy"})
+
+	MeetingsStarted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "meetings",
+		Name:      "started_total",
+		Help:      "Meetings transitioned to active.",
+	})
+
+	MeetingsJoined = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "meetings",
+		Name:      "joined_total",
+		Help:      "Successful meeting joins.",
+	})
+
+	MeetingsEnded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "meetings",
+		Name:      "ended_total",
+		Help:      "Meetings transitioned to ended.",
+	})
+
+	Logins = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "Successful password logins.",
+	})
+
+	// LoginFailures reason is one of invalid_credentials or locked
+	LoginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "login_failures_total",
+		Help:      "Failed password logins by reason.",
+	}, []string{"reason"})
+
+	RefreshRotations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "refresh_rotations_total",
+		Help:      "Refresh tokens rotated.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		RateLimitRejections,
+		MeetingsStarted,
+		MeetingsJoined,
+		MeetingsEnded,
+		Logins,
+		LoginFailures,
+		RefreshRotations,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterPostgresPool exposes pgxpool statistics
+func RegisterPostgresPool(pool *pgxpool.Pool) error {
+	return Registry.Register(newPoolCollector(pool))
+}
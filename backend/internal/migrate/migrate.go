@@ -2,42 +2,328 @@ package migrate
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const migrationsPath = "sql"
 
+// advisoryLockID serializes migration runs across replicas booting at once
+const advisoryLockID int64 = 7310425118
+
+const downSuffix = ".down.sql"
+
+// migration is one embedded NNNN_name.sql file with its optional
+// NNNN_name.down.sql counterpart
+type migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes an embedded migration and whether it has been applied
+type Status struct {
+	Version int
+	Name    string
+	Applied bool
+	HasDown bool
+}
+
+// Run applies every pending migration in version order. Each migration runs
+// once in its own transaction and is recorded in schema_migrations together
+// with a checksum; editing a file after it was applied fails the run.
 func Run(ctx context.Context, pool *pgxpool.Pool) error {
+	migrations, err := load()
+	if err != nil {
+		return err
+	}
+
+	return withLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		applied, err := appliedChecksums(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if checksum, ok := applied[m.Version]; ok {
+				if checksum != m.Checksum {
+					return fmt.Errorf("migration %04d_%s was modified after it was applied (checksum %s, expected %s)",
+						m.Version, m.Name, m.Checksum, checksum)
+				}
+				continue
+			}
+
+			if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `
+					INSERT INTO schema_migrations (version, name, checksum)
+					VALUES ($1, $2, $3)`, m.Version, m.Name, m.Checksum)
+				return err
+			}); err != nil {
+				return fmt.Errorf("execute migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down rolls back the most recently applied migrations, newest first.
+// It stops with an error at the first migration that has no down file.
+func Down(ctx context.Context, pool *pgxpool.Pool, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+
+	migrations, err := load()
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	return withLock(ctx, pool, func(conn *pgxpool.Conn) error {
+		rows, err := conn.Query(ctx, `
+			SELECT version
+			  FROM schema_migrations
+			 ORDER BY version DESC
+			 LIMIT $1`, steps)
+		if err != nil {
+			return fmt.Errorf("list applied migrations: %w", err)
+		}
+		versions, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("list applied migrations: %w", err)
+		}
+
+		for _, version := range versions {
+			m, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("migration %04d is applied but not embedded in this binary", version)
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migration %04d_%s has no down migration", m.Version, m.Name)
+			}
+
+			if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, m.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("roll back migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CurrentVersion returns the highest applied migration version, or 0 when
+// nothing has been applied yet
+func CurrentVersion(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	if pool == nil {
+		return 0, fmt.Errorf("postgres pool is nil")
+	}
+
+	var version int
+	err := pool.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		// 42P01: schema_migrations does not exist yet
+		if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// LatestVersion returns the version of the newest embedded migration
+func LatestVersion() (int, error) {
+	migrations, err := load()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// List reports every embedded migration and whether it has been applied
+func List(ctx context.Context, pool *pgxpool.Pool) ([]Status, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("postgres pool is nil")
+	}
+
+	migrations, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := CurrentVersion(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int]bool{}
+	if current > 0 {
+		rows, err := pool.Query(ctx, `SELECT version FROM schema_migrations`)
+		if err != nil {
+			return nil, fmt.Errorf("list applied migrations: %w", err)
+		}
+		versions, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return nil, fmt.Errorf("list applied migrations: %w", err)
+		}
+		for _, version := range versions {
+			applied[version] = true
+		}
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, Status{
+			Version: m.Version,
+			Name:    m.Name,
+			Applied: applied[m.Version],
+			HasDown: m.Down != "",
+		})
+	}
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+// Session-level locks belong to a connection, so the lock, the migrations and
+// the unlock must all use the same one.
+func withLock(ctx context.Context, pool *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
 	if pool == nil {
 		return fmt.Errorf("postgres pool is nil")
 	}
 
-	entries, err := migrationsFS.ReadDir(migrationsPath)
+	conn, err := pool.Acquire(ctx)
 	if err != nil {
-		return fmt.Errorf("read migrations: %w", err)
+		return fmt.Errorf("acquire connection: %w", err)
 	}
+	defer conn.Release()
 
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Name() < entries[j].Name()
-	})
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was cancelled
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockID)
+	}()
 
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version     INTEGER PRIMARY KEY,
+			name        TEXT NOT NULL,
+			checksum    TEXT NOT NULL,
+			applied_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func appliedChecksums(ctx context.Context, conn *pgxpool.Conn) (map[int]string, error) {
+	rows, err := conn.Query(ctx, `SELECT version, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]string{}
+	for rows.Next() {
+		var (
+			version  int
+			checksum string
+		)
+		if err := rows.Scan(&version, &checksum); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[version] = checksum
+	}
+	return applied, rows.Err()
+}
+
+// load reads and pairs the embedded migration files, sorted by version
+func load() ([]migration, error) {
+	entries, err := migrationsFS.ReadDir(migrationsPath)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := map[int]*migration{}
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 
-		sqlBytes, err := migrationsFS.ReadFile(fmt.Sprintf("%s/%s", migrationsPath, entry.Name()))
+		fileName := entry.Name()
+		isDown := strings.HasSuffix(fileName, downSuffix)
+		base := strings.TrimSuffix(strings.TrimSuffix(fileName, downSuffix), ".sql")
+
+		prefix, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s has no version prefix", fileName)
+		}
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s has invalid version prefix", fileName)
+		}
+
+		sqlBytes, err := migrationsFS.ReadFile(fmt.Sprintf("%s/%s", migrationsPath, fileName))
 		if err != nil {
-			return fmt.Errorf("read migration %s: %w", entry.Name(), err)
+			return nil, fmt.Errorf("read migration %s: %w", fileName, err)
 		}
 
-		if _, err := pool.Exec(ctx, string(sqlBytes)); err != nil {
-			return fmt.Errorf("execute migration %s: %w", entry.Name(), err)
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %04d is used by both %q and %q", version, m.Name, name)
 		}
+
+		if isDown {
+			m.Down = string(sqlBytes)
+			continue
+		}
+		if m.Up != "" {
+			return nil, fmt.Errorf("duplicate migration %s", fileName)
+		}
+		sum := sha256.Sum256(sqlBytes)
+		m.Up = string(sqlBytes)
+		m.Checksum = hex.EncodeToString(sum[:])
 	}
 
-	return nil
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has a down file but no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
 }
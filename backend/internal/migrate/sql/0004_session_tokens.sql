@@ -1,3 +1,6 @@
+-- 0004_session_tokens.sql
+-- Refresh token storage for cookie-based sessions
+
 CREATE TABLE IF NOT EXISTS session_tokens (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
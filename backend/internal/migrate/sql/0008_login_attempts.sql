@@ -0,0 +1,22 @@
+-- 0008_login_attempts.sql
+-- Per-account failed login tracking (used when Redis is not configured) and unlock tokens
+
+-- Keyed by normalized email rather than user id so that unknown accounts are
+-- throttled exactly like real ones and lockout responses do not leak existence.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    identifier      TEXT PRIMARY KEY,
+    failed_count    INTEGER NOT NULL DEFAULT 0,
+    last_failed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS account_unlock_tokens (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email       TEXT NOT NULL,
+    token_hash  TEXT NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS account_unlock_tokens_email_idx ON account_unlock_tokens (email);
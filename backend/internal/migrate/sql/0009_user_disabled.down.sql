@@ -0,0 +1,2 @@
+ALTER TABLE app_users
+    DROP COLUMN IF EXISTS disabled_at;
@@ -0,0 +1,5 @@
+-- 0009_user_disabled.sql
+-- Allow operators to disable accounts without deleting their data
+
+ALTER TABLE app_users
+    ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS meeting_recurrence_exceptions;
+
+-- Stored occurrences become standalone meetings; the series templates go away
+UPDATE meetings SET series_id = NULL WHERE series_id IS NOT NULL;
+DELETE FROM meetings WHERE recurrence_rule IS NOT NULL;
+
+DROP INDEX IF EXISTS meetings_series_occurrence_idx;
+
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS occurrence_start,
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS recurrence_tz,
+    DROP COLUMN IF EXISTS recurrence_rule;
@@ -0,0 +1,23 @@
+-- 0010_meeting_recurrence.sql
+-- Recurring meetings. A series is a meetings row with status 'series' that
+-- carries an RFC 5545 RRULE; its start_time is the series DTSTART. Occurrences
+-- are expanded on read and only stored, as rows pointing back at the series,
+-- once they get a lifecycle of their own (started, joined or edited).
+
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS recurrence_rule TEXT,
+    ADD COLUMN IF NOT EXISTS recurrence_tz TEXT,
+    ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES meetings(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS occurrence_start TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS meetings_series_occurrence_idx
+    ON meetings (series_id, occurrence_start)
+    WHERE series_id IS NOT NULL;
+
+-- Cancelled occurrences (EXDATE)
+CREATE TABLE IF NOT EXISTS meeting_recurrence_exceptions (
+    series_id        UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    occurrence_start TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (series_id, occurrence_start)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS meeting_cancellations;
+DROP TABLE IF EXISTS calendar_feed_tokens;
+
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS ics_sequence;
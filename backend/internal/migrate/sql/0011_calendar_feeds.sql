@@ -0,0 +1,29 @@
+-- 0011_calendar_feeds.sql
+-- iCalendar subscriptions: per-user secret feed tokens, event sequence numbers
+-- and tombstones so subscribed calendars learn about cancellations
+
+-- Bumped on every change a calendar client should pick up (RFC 5545 SEQUENCE)
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS ics_sequence INTEGER NOT NULL DEFAULT 0;
+
+-- Only a hash of the token is stored; rotating replaces the row
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    user_id    UUID PRIMARY KEY REFERENCES app_users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Deleted meetings stay in feeds as STATUS:CANCELLED for a while
+CREATE TABLE IF NOT EXISTS meeting_cancellations (
+    meeting_slug     TEXT PRIMARY KEY,
+    host_user_id     UUID REFERENCES app_users(id) ON DELETE CASCADE,
+    invitee_user_ids UUID[] NOT NULL DEFAULT '{}',
+    invitee_emails   TEXT[] NOT NULL DEFAULT '{}',
+    title            TEXT NOT NULL,
+    start_time       TIMESTAMPTZ NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    ics_sequence     INTEGER NOT NULL,
+    cancelled_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS meeting_cancellations_cancelled_idx ON meeting_cancellations (cancelled_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS meetings_host_ical_uid_idx;
+
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS ical_uid;
@@ -0,0 +1,9 @@
+-- 0012_meeting_ical_uid.sql
+-- Remember the iCalendar UID of imported meetings so re-imports update them
+
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS ical_uid TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS meetings_host_ical_uid_idx
+    ON meetings (host_user_id, ical_uid)
+    WHERE ical_uid IS NOT NULL;
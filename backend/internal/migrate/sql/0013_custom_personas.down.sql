@@ -0,0 +1,20 @@
+ALTER TABLE meetings
+    DROP CONSTRAINT IF EXISTS meetings_ai_persona_id_fkey;
+
+UPDATE meetings
+   SET ai_persona_id = NULL
+ WHERE ai_persona_id IN (SELECT id FROM ai_personas WHERE owner_user_id IS NOT NULL);
+
+ALTER TABLE user_preferences
+    DROP COLUMN IF EXISTS default_persona_id;
+
+DELETE FROM ai_personas WHERE owner_user_id IS NOT NULL;
+
+ALTER TABLE ai_personas
+    DROP COLUMN IF EXISTS instructions,
+    DROP COLUMN IF EXISTS voice_preset_id,
+    DROP COLUMN IF EXISTS owner_user_id;
+
+ALTER TABLE meetings
+    ADD CONSTRAINT meetings_ai_persona_id_fkey
+        FOREIGN KEY (ai_persona_id) REFERENCES ai_personas(id);
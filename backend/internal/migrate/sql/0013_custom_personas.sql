@@ -0,0 +1,25 @@
+-- 0013_custom_personas.sql
+-- User-owned AI personas. Personas without an owner are built in and
+-- available to everyone.
+
+ALTER TABLE ai_personas
+    ADD COLUMN IF NOT EXISTS owner_user_id   UUID REFERENCES app_users(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS voice_preset_id UUID REFERENCES voice_presets(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS instructions    TEXT;
+
+-- Meetings default to this persona, so it must exist even without seed data
+INSERT INTO ai_personas (id, name, voice_preset, tone, energy, description)
+VALUES ('aurora', 'Aurora', 'Evelyn · Warm Alto', 'Warm', 'Balanced', 'Adaptive AI co-host tuned for coaching and focus sessions.')
+ON CONFLICT (id) DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS ai_personas_owner_idx ON ai_personas (owner_user_id);
+
+ALTER TABLE user_preferences
+    ADD COLUMN IF NOT EXISTS default_persona_id TEXT REFERENCES ai_personas(id) ON DELETE SET NULL;
+
+-- Past meetings keep working when their persona is deleted; readers fall
+-- back to the built-in persona
+ALTER TABLE meetings
+    DROP CONSTRAINT IF EXISTS meetings_ai_persona_id_fkey,
+    ADD CONSTRAINT meetings_ai_persona_id_fkey
+        FOREIGN KEY (ai_persona_id) REFERENCES ai_personas(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS meeting_template_agenda_items;
+DROP TABLE IF EXISTS meeting_templates;
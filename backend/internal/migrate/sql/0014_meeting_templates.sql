@@ -0,0 +1,57 @@
+-- 0014_meeting_templates.sql
+-- Reusable meeting templates. Templates without an owner are built in;
+-- user templates are private or shared with the whole workspace.
+
+CREATE TABLE IF NOT EXISTS meeting_templates (
+    id               TEXT PRIMARY KEY,
+    owner_user_id    UUID REFERENCES app_users(id) ON DELETE CASCADE,
+    title            TEXT NOT NULL,
+    description      TEXT,
+    badge            TEXT,
+    duration_minutes INTEGER NOT NULL DEFAULT 30,
+    ai_persona_id    TEXT REFERENCES ai_personas(id) ON DELETE SET NULL,
+    voice_profile    TEXT,
+    visibility       TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'workspace')),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS meeting_templates_owner_idx ON meeting_templates (owner_user_id);
+
+CREATE TABLE IF NOT EXISTS meeting_template_agenda_items (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id      TEXT NOT NULL REFERENCES meeting_templates(id) ON DELETE CASCADE,
+    order_index      INTEGER NOT NULL,
+    title            TEXT NOT NULL,
+    description      TEXT,
+    duration_minutes INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS meeting_template_agenda_order_idx ON meeting_template_agenda_items (template_id, order_index);
+
+-- The quick-start templates previously hard-coded in the meetings list
+INSERT INTO meeting_templates (id, title, description, badge, duration_minutes, ai_persona_id, visibility)
+VALUES
+    ('template-retro', 'Retro reboot', 'Re-energize your retros with AI prompts.', 'Popular', 45, 'aurora', 'workspace'),
+    ('template-product', 'Product narrative', 'Pressure-test the story before investor updates.', 'AI guided', 30, 'aurora', 'workspace'),
+    ('template-onboarding', 'Onboarding accelerator', 'Give new hires a personal AI co-pilot.', 'New', 60, 'aurora', 'workspace')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO meeting_template_agenda_items (template_id, order_index, title, description, duration_minutes)
+SELECT v.template_id, v.order_index, v.title, v.description, v.duration_minutes
+  FROM (VALUES
+    ('template-retro', 1, 'Check-in', 'One word on how the last sprint felt.', 5),
+    ('template-retro', 2, 'What went well', 'Wins worth repeating.', 10),
+    ('template-retro', 3, 'What to improve', 'Friction, blockers and surprises.', 15),
+    ('template-retro', 4, 'Action items', 'Owners and due dates for the next sprint.', 15),
+    ('template-product', 1, 'The story', 'Walk through the narrative end to end.', 10),
+    ('template-product', 2, 'Pressure test', 'The AI co-host plays a sceptical investor.', 15),
+    ('template-product', 3, 'Next revision', 'Agree what changes before the update goes out.', 5),
+    ('template-onboarding', 1, 'Welcome', 'Introductions and goals for the first weeks.', 10),
+    ('template-onboarding', 2, 'Tools and access', 'Accounts, repositories and where to ask for help.', 20),
+    ('template-onboarding', 3, 'First tasks', 'Pick a starter project with a buddy.', 20),
+    ('template-onboarding', 4, 'Questions', 'Open floor.', 10)
+  ) AS v (template_id, order_index, title, description, duration_minutes)
+ WHERE NOT EXISTS (
+    SELECT 1 FROM meeting_template_agenda_items a WHERE a.template_id = v.template_id
+ );
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS meeting_notes;
+DROP TABLE IF EXISTS meeting_resources;
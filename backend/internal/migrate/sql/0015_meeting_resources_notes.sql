@@ -0,0 +1,25 @@
+-- 0015_meeting_resources_notes.sql
+-- Links and host notes attached to meetings
+
+-- The id is kept when an occurrence of a recurring meeting copies the
+-- series' resources, so clients can keep addressing them by the same id
+CREATE TABLE IF NOT EXISTS meeting_resources (
+    meeting_id  UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    id          UUID NOT NULL DEFAULT gen_random_uuid(),
+    order_index INTEGER NOT NULL,
+    title       TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    type        TEXT NOT NULL DEFAULT 'link',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (meeting_id, id)
+);
+
+CREATE INDEX IF NOT EXISTS meeting_resources_order_idx ON meeting_resources (meeting_id, order_index);
+
+CREATE TABLE IF NOT EXISTS meeting_notes (
+    meeting_id UUID PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    content    TEXT NOT NULL,
+    updated_by UUID REFERENCES app_users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
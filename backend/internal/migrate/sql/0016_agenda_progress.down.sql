@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS meeting_agenda_current_idx;
+
+-- Items copied between meetings share ids; give them new ones
+UPDATE meeting_agenda_items a
+   SET id = gen_random_uuid()
+ WHERE EXISTS (
+    SELECT 1
+      FROM meeting_agenda_items b
+     WHERE b.id = a.id
+       AND b.meeting_id <> a.meeting_id
+ );
+
+ALTER TABLE meeting_agenda_items
+    DROP CONSTRAINT IF EXISTS meeting_agenda_items_pkey,
+    ADD PRIMARY KEY (id);
+
+ALTER TABLE meeting_agenda_items
+    DROP CONSTRAINT IF EXISTS meeting_agenda_items_status_check,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS status;
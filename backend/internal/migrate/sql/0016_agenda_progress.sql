@@ -0,0 +1,21 @@
+-- 0016_agenda_progress.sql
+-- Live agenda progress. Items are keyed per meeting so that occurrences of a
+-- recurring meeting can copy the series' agenda and keep the item ids.
+
+ALTER TABLE meeting_agenda_items
+    ADD COLUMN IF NOT EXISTS status       TEXT NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS started_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+ALTER TABLE meeting_agenda_items
+    DROP CONSTRAINT IF EXISTS meeting_agenda_items_status_check,
+    ADD CONSTRAINT meeting_agenda_items_status_check
+        CHECK (status IN ('pending', 'current', 'completed'));
+
+ALTER TABLE meeting_agenda_items
+    DROP CONSTRAINT IF EXISTS meeting_agenda_items_pkey,
+    ADD PRIMARY KEY (meeting_id, id);
+
+-- At most one item is being discussed at a time
+CREATE UNIQUE INDEX IF NOT EXISTS meeting_agenda_current_idx
+    ON meeting_agenda_items (meeting_id) WHERE status = 'current';
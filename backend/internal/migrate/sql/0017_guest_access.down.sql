@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS meeting_guests;
+DROP TABLE IF EXISTS meeting_guest_links;
+
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS guest_approval_required,
+    DROP COLUMN IF EXISTS locked;
@@ -0,0 +1,28 @@
+-- 0017_guest_access.sql
+-- Guest join links for public meetings and the host's access controls
+
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS locked                  BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS guest_approval_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One shareable link per meeting; only a hash of the token is stored
+CREATE TABLE IF NOT EXISTS meeting_guest_links (
+    meeting_id UUID PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_by UUID REFERENCES app_users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Short-lived identities handed to unauthenticated guests
+CREATE TABLE IF NOT EXISTS meeting_guests (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    meeting_id   UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    display_name TEXT NOT NULL,
+    token_hash   TEXT NOT NULL UNIQUE,
+    status       TEXT NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'admitted', 'denied')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at   TIMESTAMPTZ,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS meeting_guests_meeting_idx ON meeting_guests (meeting_id, created_at);
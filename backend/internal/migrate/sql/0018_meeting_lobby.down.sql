@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS meeting_participants_user_idx;
+
+ALTER TABLE meeting_participants
+    DROP CONSTRAINT IF EXISTS meeting_participants_admission_status_check,
+    DROP COLUMN IF EXISTS decided_by,
+    DROP COLUMN IF EXISTS decided_at,
+    DROP COLUMN IF EXISTS requested_at,
+    DROP COLUMN IF EXISTS admission_status;
+
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS lobby_enabled;
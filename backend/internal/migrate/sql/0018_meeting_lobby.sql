@@ -0,0 +1,27 @@
+-- 0018_meeting_lobby.sql
+-- Lobby mode: participants wait until the host admits them. Requests and the
+-- host's decisions are kept on the participant row.
+
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS lobby_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE meeting_participants
+    ADD COLUMN IF NOT EXISTS admission_status TEXT,
+    ADD COLUMN IF NOT EXISTS requested_at     TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS decided_at       TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS decided_by       UUID REFERENCES app_users(id) ON DELETE SET NULL;
+
+ALTER TABLE meeting_participants
+    DROP CONSTRAINT IF EXISTS meeting_participants_admission_status_check,
+    ADD CONSTRAINT meeting_participants_admission_status_check
+        CHECK (admission_status IN ('waiting', 'admitted', 'denied'));
+
+-- A user appears once per meeting; keep the first row of any duplicates
+DELETE FROM meeting_participants a
+ USING meeting_participants b
+ WHERE a.meeting_id = b.meeting_id
+   AND a.user_id = b.user_id
+   AND a.display_name > b.display_name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS meeting_participants_user_idx
+    ON meeting_participants (meeting_id, user_id) WHERE user_id IS NOT NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS ended_at;
+
+ALTER TABLE meeting_participants
+    DROP CONSTRAINT IF EXISTS meeting_participants_role_check;
+
+UPDATE meeting_participants
+   SET role = CASE role
+       WHEN 'host' THEN 'Host'
+       WHEN 'co_host' THEN 'Co-host'
+       WHEN 'presenter' THEN 'Presenter'
+       ELSE 'Participant'
+   END;
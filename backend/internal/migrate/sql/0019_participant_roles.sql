@@ -0,0 +1,23 @@
+-- 0019_participant_roles.sql
+-- Participant roles were free text ("Host", job titles). They now pick a
+-- permission set: host, co_host, presenter or viewer. The meeting's host
+-- stays authoritative in meetings.host_user_id.
+
+UPDATE meeting_participants p
+   SET role = CASE
+       WHEN p.user_id IS NOT NULL AND p.user_id = m.host_user_id THEN 'host'
+       WHEN LOWER(p.role) IN ('co-host', 'cohost', 'co_host') THEN 'co_host'
+       WHEN LOWER(p.role) = 'presenter' THEN 'presenter'
+       ELSE 'viewer'
+   END
+  FROM meetings m
+ WHERE m.id = p.meeting_id;
+
+ALTER TABLE meeting_participants
+    DROP CONSTRAINT IF EXISTS meeting_participants_role_check,
+    ADD CONSTRAINT meeting_participants_role_check
+        CHECK (role IN ('host', 'co_host', 'presenter', 'viewer'));
+
+-- Set when a meeting is ended
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS ended_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS transcript_sections_meeting_event ON transcript_sections;
+DROP FUNCTION IF EXISTS log_transcript_section_event();
+DROP TABLE IF EXISTS meeting_events;
@@ -0,0 +1,39 @@
+-- 0020_meeting_events.sql
+-- Append-only log behind the meeting event stream. Ids are increasing, so a
+-- client that reconnects resumes after the last id it received.
+
+CREATE TABLE IF NOT EXISTS meeting_events (
+    id          BIGSERIAL PRIMARY KEY,
+    meeting_id  UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    type        TEXT NOT NULL,
+    data        JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS meeting_events_meeting_idx ON meeting_events (meeting_id, id);
+
+-- Transcript sections are written by the transcription pipeline rather than
+-- through the API, so they are logged by a trigger
+CREATE OR REPLACE FUNCTION log_transcript_section_event() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO meeting_events (meeting_id, type, data)
+    SELECT s.meeting_id,
+           'transcript.section',
+           jsonb_build_object(
+               'transcriptId', t.id,
+               'timestampMs', NEW.timestamp_ms,
+               'speaker', NEW.speaker,
+               'text', NEW.text
+           )
+      FROM transcripts t
+      JOIN sessions s ON s.id = t.session_id
+     WHERE t.id = NEW.transcript_id
+       AND s.meeting_id IS NOT NULL;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS transcript_sections_meeting_event ON transcript_sections;
+CREATE TRIGGER transcript_sections_meeting_event
+    AFTER INSERT ON transcript_sections
+    FOR EACH ROW EXECUTE FUNCTION log_transcript_section_event();
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS meetings_open_start_idx;
+
+UPDATE meetings SET status = 'scheduled' WHERE status = 'missed';
+UPDATE meetings SET status = 'instant' WHERE status = 'expired';
+
+ALTER TABLE meetings
+    DROP COLUMN IF EXISTS reminder_sent_at;
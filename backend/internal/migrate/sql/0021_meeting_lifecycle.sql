@@ -0,0 +1,11 @@
+-- 0021_meeting_lifecycle.sql
+-- The lifecycle worker closes meetings that never started ('missed' and
+-- 'expired'), ends overrunning ones and sends start reminders once.
+
+ALTER TABLE meetings
+    ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMPTZ;
+
+-- The worker only looks at meetings that are still open
+CREATE INDEX IF NOT EXISTS meetings_open_start_idx
+    ON meetings (start_time)
+    WHERE status IN ('scheduled', 'instant', 'active');
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS transcripts_unnotified_idx;
+
+ALTER TABLE transcripts
+    DROP COLUMN IF EXISTS notified_at;
+
+DROP TABLE IF EXISTS notification_webhooks;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,46 @@
+-- 0022_notifications.sql
+-- Notifications go out over channels (in-app inbox, email, webhook). Users
+-- choose channels per notification type; user_preferences.notifications_enabled
+-- stays the master switch.
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    type        TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    body        TEXT NOT NULL DEFAULT '',
+    link        TEXT NOT NULL DEFAULT '',
+    meeting_id  UUID REFERENCES meetings(id) ON DELETE SET NULL,
+    read_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Missing rows mean the channel is enabled
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id     UUID NOT NULL REFERENCES app_users(id) ON DELETE CASCADE,
+    type        TEXT NOT NULL,
+    channel     TEXT NOT NULL,
+    enabled     BOOLEAN NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, type, channel)
+);
+
+CREATE TABLE IF NOT EXISTS notification_webhooks (
+    user_id     UUID PRIMARY KEY REFERENCES app_users(id) ON DELETE CASCADE,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Transcripts are announced once by the lifecycle worker. Existing ones are
+-- not announced after the fact.
+ALTER TABLE transcripts
+    ADD COLUMN IF NOT EXISTS notified_at TIMESTAMPTZ;
+
+UPDATE transcripts SET notified_at = created_at WHERE notified_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS transcripts_unnotified_idx ON transcripts (created_at) WHERE notified_at IS NULL;
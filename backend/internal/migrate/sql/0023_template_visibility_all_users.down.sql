@@ -0,0 +1,7 @@
+ALTER TABLE meeting_templates
+    DROP CONSTRAINT IF EXISTS meeting_templates_visibility_check;
+
+UPDATE meeting_templates SET visibility = 'workspace' WHERE visibility = 'all_users';
+
+ALTER TABLE meeting_templates
+    ADD CONSTRAINT meeting_templates_visibility_check CHECK (visibility IN ('private', 'workspace'));
@@ -0,0 +1,11 @@
+-- 0023_template_visibility_all_users.sql
+-- There is no workspace or team model, so 'workspace' templates were shown to
+-- every account. Rename the value to say so.
+
+ALTER TABLE meeting_templates
+    DROP CONSTRAINT IF EXISTS meeting_templates_visibility_check;
+
+UPDATE meeting_templates SET visibility = 'all_users' WHERE visibility = 'workspace';
+
+ALTER TABLE meeting_templates
+    ADD CONSTRAINT meeting_templates_visibility_check CHECK (visibility IN ('private', 'all_users'));
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS meeting_events_created_idx;
+DROP TRIGGER IF EXISTS meeting_events_commit_order ON meeting_events;
+DROP FUNCTION IF EXISTS order_meeting_event();
@@ -0,0 +1,23 @@
+-- 0024_meeting_events_commit_order.sql
+-- Streams resume after the last event id they sent, so a meeting's ids must
+-- become visible in order. Events are often written inside longer
+-- transactions, where a default id taken at insert can commit after a higher
+-- one. The trigger locks the meeting row first and only then takes the id:
+-- writers of one meeting's events are serialized until commit, so a lower id
+-- is always committed before a higher one is handed out.
+
+CREATE OR REPLACE FUNCTION order_meeting_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM 1 FROM meetings WHERE id = NEW.meeting_id FOR NO KEY UPDATE;
+    NEW.id := nextval('meeting_events_id_seq');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS meeting_events_commit_order ON meeting_events;
+CREATE TRIGGER meeting_events_commit_order
+    BEFORE INSERT ON meeting_events
+    FOR EACH ROW EXECUTE FUNCTION order_meeting_event();
+
+-- Old events are purged by the lifecycle worker
+CREATE INDEX IF NOT EXISTS meeting_events_created_idx ON meeting_events (created_at);
@@ -0,0 +1 @@
+-- Nothing to restore: schema_version is no longer read
@@ -0,0 +1,5 @@
+-- 0025_drop_schema_version.sql
+-- A short-lived build recorded the applied version in a single-row
+-- schema_version table. schema_migrations has tracked every migration since,
+-- so databases that ran that build drop the leftover table.
+DROP TABLE IF EXISTS schema_version;
@@ -0,0 +1,422 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules used
+// for repeating meetings: DAILY, WEEKLY and MONTHLY frequencies with INTERVAL,
+// COUNT or UNTIL, BYDAY and BYMONTHDAY.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxPeriods bounds expansion of rules that never match (e.g. BYMONTHDAY=31
+// with an interval that only lands on shorter months)
+const maxPeriods = 50000
+
+const (
+	untilFormat     = "20060102T150405Z"
+	untilDateFormat = "20060102"
+)
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum is a BYDAY entry. N selects the Nth (or Nth from last when
+// negative) weekday of the month and is only used with MONTHLY; 0 means every.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+func (w WeekdayNum) String() string {
+	code := strings.ToUpper(w.Weekday.String()[:2])
+	if w.N != 0 {
+		return strconv.Itoa(w.N) + code
+	}
+	return code
+}
+
+// Rule is a parsed RRULE
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=10".
+// A leading "RRULE:" is accepted. Parts outside the supported subset are
+// rejected rather than ignored so that a rule never expands differently than
+// the calendar it came from.
+func Parse(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "RRULE:"), "rrule:")
+	if value == "" {
+		return Rule{}, errors.New("rrule is empty")
+	}
+
+	rule := Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("malformed rrule part %q", part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if seen[key] {
+			return Rule{}, fmt.Errorf("duplicate rrule part %s", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Frequency(val) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Frequency(val)
+			default:
+				return Rule{}, fmt.Errorf("unsupported FREQ %q (use DAILY, WEEKLY or MONTHLY)", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("INTERVAL must be a positive integer")
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("COUNT must be a positive integer")
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				wd, err := parseWeekdayNum(item)
+				if err != nil {
+					return Rule{}, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(item)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return Rule{}, fmt.Errorf("invalid BYMONTHDAY %q", item)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			// Weeks always start on Monday here, which is the RFC default
+			if val != "MO" {
+				return Rule{}, fmt.Errorf("unsupported WKST %q", val)
+			}
+		default:
+			return Rule{}, fmt.Errorf("unsupported rrule part %s", key)
+		}
+	}
+
+	if err := rule.validate(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+func (r Rule) validate() error {
+	if r.Freq == "" {
+		return errors.New("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errors.New("COUNT and UNTIL cannot be combined")
+	}
+	if len(r.ByDay) > 0 && r.Freq == Daily {
+		return errors.New("BYDAY is only supported with WEEKLY or MONTHLY")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != Monthly {
+		return errors.New("BYMONTHDAY is only supported with MONTHLY")
+	}
+	if len(r.ByMonthDay) > 0 && len(r.ByDay) > 0 {
+		return errors.New("BYDAY and BYMONTHDAY cannot be combined")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly {
+			return fmt.Errorf("BYDAY %s: ordinals are only supported with MONTHLY", wd)
+		}
+	}
+	return nil
+}
+
+func parseUntil(val string) (time.Time, error) {
+	if t, err := time.Parse(untilFormat, val); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(untilDateFormat, val); err == nil {
+		// A date-only UNTIL is inclusive of the whole day
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("UNTIL must be a UTC date-time (YYYYMMDDTHHMMSSZ) or date (YYYYMMDD)")
+}
+
+func parseWeekdayNum(val string) (WeekdayNum, error) {
+	val = strings.TrimSpace(val)
+	if len(val) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", val)
+	}
+	wd, ok := weekdayCodes[val[len(val)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", val)
+	}
+	var n int
+	if prefix := val[:len(val)-2]; prefix != "" {
+		parsed, err := strconv.Atoi(prefix)
+		if err != nil || parsed == 0 || parsed < -5 || parsed > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", val)
+		}
+		n = parsed
+	}
+	return WeekdayNum{Weekday: wd, N: n}, nil
+}
+
+// String returns the canonical RRULE value (without the "RRULE:" prefix)
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilFormat))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, n := range r.ByMonthDay {
+			days[i] = strconv.Itoa(n)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Iterate calls fn with each occurrence of the rule in order, starting at
+// dtstart, until fn returns false or the rule is exhausted. Occurrences keep
+// dtstart's wall-clock time in dtstart's location, so a 09:00 meeting stays at
+// 09:00 local time across DST changes.
+func (r Rule) Iterate(dtstart time.Time, fn func(time.Time) bool) {
+	emitted := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range r.candidates(dtstart, period) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return
+			}
+			if !fn(t) {
+				return
+			}
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// First returns the first occurrence, if the rule has any
+func (r Rule) First(dtstart time.Time) (time.Time, bool) {
+	var (
+		first time.Time
+		found bool
+	)
+	r.Iterate(dtstart, func(t time.Time) bool {
+		first, found = t, true
+		return false
+	})
+	return first, found
+}
+
+// Between returns the occurrences that start in [from, to)
+func (r Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var out []time.Time
+	r.Iterate(dtstart, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+		return true
+	})
+	return out
+}
+
+// Includes reports whether t is an occurrence of the rule
+func (r Rule) Includes(dtstart, t time.Time) bool {
+	found := false
+	r.Iterate(dtstart, func(occ time.Time) bool {
+		if occ.Equal(t) {
+			found = true
+		}
+		return occ.Before(t)
+	})
+	return found
+}
+
+// CountBefore returns how many occurrences start before t
+func (r Rule) CountBefore(dtstart, t time.Time) int {
+	n := 0
+	r.Iterate(dtstart, func(occ time.Time) bool {
+		if !occ.Before(t) {
+			return false
+		}
+		n++
+		return true
+	})
+	return n
+}
+
+// Split divides the rule at one of its occurrences after the first. The
+// first rule ends just before at and the second continues from it, with COUNT
+// carried over, so together they expand to the same occurrences as r.
+func (r Rule) Split(dtstart, at time.Time) (Rule, Rule) {
+	ended, rest := r, r
+	if r.Count > 0 {
+		before := r.CountBefore(dtstart, at)
+		ended.Count = before
+		rest.Count -= before
+	} else {
+		ended.Until = at.Add(-time.Second).UTC()
+	}
+	return ended, rest
+}
+
+// candidates returns the possible occurrences in the given period (day, week
+// or month, scaled by INTERVAL), sorted by time
+func (r Rule) candidates(dtstart time.Time, period int) []time.Time {
+	year, month, day := dtstart.Date()
+	hour, min, sec := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, 0, loc)
+	}
+	step := period * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{at(year, month, day+step)}
+
+	case Weekly:
+		// Monday of dtstart's week, shifted by the period
+		monday := day - mondayOffset(dtstart.Weekday()) + step*7
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(year, month, monday+mondayOffset(dtstart.Weekday()))}
+		}
+		out := make([]time.Time, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			out = append(out, at(year, month, monday+mondayOffset(wd.Weekday)))
+		}
+		return sortUnique(out)
+
+	case Monthly:
+		first := time.Date(year, month+time.Month(step), 1, 0, 0, 0, 0, loc)
+		y, m := first.Year(), first.Month()
+		daysIn := time.Date(y, m+1, 0, 0, 0, 0, 0, loc).Day()
+
+		var days []int
+		switch {
+		case len(r.ByMonthDay) > 0:
+			for _, n := range r.ByMonthDay {
+				if n < 0 {
+					n = daysIn + n + 1
+				}
+				days = append(days, n)
+			}
+		case len(r.ByDay) > 0:
+			for _, wd := range r.ByDay {
+				days = append(days, monthWeekdays(first, daysIn, wd)...)
+			}
+		default:
+			days = []int{day}
+		}
+
+		out := make([]time.Time, 0, len(days))
+		for _, d := range days {
+			// Months without the requested day are skipped, as in RFC 5545
+			if d >= 1 && d <= daysIn {
+				out = append(out, at(y, m, d))
+			}
+		}
+		return sortUnique(out)
+	}
+	return nil
+}
+
+// monthWeekdays returns the days of the month matching a BYDAY entry
+func monthWeekdays(first time.Time, daysIn int, wd WeekdayNum) []int {
+	firstMatch := 1 + (int(wd.Weekday)-int(first.Weekday())+7)%7
+	var all []int
+	for d := firstMatch; d <= daysIn; d += 7 {
+		all = append(all, d)
+	}
+	switch {
+	case wd.N == 0:
+		return all
+	case wd.N > 0 && wd.N <= len(all):
+		return []int{all[wd.N-1]}
+	case wd.N < 0 && -wd.N <= len(all):
+		return []int{all[len(all)+wd.N]}
+	}
+	return nil
+}
+
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func sortUnique(times []time.Time) []time.Time {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	out := times[:0]
+	for i, t := range times {
+		if i == 0 || !t.Equal(times[i-1]) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
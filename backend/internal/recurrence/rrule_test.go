@@ -0,0 +1,354 @@
+package recurrence
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const occurrenceLayout = "2006-01-02 15:04 -0700"
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q) error = %v", name, err)
+	}
+	return loc
+}
+
+func mustParse(t *testing.T, value string) Rule {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", value, err)
+	}
+	return rule
+}
+
+// expand returns up to limit occurrences formatted in their own location
+func expand(r Rule, dtstart time.Time, limit int) []string {
+	var out []string
+	r.Iterate(dtstart, func(t time.Time) bool {
+		out = append(out, t.Format(occurrenceLayout))
+		return len(out) < limit
+	})
+	return out
+}
+
+func formatAll(times []time.Time) []string {
+	out := make([]string, len(times))
+	for i, t := range times {
+		out[i] = t.Format(occurrenceLayout)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;COUNT=10", want: "FREQ=WEEKLY;INTERVAL=2;COUNT=10;BYDAY=TU,TH"},
+		{value: "RRULE:freq=monthly;byday=-1fr", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{value: "FREQ=MONTHLY;BYDAY=2MO,-2MO", want: "FREQ=MONTHLY;BYDAY=2MO,-2MO"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=-1,15", want: "FREQ=MONTHLY;BYMONTHDAY=-1,15"},
+		{value: "FREQ=DAILY;INTERVAL=1;UNTIL=20260331T080000Z", want: "FREQ=DAILY;UNTIL=20260331T080000Z"},
+		{value: "FREQ=DAILY;UNTIL=20260331", want: "FREQ=DAILY;UNTIL=20260331T235959Z"},
+		{value: " FREQ=WEEKLY;WKST=MO; ", want: "FREQ=WEEKLY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule := mustParse(t, tt.value)
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			again := mustParse(t, rule.String())
+			if got := again.String(); got != tt.want {
+				t.Errorf("round trip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{value: "", wantErr: "rrule is empty"},
+		{value: "RRULE:", wantErr: "rrule is empty"},
+		{value: "FREQ", wantErr: "malformed rrule part"},
+		{value: "INTERVAL=2", wantErr: "FREQ is required"},
+		{value: "FREQ=YEARLY", wantErr: "unsupported FREQ"},
+		{value: "FREQ=DAILY;FREQ=WEEKLY", wantErr: "duplicate rrule part FREQ"},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: "INTERVAL must be a positive integer"},
+		{value: "FREQ=DAILY;COUNT=-1", wantErr: "COUNT must be a positive integer"},
+		{value: "FREQ=DAILY;UNTIL=2026-03-31", wantErr: "UNTIL must be"},
+		{value: "FREQ=DAILY;UNTIL=20260331T080000", wantErr: "UNTIL must be"},
+		{value: "FREQ=DAILY;COUNT=3;UNTIL=20260331", wantErr: "COUNT and UNTIL cannot be combined"},
+		{value: "FREQ=DAILY;BYDAY=MO", wantErr: "BYDAY is only supported with WEEKLY or MONTHLY"},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: "ordinals are only supported with MONTHLY"},
+		{value: "FREQ=MONTHLY;BYDAY=6MO", wantErr: "invalid BYDAY"},
+		{value: "FREQ=MONTHLY;BYDAY=0MO", wantErr: "invalid BYDAY"},
+		{value: "FREQ=MONTHLY;BYDAY=XX", wantErr: "invalid BYDAY"},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: "BYMONTHDAY is only supported with MONTHLY"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=0", wantErr: "invalid BYMONTHDAY"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=-32", wantErr: "invalid BYMONTHDAY"},
+		{value: "FREQ=MONTHLY;BYDAY=MO;BYMONTHDAY=1", wantErr: "BYDAY and BYMONTHDAY cannot be combined"},
+		{value: "FREQ=WEEKLY;WKST=SU", wantErr: "unsupported WKST"},
+		{value: "FREQ=DAILY;BYHOUR=9", wantErr: "unsupported rrule part BYHOUR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, err := Parse(tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIterate(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	newYork := mustLoad(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		want    []string
+	}{
+		{
+			name:    "daily keeps wall time into summer time",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: time.Date(2026, 3, 28, 9, 0, 0, 0, berlin),
+			want:    []string{"2026-03-28 09:00 +0100", "2026-03-29 09:00 +0200", "2026-03-30 09:00 +0200"},
+		},
+		{
+			name:    "weekly keeps wall time out of summer time",
+			rule:    "FREQ=WEEKLY;BYDAY=SU;COUNT=3",
+			dtstart: time.Date(2026, 10, 25, 9, 0, 0, 0, newYork),
+			want:    []string{"2026-10-25 09:00 -0400", "2026-11-01 09:00 -0500", "2026-11-08 09:00 -0500"},
+		},
+		{
+			name:    "weekly interval skips days before dtstart",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4",
+			dtstart: time.Date(2026, 1, 7, 10, 0, 0, 0, time.UTC),
+			want:    []string{"2026-01-07 10:00 +0000", "2026-01-19 10:00 +0000", "2026-01-21 10:00 +0000", "2026-02-02 10:00 +0000"},
+		},
+		{
+			name:    "weekly across the year boundary",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=3",
+			dtstart: time.Date(2026, 12, 28, 10, 0, 0, 0, time.UTC),
+			want:    []string{"2026-12-28 10:00 +0000", "2027-01-01 10:00 +0000", "2027-01-04 10:00 +0000"},
+		},
+		{
+			name:    "monthly on the 31st skips short months",
+			rule:    "FREQ=MONTHLY;COUNT=4",
+			dtstart: time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC),
+			want:    []string{"2026-01-31 10:00 +0000", "2026-03-31 10:00 +0000", "2026-05-31 10:00 +0000", "2026-07-31 10:00 +0000"},
+		},
+		{
+			name:    "last day of the month",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=4",
+			dtstart: time.Date(2028, 1, 31, 10, 0, 0, 0, time.UTC),
+			want:    []string{"2028-01-31 10:00 +0000", "2028-02-29 10:00 +0000", "2028-03-31 10:00 +0000", "2028-04-30 10:00 +0000"},
+		},
+		{
+			name:    "first and last day of the month",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=4",
+			dtstart: time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC),
+			want:    []string{"2026-02-01 10:00 +0000", "2026-02-28 10:00 +0000", "2026-03-01 10:00 +0000", "2026-03-31 10:00 +0000"},
+		},
+		{
+			name:    "last Friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			dtstart: time.Date(2026, 1, 1, 15, 0, 0, 0, berlin),
+			want:    []string{"2026-01-30 15:00 +0100", "2026-02-27 15:00 +0100", "2026-03-27 15:00 +0100"},
+		},
+		{
+			name:    "fifth Monday only in months that have one",
+			rule:    "FREQ=MONTHLY;BYDAY=5MO;COUNT=2",
+			dtstart: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			want:    []string{"2026-03-30 09:00 +0000", "2026-06-29 09:00 +0000"},
+		},
+		{
+			name:    "second Tuesday every other month",
+			rule:    "FREQ=MONTHLY;INTERVAL=2;BYDAY=2TU;COUNT=3",
+			dtstart: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			want:    []string{"2026-01-13 09:00 +0000", "2026-03-10 09:00 +0000", "2026-05-12 09:00 +0000"},
+		},
+		{
+			name:    "UNTIL is inclusive",
+			rule:    "FREQ=DAILY;UNTIL=20260103T090000Z",
+			dtstart: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			want:    []string{"2026-01-01 09:00 +0000", "2026-01-02 09:00 +0000", "2026-01-03 09:00 +0000"},
+		},
+		{
+			name:    "date-only UNTIL covers the whole UTC day",
+			rule:    "FREQ=DAILY;UNTIL=20260102",
+			dtstart: time.Date(2026, 1, 1, 23, 30, 0, 0, berlin),
+			want:    []string{"2026-01-01 23:30 +0100", "2026-01-02 23:30 +0100"},
+		},
+		{
+			name:    "UNTIL before dtstart",
+			rule:    "FREQ=DAILY;UNTIL=20251231",
+			dtstart: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			want:    nil,
+		},
+		{
+			name:    "rule that never matches",
+			rule:    "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+			dtstart: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := expand(mustParse(t, tt.rule), tt.dtstart, 10)
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Iterate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	dtstart := time.Date(2026, 3, 16, 9, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		to   time.Time
+		want []string
+	}{
+		{
+			name: "window end is exclusive",
+			rule: "FREQ=WEEKLY",
+			from: time.Date(2026, 3, 23, 9, 0, 0, 0, berlin),
+			to:   time.Date(2026, 4, 6, 9, 0, 0, 0, berlin),
+			want: []string{"2026-03-23 09:00 +0100", "2026-03-30 09:00 +0200"},
+		},
+		{
+			name: "window in another location",
+			rule: "FREQ=WEEKLY",
+			from: time.Date(2026, 3, 30, 7, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 3, 30, 7, 0, 1, 0, time.UTC),
+			want: []string{"2026-03-30 09:00 +0200"},
+		},
+		{
+			name: "COUNT ends the series inside the window",
+			rule: "FREQ=WEEKLY;COUNT=2",
+			from: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			want: []string{"2026-03-16 09:00 +0100", "2026-03-23 09:00 +0100"},
+		},
+		{
+			name: "window before dtstart",
+			rule: "FREQ=WEEKLY",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 3, 16, 8, 0, 0, 0, time.UTC),
+			want: nil,
+		},
+		{
+			name: "window after the series ended",
+			rule: "FREQ=WEEKLY;UNTIL=20260401",
+			from: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatAll(mustParse(t, tt.rule).Between(dtstart, tt.from, tt.to))
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	tests := []struct {
+		name      string
+		rule      string
+		dtstart   time.Time
+		at        time.Time
+		wantEnded string
+		wantRest  string
+	}{
+		{
+			name:      "COUNT carries over",
+			rule:      "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10",
+			dtstart:   time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			at:        time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC),
+			wantEnded: "FREQ=WEEKLY;COUNT=3;BYDAY=MO,TH",
+			wantRest:  "FREQ=WEEKLY;COUNT=7;BYDAY=MO,TH",
+		},
+		{
+			name:      "COUNT across a DST change",
+			rule:      "FREQ=DAILY;COUNT=5",
+			dtstart:   time.Date(2026, 3, 27, 9, 0, 0, 0, berlin),
+			at:        time.Date(2026, 3, 30, 9, 0, 0, 0, berlin),
+			wantEnded: "FREQ=DAILY;COUNT=3",
+			wantRest:  "FREQ=DAILY;COUNT=2",
+		},
+		{
+			name:      "COUNT with skipped months",
+			rule:      "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4",
+			dtstart:   time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			at:        time.Date(2026, 5, 31, 9, 0, 0, 0, time.UTC),
+			wantEnded: "FREQ=MONTHLY;COUNT=2;BYMONTHDAY=31",
+			wantRest:  "FREQ=MONTHLY;COUNT=2;BYMONTHDAY=31",
+		},
+		{
+			name:      "open-ended series ends before the split",
+			rule:      "FREQ=MONTHLY;BYDAY=-1FR",
+			dtstart:   time.Date(2026, 1, 30, 15, 0, 0, 0, berlin),
+			at:        time.Date(2026, 3, 27, 15, 0, 0, 0, berlin),
+			wantEnded: "FREQ=MONTHLY;UNTIL=20260327T135959Z;BYDAY=-1FR",
+			wantRest:  "FREQ=MONTHLY;BYDAY=-1FR",
+		},
+		{
+			name:      "UNTIL stays with the continuing series",
+			rule:      "FREQ=DAILY;UNTIL=20260110",
+			dtstart:   time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+			at:        time.Date(2026, 1, 4, 9, 0, 0, 0, time.UTC),
+			wantEnded: "FREQ=DAILY;UNTIL=20260104T085959Z",
+			wantRest:  "FREQ=DAILY;UNTIL=20260110T235959Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := mustParse(t, tt.rule)
+			ended, rest := rule.Split(tt.dtstart, tt.at)
+			if got := ended.String(); got != tt.wantEnded {
+				t.Errorf("ended = %q, want %q", got, tt.wantEnded)
+			}
+			if got := rest.String(); got != tt.wantRest {
+				t.Errorf("rest = %q, want %q", got, tt.wantRest)
+			}
+
+			// Both halves together expand to the original occurrences
+			const limit = 40
+			want := expand(rule, tt.dtstart, limit)
+			got := append(expand(ended, tt.dtstart, limit), expand(rest, tt.at, limit)...)
+			if len(got) > limit {
+				got = got[:limit]
+			}
+			if strings.Join(got, ", ") != strings.Join(want, ", ") {
+				t.Errorf("split expands to %v, want %v", got, want)
+			}
+		})
+	}
+}
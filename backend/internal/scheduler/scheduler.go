@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
+)
+
+// Worker runs the meeting lifecycle pass on an interval. Every replica may
+// run one; the service claims each change so work is not done twice.
+type Worker struct {
+	svc      *services.AppService
+	opts     services.MeetingLifecycleOptions
+	interval time.Duration
+	logger   logger.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a worker; call Start to begin running it
+func New(svc *services.AppService, interval time.Duration, opts services.MeetingLifecycleOptions, log logger.Logger) *Worker {
+	return &Worker{
+		svc:      svc,
+		opts:     opts,
+		interval: interval,
+		logger:   log,
+	}
+}
+
+// Start runs a pass right away and then once per interval
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			w.runOnce(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop cancels the running pass and waits for the worker to exit
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+func (w *Worker) runOnce(ctx context.Context) {
+	// A pass must not overlap the next one
+	ctx, cancel := context.WithTimeout(ctx, w.interval)
+	defer cancel()
+
+	result, err := w.svc.RunMeetingLifecycle(ctx, w.opts)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error("meeting lifecycle pass failed", "error", err)
+		}
+		return
+	}
+	if result != (services.MeetingLifecycleResult{}) {
+		w.logger.Info("meeting lifecycle pass",
+			"missed", result.Missed,
+			"expired", result.Expired,
+			"ended", result.Ended,
+			"reminded", result.Reminded,
+			"transcripts", result.Transcripts,
+			"events_purged", result.EventsPurged,
+		)
+	}
+}
@@ -10,28 +10,29 @@ import (
 	"github.com/aicomp/ai-virtual-chat/backend/internal/config"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/httpapi"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/infrastructure"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/metrics"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/migrate"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/scheduler"
 	"github.com/aicomp/ai-virtual-chat/backend/internal/services"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-type Logger interface {
-	Println(v ...any)
-	Printf(format string, v ...any)
-	Fatalf(format string, v ...any)
-}
-
 type Server struct {
 	cfg    *config.Config
-	logger Logger
+	logger logger.Logger
 	http   *http.Server
-	api    *httpapi.API
-	pg     *pgxpool.Pool
-	redis  *redis.Client
+	// metrics serves /metrics on a separate port when configured
+	metrics *http.Server
+	api     *httpapi.API
+	// scheduler runs the meeting lifecycle when enabled and a database is configured
+	scheduler *scheduler.Worker
+	pg        *pgxpool.Pool
+	redis     *redis.Client
 }
 
-func New(cfg *config.Config, logger Logger) (*Server, error) {
+func New(cfg *config.Config, logger logger.Logger) (*Server, error) {
 	ctx := context.Background()
 
 	var pgPool *pgxpool.Pool
@@ -44,14 +45,22 @@ func New(cfg *config.Config, logger Logger) (*Server, error) {
 			pool.Close()
 			return nil, fmt.Errorf("run migrations: %w", err)
 		}
-		if err := services.Seed(ctx, pool, logger); err != nil {
+		// Demo data is only loaded automatically for local development;
+		// other environments use `admin seed` explicitly
+		if cfg.Env == "development" {
+			if err := services.Seed(ctx, pool, logger); err != nil {
+				pool.Close()
+				return nil, fmt.Errorf("seed database: %w", err)
+			}
+		}
+		if err := metrics.RegisterPostgresPool(pool); err != nil {
 			pool.Close()
-			return nil, fmt.Errorf("seed database: %w", err)
+			return nil, fmt.Errorf("register pool metrics: %w", err)
 		}
 		pgPool = pool
-		logger.Println("postgres connected")
+		logger.Info("postgres connected")
 	} else {
-		logger.Println("postgres dsn not provided, running without database connection")
+		logger.Warn("postgres dsn not provided, running without database connection")
 	}
 
 	var redisClient *redis.Client
@@ -64,14 +73,36 @@ func New(cfg *config.Config, logger Logger) (*Server, error) {
 			return nil, err
 		}
 		redisClient = client
-		logger.Println("redis connected")
+		logger.Info("redis connected")
+	} else {
+		logger.Warn("redis url not provided, running without redis connection")
+	}
+
+	var mailer services.Mailer
+	if cfg.SMTPHost != "" {
+		mailer = &services.SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			TLS:      cfg.SMTPTLS,
+		}
 	} else {
-		logger.Println("redis url not provided, running without redis connection")
+		logger.Warn("smtp host not provided, emails will be logged instead of delivered")
 	}
 
 	var appService *services.AppService
 	if pgPool != nil {
-		appService = services.NewAppService(pgPool)
+		appService = services.NewAppService(pgPool, services.Options{
+			Redis:                    redisClient,
+			AppURL:                   cfg.AppBaseURL,
+			APIURL:                   cfg.APIBaseURL,
+			AllowPrivateCalendarURLs: cfg.CalendarImportAllowPrivate,
+			MissedAfter:              time.Duration(cfg.MeetingMissedAfterMin) * time.Minute,
+			Mailer:                   mailer,
+			Logger:                   logger,
+		})
 	}
 
 	api := httpapi.New(cfg, logger, httpapi.Dependencies{
@@ -80,6 +111,17 @@ func New(cfg *config.Config, logger Logger) (*Server, error) {
 		Service:  appService,
 	})
 
+	var lifecycle *scheduler.Worker
+	if appService != nil && cfg.SchedulerEnabled {
+		lifecycle = scheduler.New(appService, time.Duration(cfg.SchedulerIntervalSec)*time.Second, services.MeetingLifecycleOptions{
+			MissedAfter:  time.Duration(cfg.MeetingMissedAfterMin) * time.Minute,
+			AutoEndAfter: time.Duration(cfg.MeetingAutoEndAfterMin) * time.Minute,
+			ReminderLead: time.Duration(cfg.MeetingReminderMin) * time.Minute,
+
+			EventRetention: time.Duration(cfg.MeetingEventRetentionHours) * time.Hour,
+		}, logger)
+	}
+
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.HTTPPort),
 		Handler:      api.Routes(),
@@ -87,35 +129,83 @@ func New(cfg *config.Config, logger Logger) (*Server, error) {
 		WriteTimeout: time.Duration(cfg.WriteTimeoutSec) * time.Second,
 	}
 
+	var metricsServer *http.Server
+	if cfg.MetricsPort > 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:        fmt.Sprintf(":%d", cfg.MetricsPort),
+			Handler:     mux,
+			ReadTimeout: 5 * time.Second,
+		}
+	}
+
 	return &Server{
-		cfg:    cfg,
-		logger: logger,
-		http:   httpServer,
-		api:    api,
-		pg:     pgPool,
-		redis:  redisClient,
+		cfg:       cfg,
+		logger:    logger,
+		http:      httpServer,
+		metrics:   metricsServer,
+		api:       api,
+		scheduler: lifecycle,
+		pg:        pgPool,
+		redis:     redisClient,
 	}, nil
 }
 
 func (s *Server) Start() error {
-	s.logger.Printf("http server listening on %s", s.http.Addr)
+	if s.metrics != nil {
+		go func() {
+			s.logger.Info("metrics server listening", "addr", s.metrics.Addr)
+			if err := s.metrics.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error("metrics server error", "error", err)
+			}
+		}()
+	}
+
+	if s.scheduler != nil {
+		s.logger.Info("meeting lifecycle worker started")
+		s.scheduler.Start()
+	}
+
+	s.logger.Info("http server listening", "addr", s.http.Addr)
 	return s.http.ListenAndServe()
 }
 
 func (s *Server) Stop(ctx context.Context) error {
-	// Stop API background processes (rate limiter cleanup)
 	if s.api != nil {
+		// Fail readiness first and give load balancers time to notice before
+		// the listener closes
+		s.api.BeginShutdown()
+		if drain := time.Duration(s.cfg.ShutdownDrainSec) * time.Second; drain > 0 {
+			s.logger.Info("draining before shutdown", "delay", drain)
+			select {
+			case <-time.After(drain):
+			case <-ctx.Done():
+			}
+		}
+
+		// Stop API background processes (rate limiter cleanup)
 		s.api.Stop()
 	}
 
 	err := s.http.Shutdown(ctx)
 
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+	}
+
+	if s.metrics != nil {
+		if metricsErr := s.metrics.Shutdown(ctx); metricsErr != nil {
+			s.logger.Error("metrics server shutdown error", "error", metricsErr)
+		}
+	}
+
 	if s.pg != nil {
 		s.pg.Close()
 	}
 	if s.redis != nil {
 		if closeErr := s.redis.Close(); closeErr != nil && !errors.Is(closeErr, context.Canceled) {
-			s.logger.Printf("redis close error: %v", closeErr)
+			s.logger.Error("redis close error", "error", closeErr)
 		}
 	}
 
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CreateUser creates an account from an operator tool rather than self sign-up
+func (s *AppService) CreateUser(ctx context.Context, name, email, password, planTier string) (string, error) {
+	if err := s.ensureDB(); err != nil {
+		return "", err
+	}
+
+	planTier = strings.TrimSpace(planTier)
+	if planTier == "" {
+		planTier = "free"
+	}
+	return s.createUser(ctx, name, email, password, planTier)
+}
+
+// ResetPassword sets a new password and signs the user out everywhere
+func (s *AppService) ResetPassword(ctx context.Context, email, password string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+
+	email = strings.TrimSpace(strings.ToLower(email))
+	password = strings.TrimSpace(password)
+	if email == "" {
+		return InvalidField("email", "email is required")
+	}
+	if password == "" {
+		return InvalidField("password", "password is required")
+	}
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+
+	hashBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password")
+	}
+
+	result, err := s.db.Exec(ctx, `
+		UPDATE app_users
+		   SET password_hash = $1,
+		       updated_at = NOW()
+		 WHERE LOWER(email) = $2`, string(hashBytes), email)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return notFound("user_not_found", "user not found")
+	}
+
+	if _, err := s.RevokeAllSessions(ctx, email); err != nil {
+		return err
+	}
+	// A reset password should not stay behind an old lockout
+	return s.loginAttempts.Reset(ctx, email)
+}
+
+// DisableUser blocks future logins and revokes every session of the user.
+// Existing data is kept so the account can be re-enabled later.
+func (s *AppService) DisableUser(ctx context.Context, email string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+
+	email = strings.TrimSpace(strings.ToLower(email))
+	if email == "" {
+		return InvalidField("email", "email is required")
+	}
+
+	result, err := s.db.Exec(ctx, `
+		UPDATE app_users
+		   SET disabled_at = COALESCE(disabled_at, NOW()),
+		       updated_at = NOW()
+		 WHERE LOWER(email) = $1`, email)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return notFound("user_not_found", "user not found")
+	}
+
+	_, err = s.RevokeAllSessions(ctx, email)
+	return err
+}
+
+// RevokeAllSessions revokes the refresh sessions of one user, or of every
+// user when email is empty. It returns the number of sessions revoked.
+func (s *AppService) RevokeAllSessions(ctx context.Context, email string) (int64, error) {
+	if err := s.ensureDB(); err != nil {
+		return 0, err
+	}
+
+	email = strings.TrimSpace(strings.ToLower(email))
+	if email == "" {
+		result, err := s.db.Exec(ctx, `
+			UPDATE session_tokens
+			   SET revoked = TRUE
+			 WHERE revoked = FALSE`)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected(), nil
+	}
+
+	result, err := s.db.Exec(ctx, `
+		UPDATE session_tokens st
+		   SET revoked = TRUE
+		  FROM app_users u
+		 WHERE u.id = st.user_id
+		   AND LOWER(u.email) = $1
+		   AND st.revoked = FALSE`, email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+// Agenda item progress states
+const (
+	AgendaStatusPending   = "pending"
+	AgendaStatusCurrent   = "current"
+	AgendaStatusCompleted = "completed"
+)
+
+const (
+	maxAgendaItems             = 50
+	maxAgendaTitleLength       = 200
+	maxAgendaDescriptionLength = 2000
+)
+
+// GetMeetingAgenda returns a meeting's agenda with its live progress
+func (s *AppService) GetMeetingAgenda(ctx context.Context, identifier string) (*core.MeetingAgendaResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+	agenda, err := loadMeetingAgenda(ctx, s.db, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return &core.MeetingAgendaResponse{Agenda: agenda}, nil
+}
+
+// AddAgendaItem inserts an item at req.Position or at the end of the agenda
+func (s *AppService) AddAgendaItem(ctx context.Context, identifier string, req core.CreateAgendaItemRequest) (*core.AgendaItem, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	item := core.AgendaItem{
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Duration:    req.Duration,
+	}
+	if err := validateAgendaItem(item); err != nil {
+		return nil, err
+	}
+	if req.Position < 0 {
+		return nil, InvalidField("position", "position must be positive")
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM meeting_agenda_items WHERE meeting_id = $1::uuid`, meetingID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count >= maxAgendaItems {
+		return nil, InvalidField("agenda", fmt.Sprintf("a meeting can have at most %d agenda items", maxAgendaItems))
+	}
+	position := req.Position
+	if position == 0 || position > count+1 {
+		position = count + 1
+	}
+
+	// Make room, then renumber so order_index stays 1..n
+	if _, err := tx.Exec(ctx, `
+		UPDATE meeting_agenda_items
+		   SET order_index = order_index + 1
+		 WHERE meeting_id = $1::uuid
+		   AND order_index >= $2`, meetingID, position); err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO meeting_agenda_items (meeting_id, order_index, title, description, duration_minutes)
+		VALUES ($1::uuid, $2, $3, $4, $5)
+		RETURNING id::text`,
+		meetingID,
+		position,
+		item.Title,
+		item.Description,
+		item.Duration,
+	).Scan(&item.ID); err != nil {
+		return nil, err
+	}
+	if err := renumberAgenda(ctx, tx, meetingID); err != nil {
+		return nil, err
+	}
+	if err := touchMeeting(ctx, tx, meetingID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	item.Status = AgendaStatusPending
+	return &item, nil
+}
+
+func (s *AppService) UpdateAgendaItem(ctx context.Context, identifier, itemID string, req core.UpdateAgendaItemRequest) (*core.AgendaItem, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	itemID = strings.TrimSpace(itemID)
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := scanAgendaItem(tx.QueryRow(ctx, agendaSelect+`
+		   AND id::text = $2
+		 FOR UPDATE`, meetingID, itemID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("agenda_item_not_found", "agenda item not found")
+		}
+		return nil, err
+	}
+
+	if req.Title != nil {
+		item.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		item.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Duration != nil {
+		item.Duration = *req.Duration
+	}
+	if err := validateAgendaItem(*item); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE meeting_agenda_items
+		   SET title = $1,
+		       description = $2,
+		       duration_minutes = $3
+		 WHERE meeting_id = $4::uuid
+		   AND id::text = $5`,
+		item.Title,
+		item.Description,
+		item.Duration,
+		meetingID,
+		itemID,
+	); err != nil {
+		return nil, err
+	}
+	if err := touchMeeting(ctx, tx, meetingID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	setAgendaTiming(item, time.Now())
+	return item, nil
+}
+
+func (s *AppService) DeleteAgendaItem(ctx context.Context, identifier, itemID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	itemID = strings.TrimSpace(itemID)
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(ctx, `
+			DELETE FROM meeting_agenda_items
+			 WHERE meeting_id = $1::uuid
+			   AND id::text = $2`, meetingID, itemID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return notFound("agenda_item_not_found", "agenda item not found")
+		}
+		if err := renumberAgenda(ctx, tx, meetingID); err != nil {
+			return err
+		}
+		return touchMeeting(ctx, tx, meetingID)
+	})
+}
+
+// ReorderAgenda puts the agenda in the given order. itemIDs must list every
+// item exactly once.
+func (s *AppService) ReorderAgenda(ctx context.Context, identifier string, itemIDs []string) (*core.MeetingAgendaResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := loadMeetingAgenda(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(current))
+	for _, item := range current {
+		known[item.ID] = true
+	}
+	seen := make(map[string]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		id = strings.TrimSpace(id)
+		if !known[id] {
+			return nil, InvalidField("itemIds", fmt.Sprintf("unknown agenda item %q", id))
+		}
+		if seen[id] {
+			return nil, InvalidField("itemIds", fmt.Sprintf("agenda item %q is listed twice", id))
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(current) {
+		return nil, InvalidField("itemIds", "every agenda item must be listed")
+	}
+
+	for idx, id := range itemIDs {
+		if _, err := tx.Exec(ctx, `
+			UPDATE meeting_agenda_items
+			   SET order_index = $1
+			 WHERE meeting_id = $2::uuid
+			   AND id::text = $3`, idx+1, meetingID, strings.TrimSpace(id)); err != nil {
+			return nil, err
+		}
+	}
+	if err := touchMeeting(ctx, tx, meetingID); err != nil {
+		return nil, err
+	}
+
+	agenda, err := loadMeetingAgenda(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &core.MeetingAgendaResponse{Agenda: agenda}, nil
+}
+
+// UpdateAgendaProgress moves an item of a running meeting to pending, current
+// or completed. Making an item current completes the previous current item;
+// moving it back to pending clears its timestamps.
+func (s *AppService) UpdateAgendaProgress(ctx context.Context, identifier, itemID string, req core.AgendaProgressRequest) (*core.MeetingAgendaResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	itemID = strings.TrimSpace(itemID)
+	status := strings.TrimSpace(req.Status)
+	switch status {
+	case AgendaStatusPending, AgendaStatusCurrent, AgendaStatusCompleted:
+	default:
+		return nil, InvalidField("status", "status must be pending, current or completed")
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Instant meetings never become active; they run once someone joined
+	var (
+		meetingID string
+		running   bool
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT m.id::text,
+		       m.status = 'active'
+		       OR (m.status = 'instant' AND EXISTS (
+				SELECT 1
+				  FROM meeting_participants p
+				 WHERE p.meeting_id = m.id
+				   AND p.joined_at IS NOT NULL
+		       ))
+		  FROM meetings m
+		 WHERE COALESCE(m.external_id, m.id::text) = $1
+		 FOR UPDATE OF m`, strings.TrimSpace(identifier)).Scan(&meetingID, &running); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("meeting_not_found", "meeting not found")
+		}
+		return nil, err
+	}
+	if !running {
+		return nil, conflict("meeting_not_active", "agenda progress can only be tracked while the meeting is running")
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM meeting_agenda_items WHERE meeting_id = $1::uuid AND id::text = $2)`,
+		meetingID, itemID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, notFound("agenda_item_not_found", "agenda item not found")
+	}
+
+	switch status {
+	case AgendaStatusCurrent:
+		if _, err := tx.Exec(ctx, `
+			UPDATE meeting_agenda_items
+			   SET status = 'completed',
+			       completed_at = NOW()
+			 WHERE meeting_id = $1::uuid
+			   AND status = 'current'
+			   AND id::text <> $2`, meetingID, itemID); err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE meeting_agenda_items
+			   SET status = 'current',
+			       started_at = COALESCE(started_at, NOW()),
+			       completed_at = NULL
+			 WHERE meeting_id = $1::uuid
+			   AND id::text = $2`, meetingID, itemID)
+	case AgendaStatusCompleted:
+		_, err = tx.Exec(ctx, `
+			UPDATE meeting_agenda_items
+			   SET status = 'completed',
+			       completed_at = COALESCE(completed_at, NOW())
+			 WHERE meeting_id = $1::uuid
+			   AND id::text = $2`, meetingID, itemID)
+	default:
+		_, err = tx.Exec(ctx, `
+			UPDATE meeting_agenda_items
+			   SET status = 'pending',
+			       started_at = NULL,
+			       completed_at = NULL
+			 WHERE meeting_id = $1::uuid
+			   AND id::text = $2`, meetingID, itemID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	agenda, err := loadMeetingAgenda(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordMeetingEvent(ctx, tx, meetingID, MeetingEventAgendaProgress, core.AgendaProgressEvent{
+		ItemID: itemID,
+		Status: status,
+		Agenda: agenda,
+	}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.notifyMeetingEvents(meetingID)
+	return &core.MeetingAgendaResponse{Agenda: agenda}, nil
+}
+
+const agendaSelect = `
+	SELECT id::text,
+	       title,
+	       COALESCE(description, ''),
+	       duration_minutes,
+	       status,
+	       started_at,
+	       completed_at
+	  FROM meeting_agenda_items
+	 WHERE meeting_id = $1::uuid`
+
+func scanAgendaItem(row pgx.Row) (*core.AgendaItem, error) {
+	var item core.AgendaItem
+	if err := row.Scan(
+		&item.ID,
+		&item.Title,
+		&item.Description,
+		&item.Duration,
+		&item.Status,
+		&item.StartedAt,
+		&item.CompletedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func loadMeetingAgenda(ctx context.Context, q querier, meetingID string) ([]core.AgendaItem, error) {
+	rows, err := q.Query(ctx, agendaSelect+`
+		 ORDER BY order_index`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	agenda := []core.AgendaItem{}
+	for rows.Next() {
+		item, err := scanAgendaItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		setAgendaTiming(item, now)
+		agenda = append(agenda, *item)
+	}
+	return agenda, rows.Err()
+}
+
+// setAgendaTiming fills in the time spent on an item, up to now for the
+// current one, and whether that exceeded its time box
+func setAgendaTiming(item *core.AgendaItem, now time.Time) {
+	if item.StartedAt == nil {
+		return
+	}
+	end := now
+	if item.CompletedAt != nil {
+		end = *item.CompletedAt
+	}
+	spent := end.Sub(*item.StartedAt)
+	if spent < 0 {
+		spent = 0
+	}
+	item.ActualMinutes = int(math.Ceil(spent.Minutes()))
+	item.RanOver = item.Duration > 0 && spent > time.Duration(item.Duration)*time.Minute
+}
+
+// renumberAgenda closes gaps in order_index after inserts and deletes
+func renumberAgenda(ctx context.Context, tx pgx.Tx, meetingID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE meeting_agenda_items a
+		   SET order_index = o.position
+		  FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY order_index, id) AS position
+			  FROM meeting_agenda_items
+			 WHERE meeting_id = $1::uuid
+		  ) o
+		 WHERE a.meeting_id = $1::uuid
+		   AND a.id = o.id`, meetingID)
+	return err
+}
+
+// touchMeeting records an edit that calendar subscribers should pick up
+func touchMeeting(ctx context.Context, tx pgx.Tx, meetingID string) error {
+	_, err := tx.Exec(ctx, `UPDATE meetings SET updated_at = NOW(), ics_sequence = ics_sequence + 1 WHERE id = $1::uuid`, meetingID)
+	return err
+}
+
+func validateAgendaItem(item core.AgendaItem) error {
+	var verr ValidationError
+	switch n := utf8.RuneCountInString(item.Title); {
+	case n == 0:
+		verr.Fields = append(verr.Fields, FieldError{Field: "title", Message: "title is required"})
+	case n > maxAgendaTitleLength:
+		verr.Fields = append(verr.Fields, FieldError{Field: "title", Message: fmt.Sprintf("title must be at most %d characters", maxAgendaTitleLength)})
+	}
+	if utf8.RuneCountInString(item.Description) > maxAgendaDescriptionLength {
+		verr.Fields = append(verr.Fields, FieldError{Field: "description", Message: fmt.Sprintf("description must be at most %d characters", maxAgendaDescriptionLength)})
+	}
+	if item.Duration < 0 {
+		verr.Fields = append(verr.Fields, FieldError{Field: "durationMinutes", Message: "durationMinutes cannot be negative"})
+	}
+	if len(verr.Fields) > 0 {
+		return &verr
+	}
+	return nil
+}
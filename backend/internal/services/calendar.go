@@ -0,0 +1,489 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/ical"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	calendarProdID = "-//AI Virtual Chat//Meetings//EN"
+	// calendarFeedLookback limits how far back one-off meetings appear in feeds
+	calendarFeedLookback = 90 * 24 * time.Hour
+	// cancellationRetention is how long deleted meetings stay in feeds as cancelled
+	cancellationRetention = 30 * 24 * time.Hour
+)
+
+// calendarMeeting is what is needed to render a meeting as a VEVENT
+type calendarMeeting struct {
+	ID              string
+	Slug            string
+	Title           string
+	Description     string
+	Start           time.Time
+	DurationMinutes int
+	Sequence        int
+	UpdatedAt       time.Time
+	HostName        string
+	HostEmail       string
+	RRule           string
+	TimeZone        string
+	Exceptions      []time.Time
+	// SeriesSlug is set on occurrences, which are written as overrides of
+	// their series' event
+	SeriesSlug      string
+	OccurrenceStart time.Time
+	Agenda          []core.AgendaItem
+	Attendees       []ical.Attendee
+}
+
+// RotateCalendarFeedToken issues a new secret calendar feed token for the
+// user and invalidates the previous one. Only a hash is stored, so the token
+// can be shown once.
+func (s *AppService) RotateCalendarFeedToken(ctx context.Context, userID string) (*core.CalendarFeedResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+
+	token, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO calendar_feed_tokens (user_id, token_hash)
+		VALUES ($1::uuid, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		   SET token_hash = EXCLUDED.token_hash,
+		       created_at = NOW()`, userID, hashRefreshToken(token)); err != nil {
+		return nil, err
+	}
+
+	return &core.CalendarFeedResponse{
+		URL: fmt.Sprintf("%s/api/v1/calendar/feed/%s.ics", strings.TrimRight(s.apiURL, "/"), token),
+	}, nil
+}
+
+// RevokeCalendarFeedToken disables the user's calendar feed
+func (s *AppService) RevokeCalendarFeedToken(ctx context.Context, userID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(ctx, `DELETE FROM calendar_feed_tokens WHERE user_id::text = $1`, strings.TrimSpace(userID))
+	return err
+}
+
+// CalendarFeed returns the calendar behind a feed token: meetings the user
+// hosts or is invited to, plus recently deleted ones as cancelled events.
+func (s *AppService) CalendarFeed(ctx context.Context, token string) (*ical.Calendar, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, notFound("calendar_feed_not_found", "calendar feed not found")
+	}
+
+	var userID, email string
+	if err := s.db.QueryRow(ctx, `
+		SELECT t.user_id::text, LOWER(u.email)
+		  FROM calendar_feed_tokens t
+		  JOIN app_users u ON u.id = t.user_id
+		 WHERE t.token_hash = $1
+		   AND u.disabled_at IS NULL`, hashRefreshToken(token)).Scan(&userID, &email); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("calendar_feed_not_found", "calendar feed not found")
+		}
+		return nil, err
+	}
+
+	meetings, err := s.loadCalendarMeetings(ctx, `
+		(m.host_user_id = $1::uuid
+		 OR EXISTS (
+			SELECT 1
+			  FROM meeting_invites i
+			 WHERE i.meeting_id IN (m.id, m.series_id)
+			   AND i.status IN ('pending', 'accepted')
+			   AND (i.invitee_user_id = $1::uuid OR LOWER(i.email) = $2)
+		 ))
+		AND (m.status = 'series' OR m.start_time >= $3)`,
+		userID, email, time.Now().Add(-calendarFeedLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ical.Calendar{ProdID: calendarProdID, Name: "Meetings"}
+	for _, m := range meetings {
+		cal.Events = append(cal.Events, s.meetingEvent(m))
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT meeting_slug, title, start_time, duration_minutes, ics_sequence, cancelled_at
+		  FROM meeting_cancellations
+		 WHERE cancelled_at >= $3
+		   AND (host_user_id = $1::uuid OR $1::uuid = ANY(invitee_user_ids) OR $2 = ANY(invitee_emails))
+		 ORDER BY start_time`,
+		userID, email, time.Now().Add(-cancellationRetention))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			slug, title string
+			start, at   time.Time
+			duration    int
+			seq         int
+		)
+		if err := rows.Scan(&slug, &title, &start, &duration, &seq, &at); err != nil {
+			return nil, err
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			UID:          s.calendarUID(slug),
+			Sequence:     seq,
+			Stamp:        at,
+			LastModified: at,
+			Start:        start.UTC(),
+			Duration:     time.Duration(duration) * time.Minute,
+			Summary:      title,
+			Status:       ical.StatusCancelled,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cal, nil
+}
+
+// MeetingCalendar renders a meeting, as returned by GetMeeting, as a single
+// event calendar for download. Occurrences of recurring meetings are written
+// as overrides of their series (RECURRENCE-ID) so they merge with feed entries.
+func (s *AppService) MeetingCalendar(ctx context.Context, detail *core.MeetingDetail) (*ical.Calendar, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+
+	m := calendarMeeting{
+		Slug:            detail.Summary.ID,
+		Title:           detail.Summary.Title,
+		Description:     detail.Summary.Description,
+		Start:           detail.Summary.StartTime,
+		DurationMinutes: detail.Summary.DurationMinutes,
+		SeriesSlug:      detail.Summary.SeriesID,
+		Agenda:          detail.Agenda,
+		TimeZone:        "UTC",
+	}
+	if detail.Recurrence != nil {
+		m.RRule = detail.Recurrence.RRule
+		m.TimeZone = detail.Recurrence.TimeZone
+		m.Exceptions = detail.Recurrence.Exceptions
+	}
+	if _, start, ok := splitOccurrenceID(m.Slug); ok {
+		m.OccurrenceStart = start
+	}
+
+	// Sequence, organizer and attendees are not part of the detail. Occurrences
+	// that were never stored take them from their series.
+	var meetingID, seriesID, zone string
+	if err := s.db.QueryRow(ctx, `
+		SELECT m.id::text,
+		       COALESCE(m.series_id::text, ''),
+		       m.ics_sequence,
+		       m.updated_at,
+		       COALESCE(u.name, ''),
+		       COALESCE(u.email, ''),
+		       COALESCE(p.recurrence_tz, m.recurrence_tz, 'UTC')
+		  FROM meetings m
+		  LEFT JOIN app_users u ON u.id = m.host_user_id
+		  LEFT JOIN meetings p ON p.id = m.series_id
+		 WHERE COALESCE(m.external_id, m.id::text) IN ($1, $2)
+		 ORDER BY m.series_id NULLS LAST
+		 LIMIT 1`, m.Slug, m.SeriesSlug,
+	).Scan(&meetingID, &seriesID, &m.Sequence, &m.UpdatedAt, &m.HostName, &m.HostEmail, &zone); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("meeting_not_found", "meeting not found")
+		}
+		return nil, err
+	}
+	if m.SeriesSlug != "" {
+		m.TimeZone = zone
+	}
+
+	ids := []string{meetingID}
+	if seriesID != "" {
+		ids = append(ids, seriesID)
+	}
+	attendees, err := s.loadCalendarAttendees(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		m.Attendees = append(m.Attendees, attendees[id]...)
+	}
+
+	return &ical.Calendar{
+		ProdID: calendarProdID,
+		Method: "PUBLISH",
+		Events: []ical.Event{s.meetingEvent(m)},
+	}, nil
+}
+
+// loadCalendarMeetings loads stored meetings matching a condition on the
+// meetings table aliased as m, with their agenda, attendees and exceptions
+func (s *AppService) loadCalendarMeetings(ctx context.Context, condition string, args ...any) ([]calendarMeeting, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT m.id::text,
+		       COALESCE(m.external_id, m.id::text),
+		       m.title,
+		       COALESCE(m.description, ''),
+		       m.start_time,
+		       m.duration_minutes,
+		       m.ics_sequence,
+		       m.updated_at,
+		       COALESCE(u.name, ''),
+		       COALESCE(u.email, ''),
+		       COALESCE(m.recurrence_rule, ''),
+		       COALESCE(p.recurrence_tz, m.recurrence_tz, 'UTC'),
+		       COALESCE(p.external_id, p.id::text, ''),
+		       COALESCE(m.occurrence_start, m.start_time)
+		  FROM meetings m
+		  LEFT JOIN app_users u ON u.id = m.host_user_id
+		  LEFT JOIN meetings p ON p.id = m.series_id
+		 WHERE `+condition+`
+		 ORDER BY m.start_time`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var meetings []calendarMeeting
+	for rows.Next() {
+		var m calendarMeeting
+		if err := rows.Scan(
+			&m.ID,
+			&m.Slug,
+			&m.Title,
+			&m.Description,
+			&m.Start,
+			&m.DurationMinutes,
+			&m.Sequence,
+			&m.UpdatedAt,
+			&m.HostName,
+			&m.HostEmail,
+			&m.RRule,
+			&m.TimeZone,
+			&m.SeriesSlug,
+			&m.OccurrenceStart,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		meetings = append(meetings, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(meetings) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(meetings))
+	for i, m := range meetings {
+		ids[i] = m.ID
+	}
+	agendas, err := s.loadCalendarAgendas(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	attendees, err := s.loadCalendarAttendees(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := s.loadCalendarExceptions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range meetings {
+		meetings[i].Agenda = agendas[meetings[i].ID]
+		meetings[i].Attendees = attendees[meetings[i].ID]
+		meetings[i].Exceptions = exceptions[meetings[i].ID]
+	}
+	return meetings, nil
+}
+
+func (s *AppService) loadCalendarAgendas(ctx context.Context, meetingIDs []string) (map[string][]core.AgendaItem, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT meeting_id::text, id::text, title, COALESCE(description, ''), duration_minutes
+		  FROM meeting_agenda_items
+		 WHERE meeting_id = ANY($1::uuid[])
+		 ORDER BY meeting_id, order_index`, meetingIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]core.AgendaItem)
+	for rows.Next() {
+		var (
+			meetingID string
+			item      core.AgendaItem
+		)
+		if err := rows.Scan(&meetingID, &item.ID, &item.Title, &item.Description, &item.Duration); err != nil {
+			return nil, err
+		}
+		out[meetingID] = append(out[meetingID], item)
+	}
+	return out, rows.Err()
+}
+
+func (s *AppService) loadCalendarAttendees(ctx context.Context, meetingIDs []string) (map[string][]ical.Attendee, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT i.meeting_id::text, LOWER(i.email), COALESCE(u.name, ''), i.status
+		  FROM meeting_invites i
+		  LEFT JOIN app_users u ON u.id = i.invitee_user_id
+		 WHERE i.meeting_id = ANY($1::uuid[])
+		   AND i.status IN ('pending', 'accepted', 'declined')
+		   AND i.email <> ''
+		 ORDER BY i.meeting_id, i.created_at`, meetingIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]ical.Attendee)
+	for rows.Next() {
+		var meetingID, status string
+		var a ical.Attendee
+		if err := rows.Scan(&meetingID, &a.Email, &a.Name, &status); err != nil {
+			return nil, err
+		}
+		switch status {
+		case "accepted":
+			a.PartStat = "ACCEPTED"
+		case "declined":
+			a.PartStat = "DECLINED"
+		default:
+			a.PartStat = "NEEDS-ACTION"
+		}
+		out[meetingID] = append(out[meetingID], a)
+	}
+	return out, rows.Err()
+}
+
+func (s *AppService) loadCalendarExceptions(ctx context.Context, seriesIDs []string) (map[string][]time.Time, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT series_id::text, occurrence_start
+		  FROM meeting_recurrence_exceptions
+		 WHERE series_id = ANY($1::uuid[])
+		 ORDER BY series_id, occurrence_start`, seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]time.Time)
+	for rows.Next() {
+		var (
+			seriesID string
+			start    time.Time
+		)
+		if err := rows.Scan(&seriesID, &start); err != nil {
+			return nil, err
+		}
+		out[seriesID] = append(out[seriesID], start)
+	}
+	return out, rows.Err()
+}
+
+// meetingEvent converts a meeting to a VEVENT. Series and their occurrences
+// are written in the series' time zone so that RRULE, EXDATE and
+// RECURRENCE-ID line up across DST changes; one-off meetings use UTC.
+func (s *AppService) meetingEvent(m calendarMeeting) ical.Event {
+	loc := time.UTC
+	if m.RRule != "" || m.SeriesSlug != "" {
+		if zone, err := time.LoadLocation(m.TimeZone); err == nil {
+			loc = zone
+		}
+	}
+
+	ev := ical.Event{
+		UID:          s.calendarUID(m.Slug),
+		Sequence:     m.Sequence,
+		Stamp:        time.Now(),
+		LastModified: m.UpdatedAt,
+		Start:        m.Start.In(loc),
+		Duration:     time.Duration(m.DurationMinutes) * time.Minute,
+		Summary:      m.Title,
+		RRule:        m.RRule,
+		ExDates:      m.Exceptions,
+		Attendees:    m.Attendees,
+		Status:       ical.StatusConfirmed,
+	}
+	if m.SeriesSlug != "" {
+		ev.UID = s.calendarUID(m.SeriesSlug)
+		ev.RecurrenceID = m.OccurrenceStart
+	}
+
+	// A series has no single room to join; each occurrence has its own
+	joinURL := ""
+	if m.RRule == "" {
+		joinURL = s.meetingJoinURL(m.Slug)
+		ev.URL = joinURL
+		ev.Location = joinURL
+	}
+	ev.Description = calendarDescription(m.Description, m.Agenda, joinURL)
+
+	if m.HostEmail != "" {
+		ev.Organizer = &ical.Attendee{Email: strings.ToLower(m.HostEmail), Name: m.HostName}
+	}
+	return ev
+}
+
+// calendarUID is the iCalendar UID of a meeting, scoped to the app's host
+func (s *AppService) calendarUID(slug string) string {
+	host := "localhost"
+	if u, err := url.Parse(s.appURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return slug + "@" + host
+}
+
+func (s *AppService) meetingJoinURL(slug string) string {
+	return fmt.Sprintf("%s/%s/meeting", strings.TrimRight(s.appURL, "/"), url.PathEscape(slug))
+}
+
+// calendarDescription combines the meeting description, agenda and join link
+func calendarDescription(description string, agenda []core.AgendaItem, joinURL string) string {
+	var parts []string
+	if d := strings.TrimSpace(description); d != "" {
+		parts = append(parts, d)
+	}
+	if len(agenda) > 0 {
+		var b strings.Builder
+		b.WriteString("Agenda:")
+		for i, item := range agenda {
+			fmt.Fprintf(&b, "\n%d. %s", i+1, item.Title)
+			if item.Duration > 0 {
+				fmt.Fprintf(&b, " (%d min)", item.Duration)
+			}
+			if d := strings.TrimSpace(item.Description); d != "" {
+				b.WriteString(" - " + d)
+			}
+		}
+		parts = append(parts, b.String())
+	}
+	if joinURL != "" {
+		parts = append(parts, "Join: "+joinURL)
+	}
+	return strings.Join(parts, "\n\n")
+}
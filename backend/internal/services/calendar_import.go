@@ -0,0 +1,497 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/ical"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// maxCalendarImportBytes bounds uploaded and fetched calendars
+	maxCalendarImportBytes = 2 << 20
+	// maxCalendarImportEvents bounds the work done by one import
+	maxCalendarImportEvents = 500
+	calendarFetchTimeout    = 15 * time.Second
+	calendarFetchRedirects  = 5
+	importedMeetingTitle    = "Imported meeting"
+)
+
+// Per-event outcomes of a calendar import
+const (
+	CalendarImportCreated   = "created"
+	CalendarImportUpdated   = "updated"
+	CalendarImportUnchanged = "unchanged"
+	CalendarImportCancelled = "cancelled"
+	CalendarImportSkipped   = "skipped"
+	CalendarImportFailed    = "failed"
+)
+
+var errPrivateAddress = errors.New("address is not publicly routable")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// netip does not count as private
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// importedMeeting is the stored state of a meeting matched by UID
+type importedMeeting struct {
+	ID              string
+	Slug            string
+	Status          string
+	Title           string
+	Description     string
+	StartTime       time.Time
+	DurationMinutes int
+	AiPersonaID     string
+	VoiceProfile    string
+}
+
+// ImportCalendar creates meetings hosted by userID from the events of an
+// iCalendar file or URL. Events are matched to earlier imports by UID, so
+// importing the same calendar again updates meetings instead of duplicating
+// them. Problems with single events are reported per event and do not fail
+// the import.
+func (s *AppService) ImportCalendar(ctx context.Context, userID string, req core.CalendarImportRequest) (*core.CalendarImportResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+
+	data, rawURL := req.ICS, strings.TrimSpace(req.URL)
+	switch {
+	case strings.TrimSpace(data) != "" && rawURL != "":
+		return nil, InvalidField("ics", "provide either ics or url, not both")
+	case strings.TrimSpace(data) == "" && rawURL == "":
+		return nil, InvalidField("ics", "ics or url is required")
+	case rawURL != "":
+		var err error
+		if data, err = s.fetchCalendar(ctx, rawURL); err != nil {
+			return nil, err
+		}
+	}
+	if len(data) > maxCalendarImportBytes {
+		return nil, InvalidField("ics", fmt.Sprintf("calendar must be at most %d MB", maxCalendarImportBytes>>20))
+	}
+
+	cal, err := ical.Parse(strings.NewReader(data))
+	if err != nil {
+		return nil, InvalidField("ics", err.Error())
+	}
+	if len(cal.Events) > maxCalendarImportEvents {
+		return nil, InvalidField("ics", fmt.Sprintf("calendar must have at most %d events", maxCalendarImportEvents))
+	}
+
+	var hostEmail string
+	if err := s.db.QueryRow(ctx, `SELECT LOWER(email) FROM app_users WHERE id::text = $1`, userID).Scan(&hostEmail); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, unauthorized("session_invalid", "authentication required")
+		}
+		return nil, err
+	}
+
+	resp := &core.CalendarImportResponse{Results: make([]core.CalendarImportResult, 0, len(cal.Events))}
+	for _, ev := range cal.Events {
+		result, err := s.importEvent(ctx, userID, hostEmail, ev, req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// importEvent creates, updates or cancels the meeting for one event. Only
+// unexpected errors are returned; domain errors become a failed result.
+func (s *AppService) importEvent(ctx context.Context, userID, hostEmail string, ev ical.Event, req core.CalendarImportRequest) (core.CalendarImportResult, error) {
+	result := core.CalendarImportResult{UID: ev.UID, Title: ev.Summary}
+	skip := func(reason string) (core.CalendarImportResult, error) {
+		result.Status = CalendarImportSkipped
+		result.Reason = reason
+		return result, nil
+	}
+	switch {
+	case ev.UID == "":
+		return skip("event has no UID")
+	case !ev.RecurrenceID.IsZero():
+		return skip("changes to single occurrences of a recurring event are not imported")
+	case ev.AllDay:
+		return skip("all-day events are not imported")
+	}
+
+	existing, err := s.findImportedMeeting(ctx, userID, ev.UID)
+	if err != nil {
+		return result, err
+	}
+
+	if ev.Status == ical.StatusCancelled {
+		if existing == nil {
+			return skip("event is cancelled")
+		}
+		if err := s.DeleteMeeting(ctx, existing.Slug); err != nil {
+			return importFailure(result, err)
+		}
+		result.Status = CalendarImportCancelled
+		result.MeetingID = existing.Slug
+		return result, nil
+	}
+
+	if ev.Start.IsZero() {
+		result.Status = CalendarImportFailed
+		result.Reason = "event has no start time"
+		return result, nil
+	}
+
+	title := strings.TrimSpace(ev.Summary)
+	if title == "" {
+		title = importedMeetingTitle
+	}
+	start := ev.Start.Truncate(time.Second)
+	duration := int(ev.Duration / time.Minute)
+
+	var rec *core.MeetingRecurrence
+	if ev.RRule != "" {
+		// Zones that only came from a VTIMEZONE have a fixed offset and no
+		// IANA name; expanding in UTC gives the same instants
+		zone := start.Location().String()
+		if _, err := time.LoadLocation(zone); err != nil {
+			zone = "UTC"
+		}
+		rec = &core.MeetingRecurrence{RRule: ev.RRule, TimeZone: zone, Exceptions: ev.ExDates}
+	}
+
+	if existing == nil {
+		detail, err := s.createMeeting(ctx, core.MeetingCreateRequest{
+			Title:           title,
+			Description:     ev.Description,
+			StartTime:       start,
+			DurationMinutes: duration,
+			VoiceProfile:    req.VoiceProfile,
+			AiPersonaID:     req.AiPersonaID,
+			Recurrence:      rec,
+		}, userID, ev.UID)
+		if err != nil {
+			return importFailure(result, err)
+		}
+		result.Status = CalendarImportCreated
+		result.MeetingID = detail.Summary.ID
+	} else {
+		result.MeetingID = existing.Slug
+		if existing.Status != "scheduled" && existing.Status != meetingStatusSeries {
+			return skip("meeting has already started")
+		}
+		update, changed, err := s.importedMeetingUpdate(ctx, existing, title, ev.Description, start, duration, rec, req)
+		if err != nil {
+			return importFailure(result, err)
+		}
+		result.Status = CalendarImportUnchanged
+		if changed {
+			if _, err := s.UpdateMeeting(ctx, existing.Slug, update); err != nil {
+				return importFailure(result, err)
+			}
+			result.Status = CalendarImportUpdated
+		}
+	}
+
+	added, err := s.addImportedInvites(ctx, result.MeetingID, userID, hostEmail, ev.Attendees)
+	if err != nil {
+		return result, err
+	}
+	if added > 0 && result.Status == CalendarImportUnchanged {
+		result.Status = CalendarImportUpdated
+	}
+	return result, nil
+}
+
+// importFailure reports domain errors as a failed event and passes other
+// errors through
+func importFailure(result core.CalendarImportResult, err error) (core.CalendarImportResult, error) {
+	var (
+		domainErr     *Error
+		validationErr *ValidationError
+	)
+	if errors.As(err, &domainErr) || errors.As(err, &validationErr) {
+		result.Status = CalendarImportFailed
+		result.Reason = err.Error()
+		return result, nil
+	}
+	return result, err
+}
+
+// findImportedMeeting finds the meeting an event was imported into. UIDs of
+// this app's own feed map back to the meeting they were exported from.
+func (s *AppService) findImportedMeeting(ctx context.Context, userID, uid string) (*importedMeeting, error) {
+	ownSlug := ""
+	if slug, ok := strings.CutSuffix(uid, s.calendarUID("")); ok && slug != "" {
+		ownSlug = slug
+	}
+
+	var m importedMeeting
+	if err := s.db.QueryRow(ctx, `
+		SELECT id::text,
+		       COALESCE(external_id, id::text),
+		       status,
+		       title,
+		       COALESCE(description, ''),
+		       start_time,
+		       duration_minutes,
+		       COALESCE(ai_persona_id, ''),
+		       COALESCE(voice_profile, '')
+		  FROM meetings
+		 WHERE host_user_id::text = $1
+		   AND (ical_uid = $2 OR COALESCE(external_id, id::text) = $3)
+		 ORDER BY ical_uid NULLS LAST
+		 LIMIT 1`, userID, uid, ownSlug,
+	).Scan(&m.ID, &m.Slug, &m.Status, &m.Title, &m.Description, &m.StartTime, &m.DurationMinutes, &m.AiPersonaID, &m.VoiceProfile); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// importedMeetingUpdate builds an update with only the fields an event
+// changed, so re-importing an unchanged calendar leaves meetings, and stored
+// occurrences of series, alone
+func (s *AppService) importedMeetingUpdate(ctx context.Context, existing *importedMeeting, title, description string, start time.Time, duration int, rec *core.MeetingRecurrence, req core.CalendarImportRequest) (core.MeetingUpdateRequest, bool, error) {
+	var (
+		update  core.MeetingUpdateRequest
+		changed bool
+	)
+	if title != existing.Title {
+		update.Title = &title
+		changed = true
+	}
+	if strings.TrimSpace(description) != existing.Description {
+		update.Description = &description
+		changed = true
+	}
+	if !start.Equal(existing.StartTime) {
+		update.StartTime = &start
+		changed = true
+	}
+	if duration > 0 && duration != existing.DurationMinutes {
+		update.DurationMinutes = &duration
+		changed = true
+	}
+	if persona := strings.TrimSpace(req.AiPersonaID); persona != "" && persona != existing.AiPersonaID {
+		update.AiPersonaID = &persona
+		changed = true
+	}
+	if voice := strings.TrimSpace(req.VoiceProfile); voice != "" && voice != existing.VoiceProfile {
+		update.VoiceProfile = &voice
+		changed = true
+	}
+
+	isSeries := existing.Status == meetingStatusSeries
+	switch {
+	case isSeries && rec == nil:
+		return update, false, InvalidField("recurrence", "recurrence cannot be removed from an imported recurring meeting")
+	case !isSeries && rec != nil:
+		return update, false, InvalidField("recurrence", "an existing meeting cannot be made recurring")
+	case isSeries:
+		update.Scope = UpdateScopeSeries
+		series, err := loadSeries(ctx, s.db, existing.Slug)
+		if err != nil {
+			return update, false, err
+		}
+		rule, loc, err := parseRecurrence(rec)
+		if err != nil {
+			return update, false, err
+		}
+		if rule.String() != series.Rule.String() || loc.String() != series.Location.String() || !sameInstants(rec.Exceptions, series.Exceptions) {
+			update.Recurrence = rec
+			changed = true
+		}
+	}
+	return update, changed, nil
+}
+
+func sameInstants(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[int64]int, len(a))
+	for _, t := range a {
+		seen[t.Unix()]++
+	}
+	for _, t := range b {
+		if seen[t.Unix()] == 0 {
+			return false
+		}
+		seen[t.Unix()]--
+	}
+	return true
+}
+
+// addImportedInvites invites event attendees who are not yet invited and
+// returns how many invites were created. Attendees that already answered in
+// their calendar keep that answer; the others with an account are notified.
+func (s *AppService) addImportedInvites(ctx context.Context, slug, userID, hostEmail string, attendees []ical.Attendee) (int, error) {
+	added := 0
+	var invitees []string
+	seen := map[string]bool{hostEmail: true}
+	for _, a := range attendees {
+		email := strings.ToLower(strings.TrimSpace(a.Email))
+		if seen[email] || !strings.Contains(email, "@") {
+			continue
+		}
+		seen[email] = true
+
+		status := "pending"
+		switch a.PartStat {
+		case "ACCEPTED":
+			status = "accepted"
+		case "DECLINED":
+			status = "declined"
+		}
+		token, err := generateRandomHex(32)
+		if err != nil {
+			return added, err
+		}
+
+		var inviteeID string
+		err = s.db.QueryRow(ctx, `
+			INSERT INTO meeting_invites (meeting_id, invited_by_user_id, invitee_user_id, email, invite_token, status, accepted_at)
+			SELECT m.id,
+			       $2::uuid,
+			       (SELECT u.id FROM app_users u WHERE LOWER(u.email) = $3 LIMIT 1),
+			       $3,
+			       $4,
+			       $5,
+			       CASE WHEN $5 = 'accepted' THEN NOW() END
+			  FROM meetings m
+			 WHERE COALESCE(m.external_id, m.id::text) = $1
+			   AND NOT EXISTS (
+				SELECT 1
+				  FROM meeting_invites i
+				 WHERE i.meeting_id = m.id
+				   AND LOWER(i.email) = $3
+			   )
+			RETURNING COALESCE(invitee_user_id::text, '')`, slug, userID, email, token, status).Scan(&inviteeID)
+		if err == pgx.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return added, err
+		}
+		added++
+		// Declined attendees already answered and need no notice
+		if inviteeID != "" && status != "declined" {
+			invitees = append(invitees, inviteeID)
+		}
+	}
+	if len(invitees) > 0 {
+		s.notifyAsync(ctx, func(ctx context.Context) {
+			s.notifyInvited(ctx, slug, userID, invitees)
+		})
+	}
+	return added, nil
+}
+
+// fetchCalendar downloads an ICS URL. Unless allowed by configuration, only
+// public addresses are contacted, checked at connect time so that DNS
+// answers and redirects cannot reach internal services.
+func (s *AppService) fetchCalendar(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", InvalidField("url", "url must be an absolute http, https or webcal URL")
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+	case "webcal", "webcals":
+		u.Scheme = "https"
+	default:
+		return "", InvalidField("url", "url must be an absolute http, https or webcal URL")
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !s.allowPrivateCalendarURLs {
+		dialer.Control = publicAddrControl
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Timeout:   calendarFetchTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// via includes the original request
+			if len(via) > calendarFetchRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", InvalidField("url", "url is invalid")
+	}
+	httpReq.Header.Set("Accept", "text/calendar")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, errPrivateAddress) {
+			return "", InvalidField("url", "url must point to a public address")
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", InvalidField("url", "calendar could not be fetched")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", InvalidField("url", fmt.Sprintf("calendar url returned HTTP %d", resp.StatusCode))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarImportBytes+1))
+	if err != nil {
+		return "", InvalidField("url", "calendar could not be fetched")
+	}
+	if len(body) > maxCalendarImportBytes {
+		return "", InvalidField("url", fmt.Sprintf("calendar must be at most %d MB", maxCalendarImportBytes>>20))
+	}
+	return string(body), nil
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// publicAddrControl is a net.Dialer Control func that refuses to connect to
+// addresses that are not publicly routable
+func publicAddrControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !isPublicAddr(addr) {
+		return errPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error kinds. Handlers map these to HTTP statuses with errors.Is, so callers
+// never depend on message text.
+var (
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnavailable  = errors.New("unavailable")
+)
+
+// Error is a domain error with a stable machine-readable code. Message is
+// safe to show to clients; Code is part of the API contract.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports one or more invalid request fields
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// InvalidField returns a ValidationError for a single field
+func InvalidField(field, message string) error {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func unauthorized(code, message string) error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+func forbidden(code, message string) error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func notFound(code, message string) error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func conflict(code, message string) error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func unavailable(code, message string) error {
+	return &Error{Kind: ErrUnavailable, Code: code, Message: message}
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// Meeting event types
+const (
+	MeetingEventStarted           = "meeting.started"
+	MeetingEventEnded             = "meeting.ended"
+	MeetingEventParticipantJoined = "participant.joined"
+	MeetingEventParticipantLeft   = "participant.left"
+	MeetingEventAgendaProgress    = "agenda.progress"
+	// Logged by a database trigger when the transcription pipeline stores a
+	// section (see migration 0020)
+	MeetingEventTranscriptSection = "transcript.section"
+	MeetingEventLobbyRequested    = "lobby.requested"
+	MeetingEventLobbyDecided      = "lobby.decided"
+)
+
+const (
+	// meetingEventsChannel is the Redis channel announcing which meeting has
+	// new events; the events themselves are read from Postgres
+	meetingEventsChannel = "meeting_events"
+	// meetingEventCatchUpInterval is how often an idle stream checks for
+	// events it was not told about, such as transcript sections
+	meetingEventCatchUpInterval = 5 * time.Second
+	meetingEventPageSize        = 200
+)
+
+// eventPermissions restricts event types to roles with a permission; the
+// other types go to everyone following the meeting
+var eventPermissions = map[string]string{
+	MeetingEventTranscriptSection: PermissionViewTranscript,
+	MeetingEventLobbyRequested:    PermissionAdmit,
+	MeetingEventLobbyDecided:      PermissionAdmit,
+}
+
+// meetingEventHub wakes the streams of this replica when a meeting has new
+// events
+type meetingEventHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func newMeetingEventHub() *meetingEventHub {
+	return &meetingEventHub{subs: make(map[string]map[chan struct{}]struct{})}
+}
+
+func (h *meetingEventHub) subscribe(meetingID string) (chan struct{}, func()) {
+	wake := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[meetingID] == nil {
+		h.subs[meetingID] = make(map[chan struct{}]struct{})
+	}
+	h.subs[meetingID][wake] = struct{}{}
+	h.mu.Unlock()
+
+	return wake, func() {
+		h.mu.Lock()
+		delete(h.subs[meetingID], wake)
+		if len(h.subs[meetingID]) == 0 {
+			delete(h.subs, meetingID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *meetingEventHub) notify(meetingID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for wake := range h.subs[meetingID] {
+		// A pending wake-up already covers this event
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// meetingEventBus tells the streams of every replica that a meeting has new
+// events
+type meetingEventBus interface {
+	Notify(ctx context.Context, meetingID string) error
+	// Listen starts delivering notifications to the local hub
+	Listen()
+}
+
+// localMeetingEventBus is the fallback when Redis is not configured; only
+// streams on this replica are notified
+type localMeetingEventBus struct {
+	hub *meetingEventHub
+}
+
+func (b *localMeetingEventBus) Notify(_ context.Context, meetingID string) error {
+	b.hub.notify(meetingID)
+	return nil
+}
+
+func (b *localMeetingEventBus) Listen() {}
+
+// redisMeetingEventBus fans notifications out to all replicas over Redis
+// pub/sub. Each replica holds one subscription, opened with its first stream.
+type redisMeetingEventBus struct {
+	client *redis.Client
+	hub    *meetingEventHub
+	once   sync.Once
+}
+
+func (b *redisMeetingEventBus) Notify(ctx context.Context, meetingID string) error {
+	return b.client.Publish(ctx, meetingEventsChannel, meetingID).Err()
+}
+
+func (b *redisMeetingEventBus) Listen() {
+	b.once.Do(func() {
+		// Runs until the client is closed; go-redis resubscribes after
+		// connection errors
+		pubsub := b.client.Subscribe(context.Background(), meetingEventsChannel)
+		go func() {
+			for msg := range pubsub.Channel() {
+				b.hub.notify(msg.Payload)
+			}
+		}()
+	})
+}
+
+// recordMeetingEvent appends an event to a meeting's log. Callers notify
+// streams with notifyMeetingEvents once their transaction has committed.
+// The insert locks the meeting row until the transaction ends (see migration
+// 0024), so each meeting's event ids become visible in increasing order.
+func recordMeetingEvent(ctx context.Context, q querier, meetingID, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO meeting_events (meeting_id, type, data)
+		VALUES ($1::uuid, $2, $3::jsonb)`, meetingID, eventType, string(payload))
+	return err
+}
+
+// notifyMeetingEvents wakes the streams following a meeting. Streams also
+// catch up on their own, so a lost notification only delays events.
+func (s *AppService) notifyMeetingEvents(meetingID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = s.events.Notify(ctx, meetingID)
+}
+
+// publishMeetingEvent records an event outside of a transaction and notifies
+// streams
+func (s *AppService) publishMeetingEvent(ctx context.Context, meetingID, eventType string, data any) error {
+	if err := recordMeetingEvent(ctx, s.db, meetingID, eventType, data); err != nil {
+		return err
+	}
+	s.notifyMeetingEvents(meetingID)
+	return nil
+}
+
+// MeetingEventStream follows the events of one meeting for one user
+type MeetingEventStream struct {
+	svc         *AppService
+	meetingID   string
+	slug        string
+	role        string
+	lastID      int64
+	behind      bool
+	wake        chan struct{}
+	unsubscribe func()
+}
+
+// SubscribeMeetingEvents opens a user's event stream for a meeting. Access
+// follows JoinMeeting: participants, invitees and, for public meetings,
+// everyone, except users the host has not admitted from the lobby. A stream
+// resumes after lastEventID; without one it starts with the next event.
+func (s *AppService) SubscribeMeetingEvents(ctx context.Context, identifier, userID string, lastEventID int64) (*MeetingEventStream, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		slug       string
+		visibility string
+		seriesID   string
+		lobby      bool
+	)
+	if err := s.db.QueryRow(ctx, `
+		SELECT COALESCE(external_id, id::text),
+		       COALESCE(visibility, 'private'),
+		       COALESCE(series_id::text, ''),
+		       lobby_enabled
+		  FROM meetings
+		 WHERE id = $1::uuid`, meetingID).Scan(&slug, &visibility, &seriesID, &lobby); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("meeting_not_found", "meeting not found")
+		}
+		return nil, err
+	}
+
+	role, err := meetingRole(ctx, s.db, meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		admission, err := participantAdmission(ctx, s.db, meetingID, userID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case admission == AdmissionDenied:
+			return nil, forbidden("lobby_denied", "the host declined your request to join")
+		case lobby && admission != AdmissionAdmitted:
+			return nil, forbidden("lobby_waiting", "the host has not admitted you yet")
+		case visibility == MeetingVisibilityPrivate:
+			invited, err := isMeetingInvitee(ctx, s.db, meetingID, seriesID, userID)
+			if err != nil {
+				return nil, err
+			}
+			if !invited {
+				return nil, forbidden("not_invited", "not invited to this meeting")
+			}
+		}
+	}
+
+	if lastEventID <= 0 {
+		if err := s.db.QueryRow(ctx, `
+			SELECT COALESCE(MAX(id), 0)
+			  FROM meeting_events
+			 WHERE meeting_id = $1::uuid`, meetingID).Scan(&lastEventID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.events.Listen()
+	wake, unsubscribe := s.eventHub.subscribe(meetingID)
+	return &MeetingEventStream{
+		svc:         s,
+		meetingID:   meetingID,
+		slug:        slug,
+		role:        role,
+		lastID:      lastEventID,
+		behind:      true,
+		wake:        wake,
+		unsubscribe: unsubscribe,
+	}, nil
+}
+
+// Next waits for new events. It returns an empty slice when the stream was
+// idle for a while, so callers can send a keep-alive.
+func (st *MeetingEventStream) Next(ctx context.Context) ([]core.MeetingEvent, error) {
+	if !st.behind {
+		timer := time.NewTimer(meetingEventCatchUpInterval)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-st.wake:
+		case <-timer.C:
+		}
+	}
+	return st.fetch(ctx)
+}
+
+// Close stops the stream
+func (st *MeetingEventStream) Close() {
+	st.unsubscribe()
+}
+
+// fetch reads the events after the last one seen. Ids of one meeting commit in
+// order, so an event with a lower id cannot show up after a higher one was read.
+func (st *MeetingEventStream) fetch(ctx context.Context) ([]core.MeetingEvent, error) {
+	rows, err := st.svc.db.Query(ctx, `
+		SELECT id, type, data::text, created_at
+		  FROM meeting_events
+		 WHERE meeting_id = $1::uuid
+		   AND id > $2
+		 ORDER BY id
+		 LIMIT $3`, st.meetingID, st.lastID, meetingEventPageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]core.MeetingEvent, 0)
+	count := 0
+	for rows.Next() {
+		var (
+			ev   core.MeetingEvent
+			data string
+		)
+		if err := rows.Scan(&ev.ID, &ev.Type, &data, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		count++
+		st.lastID = ev.ID
+		if permission, ok := eventPermissions[ev.Type]; ok && !RoleAllows(st.role, permission) {
+			continue
+		}
+		ev.MeetingID = st.slug
+		ev.Data = json.RawMessage(data)
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	st.behind = count == meetingEventPageSize
+	return events, nil
+}
+
+// isMeetingInvitee reports whether a user holds an open invite to a meeting,
+// matched by user or email. Invites to a recurring meeting cover all of its
+// occurrences.
+func isMeetingInvitee(ctx context.Context, q querier, meetingID, seriesID, userID string) (bool, error) {
+	var userEmail string
+	if err := q.QueryRow(ctx, `
+		SELECT LOWER(email)
+		  FROM app_users
+		 WHERE id::text = $1
+		 LIMIT 1`,
+		userID,
+	).Scan(&userEmail); err != nil && err != pgx.ErrNoRows {
+		return false, err
+	}
+
+	var invited bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1
+			  FROM meeting_invites
+			 WHERE meeting_id::text IN ($1, $4)
+			   AND status IN ('pending', 'accepted')
+			   AND (
+				 invitee_user_id::text = $2
+				 OR (email <> '' AND LOWER(email) = $3)
+			   )
+		)`,
+		meetingID, userID, userEmail, seriesID,
+	).Scan(&invited)
+	return invited, err
+}
@@ -0,0 +1,566 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	MeetingVisibilityPrivate = "private"
+	MeetingVisibilityPublic  = "public"
+
+	GuestStatusWaiting  = "waiting"
+	GuestStatusAdmitted = "admitted"
+	GuestStatusDenied   = "denied"
+
+	// GuestSessionTTL is how long a guest identity, and the media token
+	// issued for it, stays valid
+	GuestSessionTTL = time.Hour
+
+	maxGuestNameLength = 60
+	// maxMeetingGuests caps the guests waiting in or admitted to a meeting
+	maxMeetingGuests = 100
+)
+
+// guestLinkMeeting is the meeting behind a guest link
+type guestLinkMeeting struct {
+	ID              string
+	Slug            string
+	Title           string
+	HostName        string
+	StartTime       time.Time
+	DurationMinutes int
+	Status          string
+	Locked          bool
+	// ApprovalRequired is set when guests need approval or the lobby is on
+	ApprovalRequired bool
+}
+
+// normalizeVisibility validates a meeting visibility, defaulting to private
+func normalizeVisibility(visibility string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(visibility)); v {
+	case "":
+		return MeetingVisibilityPrivate, nil
+	case MeetingVisibilityPrivate, MeetingVisibilityPublic:
+		return v, nil
+	default:
+		return "", InvalidField("visibility", "visibility must be private or public")
+	}
+}
+
+// GetMeetingAccess returns who may enter a meeting
+func (s *AppService) GetMeetingAccess(ctx context.Context, identifier string) (*core.MeetingAccessSettings, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return loadMeetingAccess(ctx, s.db, meetingID)
+}
+
+// UpdateMeetingAccess changes a meeting's visibility, lock, guest approval and
+// lobby. Making a meeting private revokes its guest link and turns away guests
+// that are still waiting; turning the lobby off admits everyone in it.
+func (s *AppService) UpdateMeetingAccess(ctx context.Context, identifier string, req core.UpdateMeetingAccessRequest) (*core.MeetingAccessSettings, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	setClauses := make([]string, 0, 4)
+	args := make([]any, 0, 5)
+	if req.Visibility != nil {
+		visibility, err := normalizeVisibility(*req.Visibility)
+		if err != nil {
+			return nil, err
+		}
+		setClauses = append(setClauses, fmt.Sprintf("visibility = $%d", len(args)+1))
+		args = append(args, visibility)
+
+		if visibility == MeetingVisibilityPrivate {
+			if _, err := tx.Exec(ctx, `DELETE FROM meeting_guest_links WHERE meeting_id = $1::uuid`, meetingID); err != nil {
+				return nil, err
+			}
+			if _, err := tx.Exec(ctx, `
+				UPDATE meeting_guests
+				   SET status = $2,
+				       decided_at = NOW()
+				 WHERE meeting_id = $1::uuid
+				   AND status = $3`, meetingID, GuestStatusDenied, GuestStatusWaiting); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.Locked != nil {
+		setClauses = append(setClauses, fmt.Sprintf("locked = $%d", len(args)+1))
+		args = append(args, *req.Locked)
+	}
+	if req.RequireGuestApproval != nil {
+		setClauses = append(setClauses, fmt.Sprintf("guest_approval_required = $%d", len(args)+1))
+		args = append(args, *req.RequireGuestApproval)
+	}
+	if req.LobbyEnabled != nil {
+		setClauses = append(setClauses, fmt.Sprintf("lobby_enabled = $%d", len(args)+1))
+		args = append(args, *req.LobbyEnabled)
+
+		if !*req.LobbyEnabled {
+			if _, err := tx.Exec(ctx, `
+				UPDATE meeting_participants
+				   SET admission_status = $2,
+				       decided_at = NOW()
+				 WHERE meeting_id = $1::uuid
+				   AND admission_status = $3`, meetingID, AdmissionAdmitted, AdmissionWaiting); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(setClauses) > 0 {
+		setClauses = append(setClauses, "updated_at = NOW()")
+		args = append(args, meetingID)
+		query := fmt.Sprintf("UPDATE meetings SET %s WHERE id = $%d::uuid", strings.Join(setClauses, ", "), len(args))
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	access, err := loadMeetingAccess(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// RotateGuestLink issues a new shareable join link for a public meeting and
+// invalidates the previous one. Only a hash is stored, so the link can be
+// shown once.
+func (s *AppService) RotateGuestLink(ctx context.Context, identifier, userID string) (*core.GuestLinkResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var status, visibility string
+	if err := tx.QueryRow(ctx, `
+		SELECT status, COALESCE(visibility, 'private')
+		  FROM meetings
+		 WHERE id = $1::uuid`, meetingID).Scan(&status, &visibility); err != nil {
+		return nil, err
+	}
+	if err := closedMeetingError(status); err != nil {
+		return nil, err
+	}
+	switch {
+	case status == meetingStatusSeries:
+		return nil, conflict("meeting_is_series", "share an occurrence of this recurring meeting instead")
+	case visibility != MeetingVisibilityPublic:
+		return nil, conflict("meeting_not_public", "only public meetings can be joined by guests")
+	}
+
+	token, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO meeting_guest_links (meeting_id, token_hash, created_by)
+		VALUES ($1::uuid, $2, NULLIF($3, '')::uuid)
+		ON CONFLICT (meeting_id) DO UPDATE
+		   SET token_hash = EXCLUDED.token_hash,
+		       created_by = EXCLUDED.created_by,
+		       created_at = NOW()`, meetingID, hashRefreshToken(token), strings.TrimSpace(userID)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &core.GuestLinkResponse{
+		URL: fmt.Sprintf("%s/join/%s", strings.TrimRight(s.appURL, "/"), token),
+	}, nil
+}
+
+// RevokeGuestLink disables a meeting's guest link. Guests that already
+// joined keep their identity until it expires.
+func (s *AppService) RevokeGuestLink(ctx context.Context, identifier string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(ctx, `DELETE FROM meeting_guest_links WHERE meeting_id = $1::uuid`, meetingID)
+	return err
+}
+
+// ListMeetingGuests returns the guests of a meeting whose identity has not
+// expired, oldest first
+func (s *AppService) ListMeetingGuests(ctx context.Context, identifier string) (*core.MeetingGuestsResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, guestSelect+`
+		 WHERE meeting_id = $1::uuid
+		   AND expires_at > NOW()
+		 ORDER BY created_at`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	guests := make([]core.MeetingGuest, 0)
+	for rows.Next() {
+		guest, err := scanGuest(rows)
+		if err != nil {
+			return nil, err
+		}
+		guests = append(guests, *guest)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &core.MeetingGuestsResponse{Guests: guests}, nil
+}
+
+// DecideMeetingGuest admits or denies a guest. Denying an admitted guest
+// stops them from getting new media tokens.
+func (s *AppService) DecideMeetingGuest(ctx context.Context, identifier, guestID string, req core.UpdateMeetingGuestRequest) (*core.MeetingGuest, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	if status != GuestStatusAdmitted && status != GuestStatusDenied {
+		return nil, InvalidField("status", "status must be admitted or denied")
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	guest, err := scanGuest(s.db.QueryRow(ctx, `
+		UPDATE meeting_guests
+		   SET status = $3,
+		       decided_at = NOW()
+		 WHERE meeting_id = $1::uuid
+		   AND id::text = $2
+		   AND expires_at > NOW()
+		RETURNING id::text, display_name, status, created_at, decided_at, expires_at`,
+		meetingID, strings.TrimSpace(guestID), status))
+	if err == pgx.ErrNoRows {
+		return nil, notFound("guest_not_found", "guest not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publishMeetingEvent(ctx, meetingID, MeetingEventLobbyDecided, core.LobbyEvent{
+		ParticipantID: GuestParticipantID(guest.ID),
+		Name:          guest.DisplayName,
+		Status:        guest.Status,
+		Guest:         true,
+	}); err != nil {
+		return nil, err
+	}
+	return guest, nil
+}
+
+// GuestMeetingPreview describes the meeting behind a guest link
+func (s *AppService) GuestMeetingPreview(ctx context.Context, linkToken string) (*core.GuestMeetingPreview, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	m, err := s.guestLinkMeeting(ctx, linkToken)
+	if err != nil {
+		return nil, err
+	}
+	return &core.GuestMeetingPreview{
+		Title:                m.Title,
+		HostName:             m.HostName,
+		StartTime:            m.StartTime,
+		DurationMinutes:      m.DurationMinutes,
+		Status:               m.Status,
+		Locked:               m.Locked,
+		RequireGuestApproval: m.ApprovalRequired,
+	}, nil
+}
+
+// JoinAsGuest creates a short-lived guest identity through a guest link. The
+// guest waits for the host when the meeting requires approval; join details
+// are included once the guest is admitted and the meeting is running.
+func (s *AppService) JoinAsGuest(ctx context.Context, linkToken string, req core.GuestJoinRequest) (*core.GuestJoinResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.DisplayName)
+	switch n := utf8.RuneCountInString(name); {
+	case n == 0:
+		return nil, InvalidField("displayName", "displayName is required")
+	case n > maxGuestNameLength:
+		return nil, InvalidField("displayName", fmt.Sprintf("displayName must be at most %d characters", maxGuestNameLength))
+	}
+
+	m, err := s.guestLinkMeeting(ctx, linkToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := closedMeetingError(m.Status); err != nil {
+		return nil, err
+	}
+	if m.Locked {
+		return nil, conflict("meeting_locked", "the host has locked this meeting")
+	}
+
+	status := GuestStatusAdmitted
+	if m.ApprovalRequired {
+		status = GuestStatusWaiting
+	}
+	token, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Concurrent joins wait on the meeting row, so the guest limit holds
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM meetings WHERE id = $1::uuid FOR UPDATE`, m.ID); err != nil {
+		return nil, err
+	}
+	var active int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		  FROM meeting_guests
+		 WHERE meeting_id = $1::uuid
+		   AND status <> $2
+		   AND expires_at > NOW()`, m.ID, GuestStatusDenied).Scan(&active); err != nil {
+		return nil, err
+	}
+	if active >= maxMeetingGuests {
+		return nil, conflict("guest_limit_reached", fmt.Sprintf("a meeting can have at most %d guests", maxMeetingGuests))
+	}
+
+	guest, err := scanGuest(tx.QueryRow(ctx, `
+		INSERT INTO meeting_guests (meeting_id, display_name, token_hash, status, decided_at, expires_at)
+		VALUES ($1::uuid, $2, $3, $4, CASE WHEN $4 = 'waiting' THEN NULL ELSE NOW() END, $5)
+		RETURNING id::text, display_name, status, created_at, decided_at, expires_at`,
+		m.ID, name, hashRefreshToken(token), status, time.Now().Add(GuestSessionTTL)))
+	if err != nil {
+		return nil, err
+	}
+
+	if guest.Status == GuestStatusWaiting {
+		err = recordMeetingEvent(ctx, tx, m.ID, MeetingEventLobbyRequested, core.LobbyEvent{
+			ParticipantID: GuestParticipantID(guest.ID),
+			Name:          guest.DisplayName,
+			Status:        guest.Status,
+			Guest:         true,
+		})
+	} else {
+		err = recordMeetingEvent(ctx, tx, m.ID, MeetingEventParticipantJoined, core.ParticipantEvent{
+			ParticipantID: GuestParticipantID(guest.ID),
+			Name:          guest.DisplayName,
+			Guest:         true,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.notifyMeetingEvents(m.ID)
+
+	resp := guestJoinResponse(m, guest)
+	resp.GuestToken = token
+	return resp, nil
+}
+
+// GuestStatus reports where a guest stands, including join details once the
+// guest is admitted and the meeting is running
+func (s *AppService) GuestStatus(ctx context.Context, linkToken, guestToken string) (*core.GuestJoinResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	guestToken = strings.TrimSpace(guestToken)
+	if guestToken == "" {
+		return nil, unauthorized("guest_token_missing", "guest token required")
+	}
+
+	m, err := s.guestLinkMeeting(ctx, linkToken)
+	if err != nil {
+		return nil, err
+	}
+
+	guest, err := scanGuest(s.db.QueryRow(ctx, guestSelect+`
+		 WHERE meeting_id = $1::uuid
+		   AND token_hash = $2`, m.ID, hashRefreshToken(guestToken)))
+	if err == pgx.ErrNoRows {
+		return nil, unauthorized("guest_token_invalid", "guest token is invalid")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(guest.ExpiresAt) {
+		return nil, unauthorized("guest_expired", "guest session has expired")
+	}
+	if guest.Status == GuestStatusDenied {
+		return nil, forbidden("guest_denied", "the host declined your request to join")
+	}
+	if err := closedMeetingError(m.Status); err != nil {
+		return nil, err
+	}
+	if m.Locked && guest.Status != GuestStatusAdmitted {
+		return nil, conflict("meeting_locked", "the host has locked this meeting")
+	}
+
+	return guestJoinResponse(m, guest), nil
+}
+
+// guestJoinResponse builds the response for a guest, with join details when
+// the guest can enter the meeting now
+func guestJoinResponse(m *guestLinkMeeting, guest *core.MeetingGuest) *core.GuestJoinResponse {
+	resp := &core.GuestJoinResponse{
+		GuestID:       guest.ID,
+		DisplayName:   guest.DisplayName,
+		Status:        guest.Status,
+		MeetingStatus: m.Status,
+		ExpiresAt:     guest.ExpiresAt,
+	}
+	if guest.Status == GuestStatusAdmitted && checkJoinable(m.Status, m.StartTime) == nil {
+		resp.Join = &core.MeetingJoinResponse{
+			MeetingID:     m.Slug,
+			ParticipantID: GuestParticipantID(guest.ID),
+			ExpiresAt:     guest.ExpiresAt,
+		}
+	}
+	return resp
+}
+
+// GuestParticipantID is the participant identity of a guest, kept apart from
+// user ids
+func GuestParticipantID(guestID string) string {
+	return "guest-" + guestID
+}
+
+// guestLinkMeeting resolves a guest link. Links of meetings that are no
+// longer public are treated as unknown.
+func (s *AppService) guestLinkMeeting(ctx context.Context, linkToken string) (*guestLinkMeeting, error) {
+	linkToken = strings.TrimSpace(linkToken)
+	if linkToken == "" {
+		return nil, notFound("guest_link_not_found", "guest link not found")
+	}
+
+	var m guestLinkMeeting
+	if err := s.db.QueryRow(ctx, `
+		SELECT m.id::text,
+		       COALESCE(m.external_id, m.id::text),
+		       m.title,
+		       COALESCE(u.name, ''),
+		       m.start_time,
+		       m.duration_minutes,
+		       m.status,
+		       m.locked,
+		       m.guest_approval_required OR m.lobby_enabled
+		  FROM meeting_guest_links l
+		  JOIN meetings m ON m.id = l.meeting_id
+		  LEFT JOIN app_users u ON u.id = m.host_user_id
+		 WHERE l.token_hash = $1
+		   AND m.visibility = $2`, hashRefreshToken(linkToken), MeetingVisibilityPublic).Scan(
+		&m.ID,
+		&m.Slug,
+		&m.Title,
+		&m.HostName,
+		&m.StartTime,
+		&m.DurationMinutes,
+		&m.Status,
+		&m.Locked,
+		&m.ApprovalRequired,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("guest_link_not_found", "guest link not found")
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+func loadMeetingAccess(ctx context.Context, q querier, meetingID string) (*core.MeetingAccessSettings, error) {
+	var access core.MeetingAccessSettings
+	if err := q.QueryRow(ctx, `
+		SELECT COALESCE(m.visibility, 'private'),
+		       m.locked,
+		       m.guest_approval_required,
+		       EXISTS(SELECT 1 FROM meeting_guest_links l WHERE l.meeting_id = m.id),
+		       m.lobby_enabled
+		  FROM meetings m
+		 WHERE m.id = $1::uuid`, meetingID).Scan(
+		&access.Visibility,
+		&access.Locked,
+		&access.RequireGuestApproval,
+		&access.GuestLinkActive,
+		&access.LobbyEnabled,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("meeting_not_found", "meeting not found")
+		}
+		return nil, err
+	}
+	return &access, nil
+}
+
+const guestSelect = `
+		SELECT id::text, display_name, status, created_at, decided_at, expires_at
+		  FROM meeting_guests`
+
+func scanGuest(row pgx.Row) (*core.MeetingGuest, error) {
+	var guest core.MeetingGuest
+	if err := row.Scan(
+		&guest.ID,
+		&guest.DisplayName,
+		&guest.Status,
+		&guest.CreatedAt,
+		&guest.DecidedAt,
+		&guest.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+	return &guest, nil
+}
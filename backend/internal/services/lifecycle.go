@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/metrics"
+	"github.com/jackc/pgx/v5"
+)
+
+// Final statuses set by the lifecycle worker besides "ended"
+const (
+	// MeetingStatusMissed marks a scheduled meeting that was never started
+	MeetingStatusMissed = "missed"
+	// MeetingStatusExpired marks an instant meeting that nobody joined
+	MeetingStatusExpired = "expired"
+)
+
+// MeetingLifecycleOptions controls RunMeetingLifecycle. Zero durations
+// disable the corresponding step, except MissedAfter, which is a grace period.
+type MeetingLifecycleOptions struct {
+	// MissedAfter is how long after its scheduled end a meeting that never
+	// started is closed as missed or expired
+	MissedAfter time.Duration
+	// AutoEndAfter is how far past its duration a running meeting may go
+	// before it is ended
+	AutoEndAfter time.Duration
+	// ReminderLead is how long before the start reminders are sent
+	ReminderLead time.Duration
+	// EventRetention is how long meeting events are kept for streams to
+	// resume from
+	EventRetention time.Duration
+}
+
+// MeetingLifecycleResult counts what one lifecycle pass changed
+type MeetingLifecycleResult struct {
+	Missed   int
+	Expired  int
+	Ended    int
+	Reminded int
+	// Transcripts counts transcripts announced to their meeting's attendees
+	Transcripts int
+	// EventsPurged counts meeting events removed after the retention period
+	EventsPurged int
+}
+
+// RunMeetingLifecycle closes meetings whose window has passed, ends meetings
+// that ran far past their duration, sends start reminders, announces new
+// transcripts and purges old meeting events. Every change is claimed with a conditional update, so replicas
+// can run it concurrently.
+func (s *AppService) RunMeetingLifecycle(ctx context.Context, opts MeetingLifecycleOptions) (MeetingLifecycleResult, error) {
+	var result MeetingLifecycleResult
+	if err := s.ensureDB(); err != nil {
+		return result, err
+	}
+
+	var err error
+	if result.Missed, result.Expired, err = s.closeUnstartedMeetings(ctx, opts.MissedAfter); err != nil {
+		return result, fmt.Errorf("close unstarted meetings: %w", err)
+	}
+	if opts.AutoEndAfter > 0 {
+		if result.Ended, err = s.autoEndMeetings(ctx, opts.AutoEndAfter); err != nil {
+			return result, fmt.Errorf("auto-end meetings: %w", err)
+		}
+	}
+	if opts.ReminderLead > 0 {
+		if result.Reminded, err = s.sendMeetingReminders(ctx, opts.ReminderLead); err != nil {
+			return result, fmt.Errorf("send meeting reminders: %w", err)
+		}
+	}
+	if result.Transcripts, err = s.announceTranscripts(ctx); err != nil {
+		return result, fmt.Errorf("announce transcripts: %w", err)
+	}
+	if opts.EventRetention > 0 {
+		if result.EventsPurged, err = s.purgeMeetingEvents(ctx, opts.EventRetention); err != nil {
+			return result, fmt.Errorf("purge meeting events: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// closeUnstartedMeetings marks scheduled meetings that were never started as
+// missed, and instant meetings that nobody joined as expired, once their
+// window plus grace has passed
+func (s *AppService) closeUnstartedMeetings(ctx context.Context, grace time.Duration) (int, int, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	missed, err := closeMeetings(ctx, tx, MeetingStatusMissed, `
+		UPDATE meetings
+		   SET status = $1,
+		       updated_at = NOW()
+		 WHERE status = 'scheduled'
+		   AND start_time + make_interval(mins => duration_minutes + $2) < NOW()
+		RETURNING id::text`, MeetingStatusMissed, int(grace.Minutes()))
+	if err != nil {
+		return 0, 0, err
+	}
+	expired, err := closeMeetings(ctx, tx, MeetingStatusExpired, `
+		UPDATE meetings m
+		   SET status = $1,
+		       updated_at = NOW()
+		 WHERE m.status = 'instant'
+		   AND m.start_time + make_interval(mins => m.duration_minutes + $2) < NOW()
+		   AND NOT EXISTS (
+			SELECT 1
+			  FROM meeting_participants p
+			 WHERE p.meeting_id = m.id
+			   AND p.joined_at IS NOT NULL
+		   )
+		RETURNING m.id::text`, MeetingStatusExpired, int(grace.Minutes()))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, err
+	}
+	for _, id := range append(missed, expired...) {
+		s.notifyMeetingEvents(id)
+	}
+	return len(missed), len(expired), nil
+}
+
+// autoEndMeetings ends active meetings, and instant meetings that were used,
+// once they run overrun past their duration
+func (s *AppService) autoEndMeetings(ctx context.Context, overrun time.Duration) (int, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	ended, err := closeMeetings(ctx, tx, "ended", `
+		UPDATE meetings m
+		   SET status = $1,
+		       ended_at = NOW(),
+		       updated_at = NOW()
+		 WHERE COALESCE(m.actual_started_at, m.start_time) + make_interval(mins => m.duration_minutes + $2) < NOW()
+		   AND (
+			m.status = 'active'
+			OR (m.status = 'instant' AND EXISTS (
+				SELECT 1
+				  FROM meeting_participants p
+				 WHERE p.meeting_id = m.id
+				   AND p.joined_at IS NOT NULL
+			))
+		   )
+		RETURNING m.id::text`, "ended", int(overrun.Minutes()))
+	if err != nil {
+		return 0, err
+	}
+	if len(ended) > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE meeting_participants
+			   SET left_at = NOW()
+			 WHERE meeting_id = ANY($1::uuid[])
+			   AND joined_at IS NOT NULL
+			   AND left_at IS NULL`, ended); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	for _, id := range ended {
+		s.notifyMeetingEvents(id)
+	}
+	metrics.MeetingsEnded.Add(float64(len(ended)))
+	s.notifyFeedbackRequested(ctx, ended)
+	return len(ended), nil
+}
+
+// purgeMeetingEvents deletes meeting events older than retention. A stream
+// resuming from a purged id continues with the oldest event that is left.
+func (s *AppService) purgeMeetingEvents(ctx context.Context, retention time.Duration) (int, error) {
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM meeting_events
+		 WHERE created_at < NOW() - make_interval(secs => $1)`, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// closeMeetings runs a status update returning meeting ids and logs the new
+// status on each meeting's event stream
+func closeMeetings(ctx context.Context, tx pgx.Tx, status, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if err := recordMeetingEvent(ctx, tx, id, MeetingEventEnded, core.MeetingStatusEvent{Status: status}); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// sendMeetingReminders notifies the host, participants and invitees of
+// meetings starting within lead. Invitees without an account are emailed when
+// a mail transport is configured.
+// Each meeting is claimed before sending, so a reminder goes out at most once
+// even when delivery fails.
+func (s *AppService) sendMeetingReminders(ctx context.Context, lead time.Duration) (int, error) {
+	now := time.Now()
+
+	// Upcoming occurrences of recurring meetings are stored so that they can
+	// carry their own reminder state
+	occurrences, err := s.listOccurrences(ctx, "", now, now.Add(lead))
+	if err != nil {
+		return 0, err
+	}
+	for _, occ := range occurrences {
+		if !occ.StartTime.After(now) {
+			continue
+		}
+		if err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+			return s.materializeOccurrence(ctx, tx, occ.ID)
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	rows, err := s.db.Query(ctx, `
+		UPDATE meetings
+		   SET reminder_sent_at = NOW()
+		 WHERE status = 'scheduled'
+		   AND reminder_sent_at IS NULL
+		   AND start_time > NOW()
+		   AND start_time <= NOW() + make_interval(mins => $1)
+		RETURNING id::text, COALESCE(external_id, id::text), title, start_time`, int(lead.Minutes()))
+	if err != nil {
+		return 0, err
+	}
+	type reminder struct {
+		meetingID string
+		slug      string
+		title     string
+		startTime time.Time
+	}
+	var reminders []reminder
+	for rows.Next() {
+		var r reminder
+		if err := rows.Scan(&r.meetingID, &r.slug, &r.title, &r.startTime); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		reminders = append(reminders, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, r := range reminders {
+		userIDs, emails, err := meetingRecipients(ctx, s.db, r.meetingID)
+		if err != nil {
+			return 0, err
+		}
+		minutes := int(time.Until(r.startTime).Round(time.Minute).Minutes())
+		msg := NotificationMessage{
+			Type:      NotificationMeetingStarting,
+			Title:     fmt.Sprintf("Reminder: %s starts in %d minutes", r.title, minutes),
+			Body:      fmt.Sprintf("%s starts at %s.", r.title, r.startTime.UTC().Format("Mon, 02 Jan 2006 15:04 MST")),
+			Link:      s.meetingJoinURL(r.slug),
+			MeetingID: r.meetingID,
+		}
+		s.notifyUsers(ctx, userIDs, msg)
+		if !s.mailConfigured {
+			continue
+		}
+		for _, email := range emails {
+			// One failed address should not hold back the others
+			_ = s.mailer.Send(ctx, EmailMessage{
+				To:      email,
+				Subject: msg.Title,
+				Body:    msg.Body + "\n\nJoin here:\n" + msg.Link,
+			})
+		}
+	}
+	return len(reminders), nil
+}
+
+// meetingRecipients returns the users among a meeting's host, participants
+// and open invites, and the addresses of invitees without an account.
+// Participants the host turned away are left out.
+func meetingRecipients(ctx context.Context, q querier, meetingID string) ([]string, []string, error) {
+	rows, err := q.Query(ctx, `
+		WITH recipients AS (
+			SELECT host_user_id AS user_id, NULL::text AS email
+			  FROM meetings
+			 WHERE id = $1::uuid
+			   AND host_user_id IS NOT NULL
+			UNION
+			SELECT user_id, NULL
+			  FROM meeting_participants
+			 WHERE meeting_id = $1::uuid
+			   AND user_id IS NOT NULL
+			   AND admission_status IS DISTINCT FROM 'denied'
+			UNION
+			SELECT invitee_user_id, NULLIF(LOWER(email), '')
+			  FROM meeting_invites
+			 WHERE status IN ('pending', 'accepted')
+			   AND (meeting_id = $1::uuid
+			        OR meeting_id = (SELECT series_id FROM meetings WHERE id = $1::uuid))
+		)
+		SELECT DISTINCT COALESCE(u.id::text, ''),
+		       CASE WHEN u.id IS NULL THEN r.email ELSE '' END
+		  FROM recipients r
+		  LEFT JOIN app_users u
+		    ON u.id = r.user_id
+		    OR (r.user_id IS NULL AND LOWER(u.email) = r.email)
+		 WHERE u.id IS NOT NULL OR r.email IS NOT NULL`, meetingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var userIDs, emails []string
+	for rows.Next() {
+		var userID, email string
+		if err := rows.Scan(&userID, &email); err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case userID != "":
+			userIDs = append(userIDs, userID)
+		case strings.TrimSpace(email) != "":
+			emails = append(emails, email)
+		}
+	}
+	return userIDs, emails, rows.Err()
+}
+
+// announceTranscripts notifies the attendees of meetings whose transcripts
+// were stored since the last pass. Transcripts are claimed first, so each is
+// announced once.
+func (s *AppService) announceTranscripts(ctx context.Context) (int, error) {
+	rows, err := s.db.Query(ctx, `
+		UPDATE transcripts t
+		   SET notified_at = NOW()
+		 WHERE t.notified_at IS NULL
+		RETURNING t.id::text,
+		          COALESCE((SELECT se.meeting_id::text FROM sessions se WHERE se.id = t.session_id), '')`)
+	if err != nil {
+		return 0, err
+	}
+	type transcript struct {
+		id        string
+		meetingID string
+	}
+	var transcripts []transcript
+	for rows.Next() {
+		var t transcript
+		if err := rows.Scan(&t.id, &t.meetingID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		transcripts = append(transcripts, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	announced := 0
+	for _, t := range transcripts {
+		if t.meetingID == "" {
+			continue
+		}
+		var title string
+		if err := s.db.QueryRow(ctx, `SELECT title FROM meetings WHERE id = $1::uuid`, t.meetingID).Scan(&title); err != nil {
+			return announced, err
+		}
+		attendees, err := meetingAttendees(ctx, s.db, t.meetingID)
+		if err != nil {
+			return announced, err
+		}
+		s.notifyUsers(ctx, attendees, NotificationMessage{
+			Type:      NotificationTranscriptReady,
+			Title:     fmt.Sprintf("Transcript of %s is ready", title),
+			Body:      fmt.Sprintf("The transcript of %s is available in your history.", title),
+			Link:      strings.TrimRight(s.appURL, "/") + "/history",
+			MeetingID: t.meetingID,
+		})
+		announced++
+	}
+	return announced, nil
+}
+
+// closedMeetingError describes why a meeting in a final status can no longer
+// be started or joined, or returns nil
+func closedMeetingError(status string) error {
+	switch status {
+	case "ended":
+		return conflict("meeting_ended", "meeting has ended")
+	case MeetingStatusMissed:
+		return conflict("meeting_missed", "meeting was never started and its time has passed")
+	case MeetingStatusExpired:
+		return conflict("meeting_expired", "meeting has expired")
+	}
+	return nil
+}
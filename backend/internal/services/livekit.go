@@ -1,7 +1,6 @@
 package services
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/livekit/protocol/auth"
@@ -10,7 +9,7 @@ import (
 // GenerateLiveKitToken generates a LiveKit access token for joining a room
 func GenerateLiveKitToken(apiKey, apiSecret, userID, userName, roomName string, canPublish, canSubscribe bool) (string, error) {
 	if apiKey == "" || apiSecret == "" {
-		return "", fmt.Errorf("LiveKit API key and secret must be configured")
+		return "", unavailable("livekit_unconfigured", "LiveKit API key and secret must be configured")
 	}
 
 	at := auth.NewAccessToken(apiKey, apiSecret)
@@ -29,3 +28,31 @@ func GenerateLiveKitToken(apiKey, apiSecret, userID, userName, roomName string,
 
 	return at.ToJWT()
 }
+
+// GenerateGuestLiveKitToken generates a LiveKit access token for a guest. Guests
+// can talk and see others but cannot send data messages or change their own
+// metadata, and the token lapses with the guest identity.
+func GenerateGuestLiveKitToken(apiKey, apiSecret, identity, name, roomName string, validFor time.Duration) (string, error) {
+	if apiKey == "" || apiSecret == "" {
+		return "", unavailable("livekit_unconfigured", "LiveKit API key and secret must be configured")
+	}
+
+	at := auth.NewAccessToken(apiKey, apiSecret)
+
+	canPublish, canSubscribe, canPublishData, canUpdateMetadata := true, true, false, false
+	grant := &auth.VideoGrant{
+		RoomJoin:             true,
+		Room:                 roomName,
+		CanPublish:           &canPublish,
+		CanSubscribe:         &canSubscribe,
+		CanPublishData:       &canPublishData,
+		CanUpdateOwnMetadata: &canUpdateMetadata,
+	}
+
+	at.AddGrant(grant).
+		SetIdentity(identity).
+		SetName(name).
+		SetValidFor(validFor)
+
+	return at.ToJWT()
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	AdmissionWaiting  = "waiting"
+	AdmissionAdmitted = "admitted"
+	AdmissionDenied   = "denied"
+	// AdmissionNone is reported to users who have not asked to join
+	AdmissionNone = "none"
+
+	JoinStatusJoined  = "joined"
+	JoinStatusWaiting = "waiting"
+
+	// defaultParticipantRole is given to participants recorded on join
+	defaultParticipantRole = RoleViewer
+)
+
+// GetMeetingLobby lists the admission requests of a meeting, waiting ones
+// first and then in the order they came in
+func (s *AppService) GetMeetingLobby(ctx context.Context, identifier string) (*core.MeetingLobbyResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &core.MeetingLobbyResponse{Entries: make([]core.LobbyEntry, 0)}
+	if err := s.db.QueryRow(ctx, `SELECT lobby_enabled FROM meetings WHERE id = $1::uuid`, meetingID).Scan(&resp.Enabled); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, lobbySelect+`
+		 WHERE meeting_id = $1::uuid
+		   AND admission_status IS NOT NULL
+		 ORDER BY admission_status <> 'waiting', requested_at NULLS LAST, display_name`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		entry, err := scanLobbyEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		resp.Entries = append(resp.Entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DecideLobbyEntry admits or denies a participant. Admitted participants get
+// their join tokens the next time they join; denied ones are turned away even
+// when the lobby is off.
+func (s *AppService) DecideLobbyEntry(ctx context.Context, identifier, userID, deciderID string, req core.UpdateLobbyEntryRequest) (*core.LobbyEntry, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	if status != AdmissionAdmitted && status != AdmissionDenied {
+		return nil, InvalidField("status", "status must be admitted or denied")
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := scanLobbyEntry(s.db.QueryRow(ctx, `
+		UPDATE meeting_participants
+		   SET admission_status = $3,
+		       decided_at = NOW(),
+		       decided_by = NULLIF($4, '')::uuid
+		 WHERE meeting_id = $1::uuid
+		   AND user_id::text = $2
+		RETURNING `+lobbyColumns,
+		meetingID, strings.TrimSpace(userID), status, strings.TrimSpace(deciderID)))
+	if err == pgx.ErrNoRows {
+		return nil, notFound("lobby_entry_not_found", "participant not found in this meeting")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publishMeetingEvent(ctx, meetingID, MeetingEventLobbyDecided, core.LobbyEvent{
+		ParticipantID: entry.UserID,
+		Name:          entry.Name,
+		Status:        entry.Status,
+	}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// LobbyStatus reports the caller's admission to a meeting, which a
+// participant polls while waiting in the lobby
+func (s *AppService) LobbyStatus(ctx context.Context, identifier, userID string) (*core.LobbyStatusResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := scanLobbyEntry(s.db.QueryRow(ctx, lobbySelect+`
+		 WHERE meeting_id = $1::uuid
+		   AND user_id::text = $2`, meetingID, userID))
+	if err == pgx.ErrNoRows {
+		return &core.LobbyStatusResponse{Status: AdmissionNone}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status == "" {
+		entry.Status = AdmissionNone
+	}
+	return &core.LobbyStatusResponse{
+		Status:      entry.Status,
+		RequestedAt: entry.RequestedAt,
+		DecidedAt:   entry.DecidedAt,
+	}, nil
+}
+
+// participantAdmission returns the recorded admission of a user, empty when
+// there is none
+func participantAdmission(ctx context.Context, q querier, meetingID, userID string) (string, error) {
+	var status string
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(admission_status, '')
+		  FROM meeting_participants
+		 WHERE meeting_id = $1::uuid
+		   AND user_id = $2::uuid`, meetingID, userID).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return status, err
+}
+
+// requestAdmission puts a user in the lobby, keeping their place when they
+// are already waiting. New requests are logged for the meeting's hosts.
+func requestAdmission(ctx context.Context, q querier, meetingID, userID string) error {
+	if err := ensureParticipant(ctx, q, meetingID, userID); err != nil {
+		return err
+	}
+	var name string
+	err := q.QueryRow(ctx, `
+		UPDATE meeting_participants
+		   SET requested_at = NOW(),
+		       admission_status = $3,
+		       decided_at = NULL,
+		       decided_by = NULL
+		 WHERE meeting_id = $1::uuid
+		   AND user_id = $2::uuid
+		   AND admission_status IS DISTINCT FROM $3
+		RETURNING display_name`, meetingID, userID, AdmissionWaiting).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return recordMeetingEvent(ctx, q, meetingID, MeetingEventLobbyRequested, core.LobbyEvent{
+		ParticipantID: userID,
+		Name:          name,
+		Status:        AdmissionWaiting,
+	})
+}
+
+// recordParticipantJoin marks a user as present in a meeting
+func recordParticipantJoin(ctx context.Context, q querier, meetingID, userID string) error {
+	if err := ensureParticipant(ctx, q, meetingID, userID); err != nil {
+		return err
+	}
+	var name string
+	if err := q.QueryRow(ctx, `
+		UPDATE meeting_participants
+		   SET joined_at = NOW(),
+		       left_at = NULL
+		 WHERE meeting_id = $1::uuid
+		   AND user_id = $2::uuid
+		RETURNING display_name`, meetingID, userID).Scan(&name); err != nil {
+		return err
+	}
+	return recordMeetingEvent(ctx, q, meetingID, MeetingEventParticipantJoined, core.ParticipantEvent{
+		ParticipantID: userID,
+		Name:          name,
+	})
+}
+
+// LeaveMeeting records that a participant left a meeting. Leaving twice is
+// not an error.
+func (s *AppService) LeaveMeeting(ctx context.Context, identifier, userID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return unauthorized("session_invalid", "authentication required")
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return err
+	}
+
+	var name string
+	err = s.db.QueryRow(ctx, `
+		UPDATE meeting_participants
+		   SET left_at = NOW()
+		 WHERE meeting_id = $1::uuid
+		   AND user_id = $2::uuid
+		   AND joined_at IS NOT NULL
+		   AND left_at IS NULL
+		RETURNING display_name`, meetingID, userID).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.publishMeetingEvent(ctx, meetingID, MeetingEventParticipantLeft, core.ParticipantEvent{
+		ParticipantID: userID,
+		Name:          name,
+	})
+}
+
+// ensureParticipant adds a participant row for a user who has none yet.
+// Display names are unique per meeting, so a taken name is qualified with
+// the start of the user id.
+func ensureParticipant(ctx context.Context, q querier, meetingID, userID string) error {
+	exists, err := hasParticipantRow(ctx, q, meetingID, userID)
+	if err != nil || exists {
+		return err
+	}
+
+	var name, avatarURL string
+	if err := q.QueryRow(ctx, `
+		SELECT name, COALESCE(avatar_url, '')
+		  FROM app_users
+		 WHERE id = $1::uuid`, userID).Scan(&name, &avatarURL); err != nil {
+		if err == pgx.ErrNoRows {
+			return unauthorized("session_invalid", "authentication required")
+		}
+		return err
+	}
+
+	shortID := userID
+	if len(shortID) > 8 {
+		shortID = shortID[:8]
+	}
+	for _, displayName := range []string{name, fmt.Sprintf("%s (%s)", name, shortID)} {
+		tag, err := q.Exec(ctx, `
+			INSERT INTO meeting_participants (meeting_id, user_id, display_name, role, avatar_url)
+			VALUES ($1::uuid, $2::uuid, $3, $4, $5)
+			ON CONFLICT DO NOTHING`, meetingID, userID, displayName, defaultParticipantRole, avatarURL)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() > 0 {
+			return nil
+		}
+	}
+	// Added concurrently for this user, or the name is taken twice over
+	if exists, err := hasParticipantRow(ctx, q, meetingID, userID); err != nil || exists {
+		return err
+	}
+	return conflict("participant_name_taken", "another participant already uses this name")
+}
+
+func hasParticipantRow(ctx context.Context, q querier, meetingID, userID string) (bool, error) {
+	var exists bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1
+			  FROM meeting_participants
+			 WHERE meeting_id = $1::uuid
+			   AND user_id = $2::uuid
+		)`, meetingID, userID).Scan(&exists)
+	return exists, err
+}
+
+const lobbyColumns = `COALESCE(user_id::text, ''), display_name, COALESCE(avatar_url, ''), COALESCE(admission_status, ''), requested_at, decided_at`
+
+const lobbySelect = `
+		SELECT ` + lobbyColumns + `
+		  FROM meeting_participants`
+
+func scanLobbyEntry(row pgx.Row) (*core.LobbyEntry, error) {
+	var entry core.LobbyEntry
+	if err := row.Scan(
+		&entry.UserID,
+		&entry.Name,
+		&entry.AvatarURL,
+		&entry.Status,
+		&entry.RequestedAt,
+		&entry.DecidedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
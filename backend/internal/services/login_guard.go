@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/metrics"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// loginBackoffThreshold is the number of failures allowed before delays kick in
+	loginBackoffThreshold = 3
+	// loginLockoutThreshold is the number of failures that triggers a temporary lockout
+	loginLockoutThreshold = 10
+	loginMaxBackoff       = 5 * time.Minute
+	loginLockoutDuration  = 30 * time.Minute
+	// loginFailureWindow resets the failure counter after a quiet period
+	loginFailureWindow = 24 * time.Hour
+	unlockTokenTTL     = 1 * time.Hour
+)
+
+// LoginLockedError is returned by AuthenticateUser while an account is throttled.
+// It is returned for unknown emails too, so it never reveals whether an account exists.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts"
+}
+
+// loginAttemptState is the failure bookkeeping for one login identifier
+type loginAttemptState struct {
+	Failures    int
+	LockedUntil time.Time
+}
+
+// loginAttemptStore persists failed login attempts
+type loginAttemptStore interface {
+	Get(ctx context.Context, identifier string) (loginAttemptState, error)
+	RecordFailure(ctx context.Context, identifier string, now time.Time) (loginAttemptState, error)
+	Reset(ctx context.Context, identifier string) error
+}
+
+// loginDelay returns how long the identifier must wait after its latest failure.
+// Delays double from one second once the backoff threshold is reached and turn
+// into a fixed lockout at the lockout threshold.
+func loginDelay(failures int) time.Duration {
+	switch {
+	case failures >= loginLockoutThreshold:
+		return loginLockoutDuration
+	case failures < loginBackoffThreshold:
+		return 0
+	}
+	delay := time.Second << (failures - loginBackoffThreshold)
+	if delay > loginMaxBackoff {
+		delay = loginMaxBackoff
+	}
+	return delay
+}
+
+// redisLoginAttemptStore keeps attempt counters in Redis so all replicas share them
+type redisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+func (s *redisLoginAttemptStore) key(identifier string) string {
+	return "login_attempts:" + identifier
+}
+
+func (s *redisLoginAttemptStore) Get(ctx context.Context, identifier string) (loginAttemptState, error) {
+	values, err := s.client.HGetAll(ctx, s.key(identifier)).Result()
+	if err != nil {
+		return loginAttemptState{}, err
+	}
+	var state loginAttemptState
+	state.Failures, _ = strconv.Atoi(values["failures"])
+	if lockedUntil, err := strconv.ParseInt(values["locked_until"], 10, 64); err == nil && lockedUntil > 0 {
+		state.LockedUntil = time.Unix(lockedUntil, 0)
+	}
+	return state, nil
+}
+
+// recordLoginFailureScript counts a failure and writes the resulting lock in
+// one step, so concurrent failures cannot skip or shorten a lock. ARGV holds
+// the window and the current time in seconds, followed by the delay for each
+// failure count from one up; higher counts use the last delay. It returns the
+// failure count and the lock's Unix time, or 0 when there is none.
+var recordLoginFailureScript = redis.NewScript(`
+local failures = redis.call('HINCRBY', KEYS[1], 'failures', 1)
+redis.call('EXPIRE', KEYS[1], ARGV[1])
+local delay = tonumber(ARGV[2 + math.min(failures, #ARGV - 2)])
+if delay <= 0 then
+	return {failures, 0}
+end
+local locked_until = tonumber(ARGV[2]) + delay
+local current = tonumber(redis.call('HGET', KEYS[1], 'locked_until') or '0')
+if current > locked_until then
+	locked_until = current
+end
+redis.call('HSET', KEYS[1], 'locked_until', locked_until)
+return {failures, locked_until}
+`)
+
+// loginDelaySeconds lists loginDelay in seconds for one failure up to the
+// lockout threshold
+func loginDelaySeconds() []any {
+	delays := make([]any, loginLockoutThreshold)
+	for i := range delays {
+		delays[i] = int64(loginDelay(i+1) / time.Second)
+	}
+	return delays
+}
+
+func (s *redisLoginAttemptStore) RecordFailure(ctx context.Context, identifier string, now time.Time) (loginAttemptState, error) {
+	args := append([]any{int64(loginFailureWindow / time.Second), now.Unix()}, loginDelaySeconds()...)
+	result, err := recordLoginFailureScript.Run(ctx, s.client, []string{s.key(identifier)}, args...).Int64Slice()
+	if err != nil {
+		return loginAttemptState{}, err
+	}
+
+	state := loginAttemptState{Failures: int(result[0])}
+	if result[1] > 0 {
+		state.LockedUntil = time.Unix(result[1], 0)
+	}
+	return state, nil
+}
+
+func (s *redisLoginAttemptStore) Reset(ctx context.Context, identifier string) error {
+	return s.client.Del(ctx, s.key(identifier)).Err()
+}
+
+// postgresLoginAttemptStore is the fallback when Redis is not configured
+type postgresLoginAttemptStore struct {
+	db *pgxpool.Pool
+}
+
+func (s *postgresLoginAttemptStore) Get(ctx context.Context, identifier string) (loginAttemptState, error) {
+	var (
+		state       loginAttemptState
+		lastFailed  time.Time
+		lockedUntil *time.Time
+	)
+	err := s.db.QueryRow(ctx, `
+		SELECT failed_count, last_failed_at, locked_until
+		  FROM login_attempts
+		 WHERE identifier = $1`, identifier).Scan(&state.Failures, &lastFailed, &lockedUntil)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return loginAttemptState{}, nil
+		}
+		return loginAttemptState{}, err
+	}
+	if time.Since(lastFailed) > loginFailureWindow {
+		return loginAttemptState{}, nil
+	}
+	if lockedUntil != nil {
+		state.LockedUntil = *lockedUntil
+	}
+	return state, nil
+}
+
+// RecordFailure runs in a transaction so the upserted row stays locked until
+// its lock is written, which keeps concurrent failures in order
+func (s *postgresLoginAttemptStore) RecordFailure(ctx context.Context, identifier string, now time.Time) (loginAttemptState, error) {
+	var state loginAttemptState
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO login_attempts (identifier, failed_count, last_failed_at)
+			VALUES ($1, 1, $2)
+			ON CONFLICT (identifier) DO UPDATE
+			   SET failed_count = CASE
+			                          WHEN login_attempts.last_failed_at < $3 THEN 1
+			                          ELSE login_attempts.failed_count + 1
+			                      END,
+			       last_failed_at = EXCLUDED.last_failed_at
+			RETURNING failed_count`,
+			identifier,
+			now,
+			now.Add(-loginFailureWindow),
+		).Scan(&state.Failures); err != nil {
+			return err
+		}
+
+		var lockedUntil *time.Time
+		if delay := loginDelay(state.Failures); delay > 0 {
+			state.LockedUntil = now.Add(delay)
+			lockedUntil = &state.LockedUntil
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE login_attempts
+			   SET locked_until = $1
+			 WHERE identifier = $2`, lockedUntil, identifier)
+		return err
+	})
+	if err != nil {
+		return loginAttemptState{}, err
+	}
+	return state, nil
+}
+
+func (s *postgresLoginAttemptStore) Reset(ctx context.Context, identifier string) error {
+	_, err := s.db.Exec(ctx, `DELETE FROM login_attempts WHERE identifier = $1`, identifier)
+	return err
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// compareDummyPassword burns the same bcrypt cost as a real comparison so that
+// unknown accounts cannot be distinguished by response time.
+func compareDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	})
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+// checkLoginAllowed returns a LoginLockedError while the identifier is throttled
+func (s *AppService) checkLoginAllowed(ctx context.Context, identifier string) error {
+	state, err := s.loginAttempts.Get(ctx, identifier)
+	if err != nil {
+		return err
+	}
+	if wait := time.Until(state.LockedUntil); wait > 0 {
+		metrics.LoginFailures.WithLabelValues("locked").Inc()
+		return &LoginLockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt. Store errors are deliberately
+// swallowed: the caller already has an authentication failure to report.
+func (s *AppService) recordLoginFailure(ctx context.Context, identifier string) {
+	metrics.LoginFailures.WithLabelValues("invalid_credentials").Inc()
+	_, _ = s.loginAttempts.RecordFailure(ctx, identifier, time.Now())
+}
+
+// UnlockAccount clears failed login state for an email (admin flow)
+func (s *AppService) UnlockAccount(ctx context.Context, email string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	email = strings.TrimSpace(strings.ToLower(email))
+	if email == "" {
+		return InvalidField("email", "email is required")
+	}
+	return s.loginAttempts.Reset(ctx, email)
+}
+
+// RequestAccountUnlock emails a single-use unlock link when the account exists
+// and is currently throttled. It returns nil in every other case so callers
+// cannot use it to probe for accounts.
+func (s *AppService) RequestAccountUnlock(ctx context.Context, email string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	email = strings.TrimSpace(strings.ToLower(email))
+	if email == "" {
+		return InvalidField("email", "email is required")
+	}
+
+	state, err := s.loginAttempts.Get(ctx, email)
+	if err != nil {
+		return err
+	}
+	if state.Failures == 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := s.db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM app_users WHERE LOWER(email) = $1)`, email).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	token, err := generateRandomHex(32)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO account_unlock_tokens (email, token_hash, expires_at)
+		VALUES ($1, $2, $3)`,
+		email,
+		hashRefreshToken(token),
+		time.Now().Add(unlockTokenTTL),
+	); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/unlock?token=%s", strings.TrimRight(s.appURL, "/"), url.QueryEscape(token))
+	return s.mailer.Send(ctx, EmailMessage{
+		To:      email,
+		Subject: "Unlock your account",
+		Body: fmt.Sprintf("We noticed several failed sign-in attempts on your account and paused logins for a while.\n\n"+
+			"If this was you, use the link below within the next hour to sign in again:\n%s\n\n"+
+			"If it wasn't you, consider changing your password after unlocking.", link),
+	})
+}
+
+// ConfirmAccountUnlock consumes an unlock token and clears the lockout
+func (s *AppService) ConfirmAccountUnlock(ctx context.Context, token string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return InvalidField("token", "unlock token is required")
+	}
+
+	var email string
+	if err := s.db.QueryRow(ctx, `
+		UPDATE account_unlock_tokens
+		   SET used_at = NOW()
+		 WHERE token_hash = $1
+		   AND used_at IS NULL
+		   AND expires_at > NOW()
+		RETURNING email`, hashRefreshToken(token)).Scan(&email); err != nil {
+		if err == pgx.ErrNoRows {
+			return InvalidField("token", "invalid or expired unlock token")
+		}
+		return err
+	}
+
+	return s.loginAttempts.Reset(ctx, email)
+}
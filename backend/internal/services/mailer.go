@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+)
+
+// smtpTimeout bounds one delivery when the caller's context has no deadline
+const smtpTimeout = 30 * time.Second
+
+// EmailMessage is a plain-text outbound email
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outbound email. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// LogMailer records emails in the logger instead of delivering them. It is
+// the default when no mail transport is configured, which configuration only
+// allows outside production. Bodies are never logged, since they carry links
+// with secret tokens.
+type LogMailer struct {
+	Logger logger.Logger
+}
+
+// Send logs the recipient and subject of the message
+func (m LogMailer) Send(ctx context.Context, msg EmailMessage) error {
+	if m.Logger == nil {
+		return fmt.Errorf("mailer logger not configured")
+	}
+	m.Logger.InfoContext(ctx, "email not delivered, no mail transport configured", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// SMTPMailer delivers email through an SMTP server. Each message uses its
+// own connection.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From is the sender, optionally with a display name
+	From string
+	// TLS is "starttls", "tls" for implicit TLS or "none"
+	TLS string
+}
+
+// Send delivers one message
+func (m *SMTPMailer) Send(ctx context.Context, msg EmailMessage) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	data, err := buildEmail(from, to, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	tlsConfig := &tls.Config{ServerName: m.Host, MinVersion: tls.VersionTLS12}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	if m.TLS == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp connect: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp greeting: %w", err)
+	}
+	defer client.Close()
+
+	if m.TLS == "starttls" {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if m.Username != "" {
+		// PlainAuth refuses to send credentials over an unencrypted
+		// connection to anything but localhost
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("smtp sender: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp recipient: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildEmail renders a plain-text UTF-8 message. Header values come from
+// parsed addresses and an encoded subject, so they cannot inject headers.
+func buildEmail(from, to *mail.Address, msg EmailMessage) ([]byte, error) {
+	subject := strings.Join(strings.Fields(msg.Subject), " ")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildEmailHeaders(t *testing.T) {
+	from := &mail.Address{Name: "Meetings", Address: "no-reply@example.com"}
+	to := &mail.Address{Address: "ada@example.com"}
+
+	data, err := buildEmail(from, to, EmailMessage{
+		Subject: "Unlock\r\nBcc: attacker@example.com",
+		Body:    "Hello Ada,\nfollow the link.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers, body, ok := strings.Cut(string(data), "\r\n\r\n")
+	if !ok {
+		t.Fatalf("message has no header/body separator: %q", data)
+	}
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Errorf("subject injected a header: %q", headers)
+	}
+	if !strings.Contains(headers, "Subject: Unlock Bcc: attacker@example.com\r\n") {
+		t.Errorf("subject not folded onto one line: %q", headers)
+	}
+	if !strings.Contains(headers, `From: "Meetings" <no-reply@example.com>`) {
+		t.Errorf("unexpected From header: %q", headers)
+	}
+	if body != "Hello Ada,\r\nfollow the link." {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestLogMailerOmitsBody(t *testing.T) {
+	var buf bytes.Buffer
+	m := LogMailer{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+	if err := m.Send(context.Background(), EmailMessage{
+		To:      "ada@example.com",
+		Subject: "Unlock your account",
+		Body:    "https://app.example.com/unlock?token=secret-token",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("log contains the message body: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Unlock your account") {
+		t.Errorf("log lacks the subject: %s", buf.String())
+	}
+}
@@ -0,0 +1,684 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Notification types. Users choose the channels of each type separately.
+const (
+	NotificationInviteReceived    = "invite_received"
+	NotificationMeetingStarting   = "meeting_starting"
+	NotificationMeetingStarted    = "meeting_started"
+	NotificationTranscriptReady   = "transcript_ready"
+	NotificationFeedbackRequested = "feedback_requested"
+)
+
+var notificationTypes = []string{
+	NotificationInviteReceived,
+	NotificationMeetingStarting,
+	NotificationMeetingStarted,
+	NotificationTranscriptReady,
+	NotificationFeedbackRequested,
+}
+
+// Built-in notification channels
+const (
+	NotificationChannelInApp   = "in_app"
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+const (
+	// notificationTimeout bounds a delivery started from a request
+	notificationTimeout = 30 * time.Second
+	webhookTimeout      = 10 * time.Second
+	// webhookSignatureHeader carries the HMAC-SHA256 of the request body,
+	// keyed with the webhook's secret
+	webhookSignatureHeader   = "X-Notification-Signature"
+	defaultNotificationLimit = 50
+	maxNotificationLimit     = 200
+)
+
+// NotificationMessage is one notification addressed to one user
+type NotificationMessage struct {
+	UserID string
+	Email  string
+	Name   string
+	Type   string
+	Title  string
+	Body   string
+	Link   string
+	// MeetingID is the row id of the meeting the notification is about
+	MeetingID string
+}
+
+// NotificationChannel delivers notifications over one medium. Channels are
+// told apart by name in user preferences.
+type NotificationChannel interface {
+	Name() string
+	Deliver(ctx context.Context, msg NotificationMessage) error
+}
+
+// inAppChannel stores notifications in the user's inbox
+type inAppChannel struct {
+	db *pgxpool.Pool
+}
+
+func (c *inAppChannel) Name() string { return NotificationChannelInApp }
+
+func (c *inAppChannel) Deliver(ctx context.Context, msg NotificationMessage) error {
+	_, err := c.db.Exec(ctx, `
+		INSERT INTO notifications (user_id, type, title, body, link, meeting_id)
+		VALUES ($1::uuid, $2, $3, $4, $5, NULLIF($6, '')::uuid)`,
+		msg.UserID, msg.Type, msg.Title, msg.Body, msg.Link, msg.MeetingID)
+	return err
+}
+
+// emailChannel sends notifications through the Mailer
+type emailChannel struct {
+	mailer Mailer
+}
+
+func (c *emailChannel) Name() string { return NotificationChannelEmail }
+
+func (c *emailChannel) Deliver(ctx context.Context, msg NotificationMessage) error {
+	if msg.Email == "" {
+		return nil
+	}
+	body := msg.Body
+	if msg.Link != "" {
+		body += "\n\n" + msg.Link
+	}
+	return c.mailer.Send(ctx, EmailMessage{To: msg.Email, Subject: msg.Title, Body: body})
+}
+
+// webhookChannel posts notifications as signed JSON to the URL a user
+// registered. Like calendar fetches, it only connects to public addresses.
+type webhookChannel struct {
+	db     *pgxpool.Pool
+	client *http.Client
+}
+
+func newWebhookChannel(db *pgxpool.Pool) *webhookChannel {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: publicAddrControl}
+	return &webhookChannel{
+		db: db,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+			Transport: &http.Transport{
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: webhookTimeout,
+			},
+			// A redirect could point anywhere; receivers must answer directly
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (c *webhookChannel) Name() string { return NotificationChannelWebhook }
+
+type webhookPayload struct {
+	Type   string    `json:"type"`
+	Title  string    `json:"title"`
+	Body   string    `json:"body"`
+	Link   string    `json:"link,omitempty"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+func (c *webhookChannel) Deliver(ctx context.Context, msg NotificationMessage) error {
+	var target, secret string
+	err := c.db.QueryRow(ctx, `
+		SELECT url, secret
+		  FROM notification_webhooks
+		 WHERE user_id = $1::uuid`, msg.UserID).Scan(&target, &secret)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Type:   msg.Type,
+		Title:  msg.Title,
+		Body:   msg.Body,
+		Link:   msg.Link,
+		SentAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notificationRecipient is a user who wants a notification type, with the
+// channels they configured for it
+type notificationRecipient struct {
+	userID   string
+	email    string
+	name     string
+	channels map[string]bool
+}
+
+// notifyUsers delivers a notification to users on every channel they have
+// not turned off for its type. Users who switched notifications off or were
+// disabled get nothing. Delivery problems are logged, not returned.
+func (s *AppService) notifyUsers(ctx context.Context, userIDs []string, msg NotificationMessage) {
+	if len(userIDs) == 0 {
+		return
+	}
+	recipients, err := s.notificationRecipients(ctx, userIDs, msg.Type)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "load notification recipients failed", "type", msg.Type, "error", err)
+		return
+	}
+	for _, r := range recipients {
+		m := msg
+		m.UserID, m.Email, m.Name = r.userID, r.email, r.name
+		for _, ch := range s.notificationChannels {
+			if enabled, ok := r.channels[ch.Name()]; ok && !enabled {
+				continue
+			}
+			if err := ch.Deliver(ctx, m); err != nil {
+				s.logger.WarnContext(ctx, "notification delivery failed",
+					"type", m.Type, "channel", ch.Name(), "user_id", m.UserID, "error", err)
+			}
+		}
+	}
+}
+
+// notifyAsync runs a notification step in the background, so that request
+// handlers do not wait for email and webhook delivery
+func (s *AppService) notifyAsync(ctx context.Context, notify func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+		defer cancel()
+		notify(ctx)
+	}()
+}
+
+func (s *AppService) notificationRecipients(ctx context.Context, userIDs []string, notificationType string) ([]notificationRecipient, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT u.id::text, u.email, u.name
+		  FROM app_users u
+		  LEFT JOIN user_preferences p ON p.user_id = u.id
+		 WHERE u.id = ANY($1::uuid[])
+		   AND u.disabled_at IS NULL
+		   AND COALESCE(p.notifications_enabled, TRUE)`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	var recipients []notificationRecipient
+	byID := make(map[string]int)
+	for rows.Next() {
+		r := notificationRecipient{channels: make(map[string]bool)}
+		if err := rows.Scan(&r.userID, &r.email, &r.name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		byID[r.userID] = len(recipients)
+		recipients = append(recipients, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	rows, err = s.db.Query(ctx, `
+		SELECT user_id::text, channel, enabled
+		  FROM notification_preferences
+		 WHERE user_id = ANY($1::uuid[])
+		   AND type = $2`, userIDs, notificationType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			userID, channel string
+			enabled         bool
+		)
+		if err := rows.Scan(&userID, &channel, &enabled); err != nil {
+			return nil, err
+		}
+		if i, ok := byID[userID]; ok {
+			recipients[i].channels[channel] = enabled
+		}
+	}
+	return recipients, rows.Err()
+}
+
+// meetingAttendees returns the host and the users who joined a meeting
+func meetingAttendees(ctx context.Context, q querier, meetingID string) ([]string, error) {
+	rows, err := q.Query(ctx, `
+		SELECT host_user_id::text
+		  FROM meetings
+		 WHERE id = $1::uuid
+		   AND host_user_id IS NOT NULL
+		UNION
+		SELECT user_id::text
+		  FROM meeting_participants
+		 WHERE meeting_id = $1::uuid
+		   AND user_id IS NOT NULL
+		   AND joined_at IS NOT NULL`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// notifyInvited tells users they were invited to a meeting
+func (s *AppService) notifyInvited(ctx context.Context, slug, inviterID string, inviteeIDs []string) {
+	var (
+		meetingID, title, inviter string
+		startTime                 time.Time
+	)
+	if err := s.db.QueryRow(ctx, `
+		SELECT m.id::text, m.title, m.start_time, COALESCE(u.name, '')
+		  FROM meetings m
+		  LEFT JOIN app_users u ON u.id = NULLIF($2, '')::uuid
+		 WHERE COALESCE(m.external_id, m.id::text) = $1`, slug, inviterID).Scan(&meetingID, &title, &startTime, &inviter); err != nil {
+		s.logger.ErrorContext(ctx, "load meeting for invite notification failed", "meeting_id", slug, "error", err)
+		return
+	}
+	if inviter == "" {
+		inviter = "Someone"
+	}
+	s.notifyUsers(ctx, inviteeIDs, NotificationMessage{
+		Type:      NotificationInviteReceived,
+		Title:     fmt.Sprintf("You're invited to %s", title),
+		Body:      fmt.Sprintf("%s invited you to %s on %s.", inviter, title, startTime.UTC().Format("Mon, 02 Jan 2006 15:04 MST")),
+		Link:      s.meetingJoinURL(slug),
+		MeetingID: meetingID,
+	})
+}
+
+// notifyMeetingStarted tells the participants and invitees of a meeting,
+// other than the user who started it, that it is running
+func (s *AppService) notifyMeetingStarted(ctx context.Context, meetingID, starterID string) {
+	var slug, title, starter string
+	if err := s.db.QueryRow(ctx, `
+		SELECT COALESCE(m.external_id, m.id::text), m.title, COALESCE(u.name, '')
+		  FROM meetings m
+		  LEFT JOIN app_users u ON u.id = NULLIF($2, '')::uuid
+		 WHERE m.id = $1::uuid`, meetingID, starterID).Scan(&slug, &title, &starter); err != nil {
+		s.logger.ErrorContext(ctx, "load meeting for start notification failed", "meeting_id", meetingID, "error", err)
+		return
+	}
+	userIDs, _, err := meetingRecipients(ctx, s.db, meetingID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "load meeting recipients failed", "meeting_id", meetingID, "error", err)
+		return
+	}
+	others := userIDs[:0]
+	for _, id := range userIDs {
+		if id != starterID {
+			others = append(others, id)
+		}
+	}
+	if starter == "" {
+		starter = "The host"
+	}
+	s.notifyUsers(ctx, others, NotificationMessage{
+		Type:      NotificationMeetingStarted,
+		Title:     fmt.Sprintf("%s has started", title),
+		Body:      fmt.Sprintf("%s started %s. Join now.", starter, title),
+		Link:      s.meetingJoinURL(slug),
+		MeetingID: meetingID,
+	})
+}
+
+// notifyFeedbackRequested asks the attendees of ended meetings to rate them
+func (s *AppService) notifyFeedbackRequested(ctx context.Context, meetingIDs []string) {
+	for _, meetingID := range meetingIDs {
+		var title string
+		if err := s.db.QueryRow(ctx, `SELECT title FROM meetings WHERE id = $1::uuid`, meetingID).Scan(&title); err != nil {
+			s.logger.ErrorContext(ctx, "load meeting for feedback request failed", "meeting_id", meetingID, "error", err)
+			continue
+		}
+		attendees, err := meetingAttendees(ctx, s.db, meetingID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "load meeting attendees failed", "meeting_id", meetingID, "error", err)
+			continue
+		}
+		s.notifyUsers(ctx, attendees, NotificationMessage{
+			Type:      NotificationFeedbackRequested,
+			Title:     fmt.Sprintf("How did %s go?", title),
+			Body:      fmt.Sprintf("%s has ended. Rate the session to help us improve it.", title),
+			Link:      strings.TrimRight(s.appURL, "/") + "/history",
+			MeetingID: meetingID,
+		})
+	}
+}
+
+// ListNotifications returns a user's inbox, newest first, with the number of
+// unread notifications
+func (s *AppService) ListNotifications(ctx context.Context, userID string, unreadOnly bool, limit int) (*core.NotificationsResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+
+	resp := &core.NotificationsResponse{Notifications: make([]core.Notification, 0)}
+	rows, err := s.db.Query(ctx, notificationSelect+`
+		 WHERE n.user_id = $1::uuid
+		   AND (NOT $2 OR n.read_at IS NULL)
+		 ORDER BY n.created_at DESC, n.id
+		 LIMIT $3`, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		resp.Notifications = append(resp.Notifications, *n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.QueryRow(ctx, `
+		SELECT COUNT(*)
+		  FROM notifications
+		 WHERE user_id = $1::uuid
+		   AND read_at IS NULL`, userID).Scan(&resp.UnreadCount); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MarkNotification marks one of a user's notifications as read or unread
+func (s *AppService) MarkNotification(ctx context.Context, userID, notificationID string, req core.UpdateNotificationRequest) (*core.Notification, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	if req.Read == nil {
+		return nil, InvalidField("read", "read is required")
+	}
+
+	userID = strings.TrimSpace(userID)
+	tag, err := s.db.Exec(ctx, `
+		UPDATE notifications
+		   SET read_at = CASE WHEN $3 THEN COALESCE(read_at, NOW()) END
+		 WHERE id = $1::uuid
+		   AND user_id = $2::uuid`, notificationID, userID, *req.Read)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, notFound("notification_not_found", "notification not found")
+	}
+	return scanNotification(s.db.QueryRow(ctx, notificationSelect+`
+		 WHERE n.id = $1::uuid`, notificationID))
+}
+
+// MarkAllNotificationsRead marks a user's whole inbox as read
+func (s *AppService) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(ctx, `
+		UPDATE notifications
+		   SET read_at = NOW()
+		 WHERE user_id = $1::uuid
+		   AND read_at IS NULL`, strings.TrimSpace(userID))
+	return err
+}
+
+// GetNotificationPreferences returns the channels a user enabled for each
+// notification type. Channels are on until turned off.
+func (s *AppService) GetNotificationPreferences(ctx context.Context, userID string) (*core.NotificationPreferencesResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+
+	resp := &core.NotificationPreferencesResponse{
+		Channels:    make([]string, 0, len(s.notificationChannels)),
+		Preferences: make([]core.NotificationPreference, 0, len(notificationTypes)),
+	}
+	if err := s.db.QueryRow(ctx, `
+		SELECT COALESCE((SELECT notifications_enabled FROM user_preferences WHERE user_id = $1::uuid), TRUE)`,
+		userID).Scan(&resp.Enabled); err != nil {
+		return nil, err
+	}
+
+	stored := make(map[string]map[string]bool)
+	rows, err := s.db.Query(ctx, `
+		SELECT type, channel, enabled
+		  FROM notification_preferences
+		 WHERE user_id = $1::uuid`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			notificationType, channel string
+			enabled                   bool
+		)
+		if err := rows.Scan(&notificationType, &channel, &enabled); err != nil {
+			return nil, err
+		}
+		if stored[notificationType] == nil {
+			stored[notificationType] = make(map[string]bool)
+		}
+		stored[notificationType][channel] = enabled
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, ch := range s.notificationChannels {
+		resp.Channels = append(resp.Channels, ch.Name())
+	}
+	for _, notificationType := range notificationTypes {
+		pref := core.NotificationPreference{Type: notificationType, Channels: make(map[string]bool)}
+		for _, channel := range resp.Channels {
+			enabled, ok := stored[notificationType][channel]
+			pref.Channels[channel] = !ok || enabled
+		}
+		resp.Preferences = append(resp.Preferences, pref)
+	}
+	return resp, nil
+}
+
+// UpdateNotificationPreferences turns channels on or off per notification
+// type. Types and channels left out keep their setting.
+func (s *AppService) UpdateNotificationPreferences(ctx context.Context, userID string, req core.UpdateNotificationPreferencesRequest) (*core.NotificationPreferencesResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+
+	channels := make(map[string]bool, len(s.notificationChannels))
+	for _, ch := range s.notificationChannels {
+		channels[ch.Name()] = true
+	}
+	for _, pref := range req.Preferences {
+		if !isNotificationType(pref.Type) {
+			return nil, InvalidField("preferences", fmt.Sprintf("unknown notification type %q", pref.Type))
+		}
+		for channel := range pref.Channels {
+			if !channels[channel] {
+				return nil, InvalidField("preferences", fmt.Sprintf("unknown notification channel %q", channel))
+			}
+		}
+	}
+
+	if err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		for _, pref := range req.Preferences {
+			for channel, enabled := range pref.Channels {
+				if _, err := tx.Exec(ctx, `
+					INSERT INTO notification_preferences (user_id, type, channel, enabled)
+					VALUES ($1::uuid, $2, $3, $4)
+					ON CONFLICT (user_id, type, channel) DO UPDATE
+					   SET enabled = EXCLUDED.enabled,
+					       updated_at = NOW()`, userID, pref.Type, channel, enabled); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return s.GetNotificationPreferences(ctx, userID)
+}
+
+func isNotificationType(notificationType string) bool {
+	for _, t := range notificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNotificationWebhook returns the webhook a user registered, without its
+// secret
+func (s *AppService) GetNotificationWebhook(ctx context.Context, userID string) (*core.NotificationWebhook, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	var hook core.NotificationWebhook
+	err := s.db.QueryRow(ctx, `
+		SELECT url, created_at, updated_at
+		  FROM notification_webhooks
+		 WHERE user_id = $1::uuid`, strings.TrimSpace(userID)).Scan(&hook.URL, &hook.CreatedAt, &hook.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, notFound("webhook_not_found", "no notification webhook configured")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// SetNotificationWebhook registers the URL receiving a user's webhook
+// notifications. Every call issues a new signing secret, which is only
+// returned here.
+func (s *AppService) SetNotificationWebhook(ctx context.Context, userID string, req core.UpdateNotificationWebhookRequest) (*core.NotificationWebhook, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+	target := strings.TrimSpace(req.URL)
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, InvalidField("url", "url must be an absolute http or https URL")
+	}
+	if u.User != nil {
+		return nil, InvalidField("url", "url must not contain credentials")
+	}
+
+	secret, err := generateRandomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	hook := core.NotificationWebhook{URL: u.String(), Secret: secret}
+	if err := s.db.QueryRow(ctx, `
+		INSERT INTO notification_webhooks (user_id, url, secret)
+		VALUES ($1::uuid, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		   SET url = EXCLUDED.url,
+		       secret = EXCLUDED.secret,
+		       updated_at = NOW()
+		RETURNING created_at, updated_at`, userID, hook.URL, secret).Scan(&hook.CreatedAt, &hook.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// DeleteNotificationWebhook removes a user's webhook. Removing a missing
+// webhook is not an error.
+func (s *AppService) DeleteNotificationWebhook(ctx context.Context, userID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(ctx, `DELETE FROM notification_webhooks WHERE user_id = $1::uuid`, strings.TrimSpace(userID))
+	return err
+}
+
+const notificationSelect = `
+		SELECT n.id::text,
+		       n.type,
+		       n.title,
+		       n.body,
+		       n.link,
+		       COALESCE((SELECT COALESCE(m.external_id, m.id::text) FROM meetings m WHERE m.id = n.meeting_id), ''),
+		       n.read_at,
+		       n.created_at
+		  FROM notifications n`
+
+func scanNotification(row pgx.Row) (*core.Notification, error) {
+	var n core.Notification
+	if err := row.Scan(&n.ID, &n.Type, &n.Title, &n.Body, &n.Link, &n.MeetingID, &n.ReadAt, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	n.Read = n.ReadAt != nil
+	return &n, nil
+}
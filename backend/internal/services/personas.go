@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// builtinPersonaID is used when neither the meeting nor the user picks one
+	builtinPersonaID = "aurora"
+
+	maxPersonaNameLength         = 80
+	maxPersonaDescriptionLength  = 500
+	maxPersonaInstructionsLength = 8000
+)
+
+// personaSelect reads personas visible to the user in $1: built-in ones and
+// the user's own
+const personaSelect = `
+	SELECT p.id,
+	       p.name,
+	       COALESCE(vp.voice_id, p.voice_preset),
+	       p.tone,
+	       p.energy,
+	       COALESCE(p.description, ''),
+	       COALESCE(p.voice_preset_id::text, ''),
+	       COALESCE(p.instructions, ''),
+	       p.owner_user_id IS NOT NULL,
+	       p.id = COALESCE(pref.default_persona_id, 'aurora')
+	  FROM ai_personas p
+	  LEFT JOIN voice_presets vp ON vp.id = p.voice_preset_id
+	  LEFT JOIN user_preferences pref ON pref.user_id::text = $1
+	 WHERE (p.owner_user_id IS NULL OR p.owner_user_id::text = $1)`
+
+func scanPersona(row pgx.Row) (*core.AiPersona, error) {
+	var p core.AiPersona
+	if err := row.Scan(
+		&p.ID,
+		&p.Name,
+		&p.VoicePreset,
+		&p.Tone,
+		&p.Energy,
+		&p.Description,
+		&p.VoicePresetID,
+		&p.Instructions,
+		&p.Custom,
+		&p.IsDefault,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListAiPersonas returns the built-in personas and the user's own
+func (s *AppService) ListAiPersonas(ctx context.Context, userID string) (*core.AiPersonasResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, personaSelect+`
+		 ORDER BY p.owner_user_id IS NOT NULL, p.created_at, p.name`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &core.AiPersonasResponse{Personas: []core.AiPersona{}}
+	for rows.Next() {
+		persona, err := scanPersona(rows)
+		if err != nil {
+			return nil, err
+		}
+		resp.Personas = append(resp.Personas, *persona)
+	}
+	return resp, rows.Err()
+}
+
+// GetAiPersona returns one persona visible to the user
+func (s *AppService) GetAiPersona(ctx context.Context, userID, personaID string) (*core.AiPersona, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	persona, err := scanPersona(s.db.QueryRow(ctx, personaSelect+`
+		   AND p.id = $2`, userID, strings.TrimSpace(personaID)))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("persona_not_found", "persona not found")
+		}
+		return nil, err
+	}
+	return persona, nil
+}
+
+func (s *AppService) CreateAiPersona(ctx context.Context, userID string, req core.CreateAiPersonaRequest) (*core.AiPersona, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+
+	persona := core.AiPersona{
+		Name:          strings.TrimSpace(req.Name),
+		Tone:          strings.TrimSpace(req.Tone),
+		Energy:        strings.TrimSpace(req.Energy),
+		VoicePresetID: strings.TrimSpace(req.VoicePresetID),
+		Description:   strings.TrimSpace(req.Description),
+		Instructions:  strings.TrimSpace(req.Instructions),
+	}
+	if persona.Tone == "" {
+		persona.Tone = "Warm"
+	}
+	if persona.Energy == "" {
+		persona.Energy = "Balanced"
+	}
+	if err := validatePersona(persona); err != nil {
+		return nil, err
+	}
+
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	voice, err := personaVoice(ctx, tx, userID, persona.VoicePresetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var personaID string
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO ai_personas (id, owner_user_id, name, voice_preset, voice_preset_id, tone, energy, description, instructions)
+		VALUES (gen_random_uuid()::text, $1::uuid, $2, $3, NULLIF($4, '')::uuid, $5, $6, $7, $8)
+		RETURNING id`,
+		userID,
+		persona.Name,
+		voice,
+		persona.VoicePresetID,
+		persona.Tone,
+		persona.Energy,
+		persona.Description,
+		persona.Instructions,
+	).Scan(&personaID); err != nil {
+		return nil, err
+	}
+
+	if req.IsDefault {
+		if err := setDefaultPersona(ctx, tx, userID, personaID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetAiPersona(ctx, userID, personaID)
+}
+
+// UpdateAiPersona edits one of the user's own personas. Built-in personas
+// can be made the default but not changed.
+func (s *AppService) UpdateAiPersona(ctx context.Context, userID, personaID string, req core.UpdateAiPersonaRequest) (*core.AiPersona, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	personaID = strings.TrimSpace(personaID)
+	if personaID == "" {
+		return nil, InvalidField("personaId", "persona identifier is required")
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	existing, err := scanPersona(tx.QueryRow(ctx, personaSelect+`
+		   AND p.id = $2
+		   FOR UPDATE OF p`, userID, personaID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("persona_not_found", "persona not found")
+		}
+		return nil, err
+	}
+
+	edited := req.Name != nil || req.Tone != nil || req.Energy != nil || req.VoicePresetID != nil || req.Description != nil || req.Instructions != nil
+	if edited && !existing.Custom {
+		return nil, forbidden("persona_builtin", "built-in personas cannot be changed")
+	}
+
+	if edited {
+		updated := *existing
+		if req.Name != nil {
+			updated.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Tone != nil {
+			if updated.Tone = strings.TrimSpace(*req.Tone); updated.Tone == "" {
+				return nil, InvalidField("tone", "tone cannot be empty")
+			}
+		}
+		if req.Energy != nil {
+			if updated.Energy = strings.TrimSpace(*req.Energy); updated.Energy == "" {
+				return nil, InvalidField("energy", "energy cannot be empty")
+			}
+		}
+		if req.VoicePresetID != nil {
+			updated.VoicePresetID = strings.TrimSpace(*req.VoicePresetID)
+		}
+		if req.Description != nil {
+			updated.Description = strings.TrimSpace(*req.Description)
+		}
+		if req.Instructions != nil {
+			updated.Instructions = strings.TrimSpace(*req.Instructions)
+		}
+		if err := validatePersona(updated); err != nil {
+			return nil, err
+		}
+
+		voice, err := personaVoice(ctx, tx, userID, updated.VoicePresetID)
+		if err != nil {
+			return nil, err
+		}
+		if updated.VoicePresetID == "" {
+			// Without a linked preset the persona keeps its last voice
+			voice = existing.VoicePreset
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE ai_personas
+			   SET name = $1,
+			       voice_preset = $2,
+			       voice_preset_id = NULLIF($3, '')::uuid,
+			       tone = $4,
+			       energy = $5,
+			       description = $6,
+			       instructions = $7,
+			       updated_at = NOW()
+			 WHERE id = $8
+			   AND owner_user_id::text = $9`,
+			updated.Name,
+			voice,
+			updated.VoicePresetID,
+			updated.Tone,
+			updated.Energy,
+			updated.Description,
+			updated.Instructions,
+			personaID,
+			userID,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.IsDefault != nil {
+		switch {
+		case *req.IsDefault:
+			err = setDefaultPersona(ctx, tx, userID, personaID)
+		case existing.IsDefault:
+			// Unsetting the default falls back to the built-in persona
+			err = setDefaultPersona(ctx, tx, userID, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetAiPersona(ctx, userID, personaID)
+}
+
+// DeleteAiPersona removes one of the user's own personas. Personas still
+// attached to upcoming meetings cannot be deleted.
+func (s *AppService) DeleteAiPersona(ctx context.Context, userID, personaID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	personaID = strings.TrimSpace(personaID)
+	if personaID == "" {
+		return InvalidField("personaId", "persona identifier is required")
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var custom bool
+		if err := tx.QueryRow(ctx, `
+			SELECT owner_user_id IS NOT NULL
+			  FROM ai_personas
+			 WHERE id = $1
+			   AND (owner_user_id IS NULL OR owner_user_id::text = $2)
+			 FOR UPDATE`, personaID, userID).Scan(&custom); err != nil {
+			if err == pgx.ErrNoRows {
+				return notFound("persona_not_found", "persona not found")
+			}
+			return err
+		}
+		if !custom {
+			return forbidden("persona_builtin", "built-in personas cannot be deleted")
+		}
+
+		var upcoming int
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*)
+			  FROM meetings
+			 WHERE ai_persona_id = $1
+			   AND status IN ('scheduled', 'instant', 'active', $2)`, personaID, meetingStatusSeries).Scan(&upcoming); err != nil {
+			return err
+		}
+		if upcoming > 0 {
+			return conflict("persona_in_use", fmt.Sprintf("persona is used by %d upcoming meeting(s)", upcoming))
+		}
+
+		_, err := tx.Exec(ctx, `DELETE FROM ai_personas WHERE id = $1 AND owner_user_id::text = $2`, personaID, userID)
+		return err
+	})
+}
+
+// resolvePersonaID checks that a meeting's persona is available to its host.
+// An empty ID resolves to the host's default persona.
+func resolvePersonaID(ctx context.Context, q querier, userID, personaID string) (string, error) {
+	personaID = strings.TrimSpace(personaID)
+	if personaID == "" {
+		if err := q.QueryRow(ctx, `
+			SELECT p.id
+			  FROM user_preferences pref
+			  JOIN ai_personas p ON p.id = pref.default_persona_id
+			 WHERE pref.user_id::text = $1
+			   AND (p.owner_user_id IS NULL OR p.owner_user_id::text = $1)`, userID).Scan(&personaID); err != nil {
+			if err == pgx.ErrNoRows {
+				return builtinPersonaID, nil
+			}
+			return "", err
+		}
+		return personaID, nil
+	}
+
+	var exists bool
+	if err := q.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			  FROM ai_personas
+			 WHERE id = $1
+			   AND (owner_user_id IS NULL OR owner_user_id::text = $2)
+		)`, personaID, userID).Scan(&exists); err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", InvalidField("aiPersonaId", "unknown AI persona")
+	}
+	return personaID, nil
+}
+
+// setDefaultPersona records the user's default persona; an empty ID clears it
+func setDefaultPersona(ctx context.Context, q querier, userID, personaID string) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO user_preferences (user_id, default_persona_id)
+		VALUES ($1::uuid, NULLIF($2, ''))
+		ON CONFLICT (user_id) DO UPDATE
+		   SET default_persona_id = EXCLUDED.default_persona_id,
+		       updated_at = NOW()`, userID, personaID)
+	return err
+}
+
+// personaVoice returns the voice of a linked preset, which must belong to the user
+func personaVoice(ctx context.Context, q querier, userID, presetID string) (string, error) {
+	if presetID == "" {
+		return "", nil
+	}
+	var voice string
+	if err := q.QueryRow(ctx, `
+		SELECT voice_id
+		  FROM voice_presets
+		 WHERE id::text = $1
+		   AND user_id::text = $2`, presetID, userID).Scan(&voice); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", InvalidField("voicePresetId", "unknown voice preset")
+		}
+		return "", err
+	}
+	return voice, nil
+}
+
+func validatePersona(p core.AiPersona) error {
+	var verr ValidationError
+	switch n := utf8.RuneCountInString(p.Name); {
+	case n == 0:
+		verr.Fields = append(verr.Fields, FieldError{Field: "name", Message: "name is required"})
+	case n > maxPersonaNameLength:
+		verr.Fields = append(verr.Fields, FieldError{Field: "name", Message: fmt.Sprintf("name must be at most %d characters", maxPersonaNameLength)})
+	}
+	if utf8.RuneCountInString(p.Description) > maxPersonaDescriptionLength {
+		verr.Fields = append(verr.Fields, FieldError{Field: "description", Message: fmt.Sprintf("description must be at most %d characters", maxPersonaDescriptionLength)})
+	}
+	if utf8.RuneCountInString(p.Instructions) > maxPersonaInstructionsLength {
+		verr.Fields = append(verr.Fields, FieldError{Field: "instructions", Message: fmt.Sprintf("instructions must be at most %d characters", maxPersonaInstructionsLength)})
+	}
+	if len(verr.Fields) > 0 {
+		return &verr
+	}
+	return nil
+}
@@ -0,0 +1,768 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/recurrence"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Update scopes for occurrences of a recurring meeting
+const (
+	UpdateScopeOccurrence = "occurrence"
+	UpdateScopeFollowing  = "following"
+	UpdateScopeSeries     = "series"
+)
+
+const (
+	// meetingStatusSeries marks the stored template of a recurring meeting.
+	// Series rows are never started or joined; their occurrences are.
+	meetingStatusSeries = "series"
+
+	// occurrenceIDLayout is appended to the series slug to identify an
+	// occurrence by its original start time, like an iCalendar RECURRENCE-ID
+	occurrenceIDLayout = "20060102T150405Z"
+
+	defaultListLookback  = 30 * 24 * time.Hour
+	defaultListLookahead = 90 * 24 * time.Hour
+	maxListWindow        = 366 * 24 * time.Hour
+)
+
+// querier is satisfied by both the pool and transactions
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// meetingSeries is the stored template of a recurring meeting
+type meetingSeries struct {
+	ID              string
+	Slug            string
+	Title           string
+	StartTime       time.Time
+	DurationMinutes int
+	Rule            recurrence.Rule
+	Location        *time.Location
+	Exceptions      []time.Time
+}
+
+// dtstart is the series start in the zone the rule is expanded in
+func (m *meetingSeries) dtstart() time.Time {
+	return m.StartTime.In(m.Location)
+}
+
+func (m *meetingSeries) isException(t time.Time) bool {
+	for _, ex := range m.Exceptions {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOccurrence reports whether t is a scheduled (not cancelled) occurrence
+func (m *meetingSeries) hasOccurrence(t time.Time) bool {
+	return !m.isException(t) && m.Rule.Includes(m.dtstart(), t)
+}
+
+func occurrenceID(seriesSlug string, start time.Time) string {
+	return seriesSlug + "_" + start.UTC().Format(occurrenceIDLayout)
+}
+
+// splitOccurrenceID parses identifiers built by occurrenceID. Meeting slugs
+// never contain underscores, so anything else is a plain meeting identifier.
+func splitOccurrenceID(identifier string) (string, time.Time, bool) {
+	idx := strings.LastIndex(identifier, "_")
+	if idx <= 0 {
+		return "", time.Time{}, false
+	}
+	start, err := time.Parse(occurrenceIDLayout, identifier[idx+1:])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return identifier[:idx], start, true
+}
+
+// parseRecurrence validates a recurrence from a request
+func parseRecurrence(rec *core.MeetingRecurrence) (recurrence.Rule, *time.Location, error) {
+	rule, err := recurrence.Parse(rec.RRule)
+	if err != nil {
+		return recurrence.Rule{}, nil, InvalidField("recurrence.rrule", err.Error())
+	}
+	loc := time.UTC
+	if tz := strings.TrimSpace(rec.TimeZone); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return recurrence.Rule{}, nil, InvalidField("recurrence.timeZone", fmt.Sprintf("unknown time zone %q", tz))
+		}
+	}
+	return rule, loc, nil
+}
+
+// loadSeries loads a recurring meeting template by slug
+func loadSeries(ctx context.Context, q querier, slug string) (*meetingSeries, error) {
+	var (
+		series   meetingSeries
+		rule, tz string
+	)
+	if err := q.QueryRow(ctx, `
+		SELECT id::text,
+		       COALESCE(external_id, id::text),
+		       title,
+		       start_time,
+		       duration_minutes,
+		       recurrence_rule,
+		       COALESCE(recurrence_tz, 'UTC')
+		  FROM meetings
+		 WHERE COALESCE(external_id, id::text) = $1
+		   AND status = $2
+		 LIMIT 1`, slug, meetingStatusSeries,
+	).Scan(&series.ID, &series.Slug, &series.Title, &series.StartTime, &series.DurationMinutes, &rule, &tz); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("meeting_not_found", "meeting not found")
+		}
+		return nil, err
+	}
+
+	var err error
+	if series.Rule, err = recurrence.Parse(rule); err != nil {
+		return nil, fmt.Errorf("series %s has invalid rrule: %w", series.Slug, err)
+	}
+	if series.Location, err = time.LoadLocation(tz); err != nil {
+		series.Location = time.UTC
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT occurrence_start
+		  FROM meeting_recurrence_exceptions
+		 WHERE series_id = $1::uuid
+		 ORDER BY occurrence_start`, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t time.Time
+		if err := rows.Scan(&t); err != nil {
+			return nil, err
+		}
+		series.Exceptions = append(series.Exceptions, t)
+	}
+	return &series, rows.Err()
+}
+
+// getOccurrence returns an occurrence that has not been stored yet. It is
+// the series detail moved to the occurrence's start time.
+func (s *AppService) getOccurrence(ctx context.Context, identifier string) (*core.MeetingDetail, error) {
+	seriesSlug, start, ok := splitOccurrenceID(identifier)
+	if !ok {
+		return nil, notFound("meeting_not_found", "meeting not found")
+	}
+	series, err := loadSeries(ctx, s.db, seriesSlug)
+	if err != nil {
+		return nil, err
+	}
+	if !series.hasOccurrence(start) {
+		return nil, notFound("meeting_not_found", "meeting not found")
+	}
+
+	detail, err := s.GetMeeting(ctx, seriesSlug)
+	if err != nil {
+		return nil, err
+	}
+	detail.Summary.ID = identifier
+	detail.Summary.StartTime = start
+	detail.Summary.Status = s.occurrenceStatus(start, detail.Summary.DurationMinutes)
+	detail.Summary.SeriesID = seriesSlug
+	detail.Recurrence = nil
+	return detail, nil
+}
+
+// occurrenceStatus is the status of an occurrence that is not stored yet. Like
+// stored meetings closed by the lifecycle worker, it counts as missed once the
+// grace after its scheduled end has passed.
+func (s *AppService) occurrenceStatus(start time.Time, durationMinutes int) string {
+	end := start.Add(time.Duration(durationMinutes) * time.Minute)
+	if end.Add(s.missedAfter).Before(time.Now()) {
+		return MeetingStatusMissed
+	}
+	return "scheduled"
+}
+
+// materializeOccurrence stores an occurrence of a recurring meeting so that it
+// can have its own status and edits. It copies the series' details, agenda and
+// participants, and is a no-op for plain meetings and already stored occurrences.
+func (s *AppService) materializeOccurrence(ctx context.Context, tx pgx.Tx, identifier string) error {
+	seriesSlug, start, ok := splitOccurrenceID(identifier)
+	if !ok {
+		return nil
+	}
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM meetings WHERE external_id = $1)`, identifier).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	series, err := loadSeries(ctx, tx, seriesSlug)
+	if err != nil {
+		return err
+	}
+	if !series.hasOccurrence(start) {
+		return notFound("meeting_not_found", "meeting not found")
+	}
+
+	var meetingID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO meetings (external_id, title, description, host_user_id, ai_persona_id, start_time, duration_minutes, voice_profile, status, visibility, guest_approval_required, lobby_enabled, series_id, occurrence_start)
+		SELECT $1, title, description, host_user_id, ai_persona_id, $2, duration_minutes, voice_profile, $4, visibility, guest_approval_required, lobby_enabled, id, $2
+		  FROM meetings
+		 WHERE id = $3::uuid
+		ON CONFLICT DO NOTHING
+		RETURNING id::text`,
+		identifier,
+		start,
+		series.ID,
+		s.occurrenceStatus(start, series.DurationMinutes),
+	).Scan(&meetingID)
+	if err == pgx.ErrNoRows {
+		// Stored concurrently by another request
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return copyMeetingChildren(ctx, tx, series.ID, meetingID)
+}
+
+// checkOccurrenceAccess stops users who may not join a recurring meeting
+// from storing its occurrences. It only applies to occurrences that are not
+// stored yet; stored ones carry their own participants and invites. Anyone
+// with a role in the series, its invitees and, for public series, everyone
+// may proceed.
+func checkOccurrenceAccess(ctx context.Context, q querier, identifier, userID string) error {
+	seriesSlug, _, ok := splitOccurrenceID(identifier)
+	if !ok {
+		return nil
+	}
+	var stored bool
+	if err := q.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM meetings WHERE external_id = $1)`, identifier).Scan(&stored); err != nil {
+		return err
+	}
+	if stored {
+		return nil
+	}
+
+	var seriesID, hostUserID, visibility string
+	if err := q.QueryRow(ctx, `
+		SELECT id::text,
+		       COALESCE(host_user_id::text, ''),
+		       COALESCE(visibility, 'private')
+		  FROM meetings
+		 WHERE COALESCE(external_id, id::text) = $1
+		   AND status = $2
+		 LIMIT 1`, seriesSlug, meetingStatusSeries,
+	).Scan(&seriesID, &hostUserID, &visibility); err != nil {
+		if err == pgx.ErrNoRows {
+			return notFound("meeting_not_found", "meeting not found")
+		}
+		return err
+	}
+	if visibility != MeetingVisibilityPrivate {
+		return nil
+	}
+	if hostUserID != "" {
+		role, err := meetingRole(ctx, q, seriesID, userID)
+		if err != nil {
+			return err
+		}
+		if role != "" {
+			return nil
+		}
+	}
+	invited, err := isMeetingInvitee(ctx, q, seriesID, "", userID)
+	if err != nil {
+		return err
+	}
+	if !invited {
+		return forbidden("not_invited", "not invited to this meeting")
+	}
+	return nil
+}
+
+// copyMeetingChildren copies the agenda, resources, notes and participants of
+// one meeting to another. Agenda items and resources keep their ids; agenda
+// progress is not copied.
+func copyMeetingChildren(ctx context.Context, tx pgx.Tx, fromID, toID string) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO meeting_agenda_items (meeting_id, id, order_index, title, description, duration_minutes)
+		SELECT $1::uuid, id, order_index, title, description, duration_minutes
+		  FROM meeting_agenda_items
+		 WHERE meeting_id = $2::uuid`, toID, fromID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO meeting_resources (meeting_id, id, order_index, title, url, type)
+		SELECT $1::uuid, id, order_index, title, url, type
+		  FROM meeting_resources
+		 WHERE meeting_id = $2::uuid`, toID, fromID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO meeting_notes (meeting_id, content, updated_by, updated_at)
+		SELECT $1::uuid, content, updated_by, updated_at
+		  FROM meeting_notes
+		 WHERE meeting_id = $2::uuid`, toID, fromID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO meeting_participants (meeting_id, user_id, display_name, role, avatar_url)
+		SELECT $1::uuid, user_id, display_name, role, avatar_url
+		  FROM meeting_participants
+		 WHERE meeting_id = $2::uuid`, toID, fromID)
+	return err
+}
+
+// replaceRecurrenceExceptions sets the cancelled occurrences of a series
+func replaceRecurrenceExceptions(ctx context.Context, tx pgx.Tx, seriesID string, exceptions []time.Time) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM meeting_recurrence_exceptions WHERE series_id = $1::uuid`, seriesID); err != nil {
+		return err
+	}
+	for _, ex := range exceptions {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO meeting_recurrence_exceptions (series_id, occurrence_start)
+			VALUES ($1::uuid, $2)
+			ON CONFLICT DO NOTHING`, seriesID, ex.Truncate(time.Second)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateSeries applies an update to the template of a recurring meeting.
+// Stored occurrences that are still scheduled follow field changes but keep
+// their own start time. When the schedule changes, those that no longer fall
+// on it are dropped.
+func (s *AppService) updateSeries(ctx context.Context, tx pgx.Tx, seriesID string, req core.MeetingUpdateRequest) error {
+	var slug string
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(external_id, id::text)
+		  FROM meetings
+		 WHERE id = $1::uuid`, seriesID).Scan(&slug); err != nil {
+		return err
+	}
+	series, err := loadSeries(ctx, tx, slug)
+	if err != nil {
+		return err
+	}
+
+	rescheduled := false
+	if req.StartTime != nil && !req.StartTime.IsZero() {
+		start := req.StartTime.Truncate(time.Second)
+		req.StartTime = &start
+		rescheduled = !start.Equal(series.StartTime)
+	}
+	if req.Recurrence != nil {
+		rule, loc, err := parseRecurrence(req.Recurrence)
+		if err != nil {
+			return err
+		}
+		if rule.String() != series.Rule.String() || loc.String() != series.Location.String() {
+			if _, err := tx.Exec(ctx, `
+				UPDATE meetings
+				   SET recurrence_rule = $1,
+				       recurrence_tz = $2,
+				       updated_at = NOW(),
+				       ics_sequence = ics_sequence + 1
+				 WHERE id = $3::uuid`, rule.String(), loc.String(), seriesID); err != nil {
+				return err
+			}
+			rescheduled = true
+		}
+		if req.Recurrence.Exceptions != nil {
+			if err := replaceRecurrenceExceptions(ctx, tx, seriesID, req.Recurrence.Exceptions); err != nil {
+				return err
+			}
+			rescheduled = true
+		}
+	}
+
+	if err := applyMeetingUpdate(ctx, tx, seriesID, req); err != nil {
+		return err
+	}
+
+	if rescheduled {
+		if series, err = loadSeries(ctx, tx, slug); err != nil {
+			return err
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id::text, occurrence_start, start_time
+		  FROM meetings
+		 WHERE series_id = $1::uuid
+		   AND status = 'scheduled'`, seriesID)
+	if err != nil {
+		return err
+	}
+	var keep, drop []string
+	for rows.Next() {
+		var (
+			id              string
+			occurrenceStart time.Time
+			start           time.Time
+		)
+		if err := rows.Scan(&id, &occurrenceStart, &start); err != nil {
+			rows.Close()
+			return err
+		}
+		switch {
+		case rescheduled && !series.hasOccurrence(occurrenceStart):
+			drop = append(drop, id)
+		case !start.Before(time.Now()):
+			keep = append(keep, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(drop) > 0 {
+		if _, err := tx.Exec(ctx, `DELETE FROM meetings WHERE id = ANY($1::uuid[])`, drop); err != nil {
+			return err
+		}
+	}
+	// Occurrences keep their own start time
+	req.StartTime = nil
+	for _, id := range keep {
+		if err := applyMeetingUpdate(ctx, tx, id, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateFollowingOccurrences splits a series at an occurrence: the original
+// series ends just before it and a new series, with the update applied, takes
+// over from there. It returns the new series.
+func (s *AppService) updateFollowingOccurrences(ctx context.Context, seriesSlug string, start time.Time, req core.MeetingUpdateRequest) (*core.MeetingDetail, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		SELECT 1
+		  FROM meetings
+		 WHERE COALESCE(external_id, id::text) = $1
+		 FOR UPDATE`, seriesSlug); err != nil {
+		return nil, err
+	}
+	series, err := loadSeries(ctx, tx, seriesSlug)
+	if err != nil {
+		return nil, err
+	}
+	if !series.hasOccurrence(start) {
+		return nil, notFound("meeting_not_found", "meeting not found")
+	}
+
+	slug := series.Slug
+	if series.Rule.CountBefore(series.dtstart(), start) == 0 {
+		// Splitting at the first occurrence is an update of the whole series
+		if err := s.updateSeries(ctx, tx, series.ID, req); err != nil {
+			return nil, err
+		}
+	} else {
+		if slug, err = s.splitSeries(ctx, tx, series, start, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetMeeting(ctx, slug)
+}
+
+// splitSeries ends a series before the occurrence at start, which must not be
+// its first, and creates the series that continues from it
+func (s *AppService) splitSeries(ctx context.Context, tx pgx.Tx, series *meetingSeries, start time.Time, req core.MeetingUpdateRequest) (string, error) {
+	ended, rule := series.Rule.Split(series.dtstart(), start)
+	loc := series.Location
+	if req.Recurrence != nil {
+		var err error
+		if rule, loc, err = parseRecurrence(req.Recurrence); err != nil {
+			return "", err
+		}
+	}
+	if req.StartTime != nil && !req.StartTime.IsZero() {
+		start = req.StartTime.Truncate(time.Second)
+	}
+	if _, ok := rule.First(start.In(loc)); !ok {
+		return "", InvalidField("recurrence.rrule", "rule has no occurrences after startTime")
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE meetings
+		   SET recurrence_rule = $1,
+		       updated_at = NOW(),
+		       ics_sequence = ics_sequence + 1
+		 WHERE id = $2::uuid`, ended.String(), series.ID); err != nil {
+		return "", err
+	}
+
+	title := series.Title
+	if req.Title != nil && strings.TrimSpace(*req.Title) != "" {
+		title = *req.Title
+	}
+	var newID, newSlug string
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO meetings (external_id, title, description, host_user_id, ai_persona_id, start_time, duration_minutes, voice_profile, status, visibility, guest_approval_required, lobby_enabled, recurrence_rule, recurrence_tz)
+		SELECT $1, title, description, host_user_id, ai_persona_id, $2, duration_minutes, voice_profile, status, visibility, guest_approval_required, lobby_enabled, $3, $4
+		  FROM meetings
+		 WHERE id = $5::uuid
+		RETURNING id::text, COALESCE(external_id, id::text)`,
+		generateMeetingExternalID(title),
+		start,
+		rule.String(),
+		loc.String(),
+		series.ID,
+	).Scan(&newID, &newSlug); err != nil {
+		return "", err
+	}
+	if err := copyMeetingChildren(ctx, tx, series.ID, newID); err != nil {
+		return "", err
+	}
+
+	// Cancellations after the split move to the new series, unless the
+	// update brings its own
+	if req.Recurrence != nil && req.Recurrence.Exceptions != nil {
+		if err := replaceRecurrenceExceptions(ctx, tx, newID, req.Recurrence.Exceptions); err != nil {
+			return "", err
+		}
+	} else if _, err := tx.Exec(ctx, `
+		UPDATE meeting_recurrence_exceptions
+		   SET series_id = $1::uuid
+		 WHERE series_id = $2::uuid
+		   AND occurrence_start >= $3`, newID, series.ID, start); err != nil {
+		return "", err
+	}
+
+	// Stored occurrences that already started stay with the original series
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM meetings
+		 WHERE series_id = $1::uuid
+		   AND status = 'scheduled'
+		   AND occurrence_start >= $2`, series.ID, start); err != nil {
+		return "", err
+	}
+
+	req.StartTime = nil
+	if err := applyMeetingUpdate(ctx, tx, newID, req); err != nil {
+		return "", err
+	}
+	return newSlug, nil
+}
+
+// cancelOccurrence removes one occurrence of a recurring meeting by recording
+// it as an exception and dropping its stored row, if any
+func (s *AppService) cancelOccurrence(ctx context.Context, identifier string) error {
+	seriesSlug, start, _ := splitOccurrenceID(identifier)
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		series, err := loadSeries(ctx, tx, seriesSlug)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx, `
+			DELETE FROM meetings
+			 WHERE series_id = $1::uuid
+			   AND external_id = $2`, series.ID, identifier)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 && !series.hasOccurrence(start) {
+			return notFound("meeting_not_found", "meeting not found")
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO meeting_recurrence_exceptions (series_id, occurrence_start)
+			VALUES ($1::uuid, $2)
+			ON CONFLICT DO NOTHING`, series.ID, start); err != nil {
+			return err
+		}
+
+		// The new EXDATE is a change calendar clients need to pick up
+		_, err = tx.Exec(ctx, `
+			UPDATE meetings
+			   SET updated_at = NOW(),
+			       ics_sequence = ics_sequence + 1
+			 WHERE id = $1::uuid`, series.ID)
+		return err
+	})
+}
+
+// listOccurrences expands the recurring meetings of a user that overlap
+// [from, to). Occurrences that are cancelled or already stored are skipped;
+// stored ones are listed as regular meetings.
+func (s *AppService) listOccurrences(ctx context.Context, userID string, from, to time.Time) ([]core.MeetingSummary, error) {
+	query := `
+		SELECT id::text,
+		       COALESCE(external_id, id::text),
+		       title,
+		       COALESCE(description, ''),
+		       start_time,
+		       duration_minutes,
+		       COALESCE(voice_profile, ''),
+		       COALESCE(visibility, 'private'),
+		       COALESCE(host_user_id::text, ''),
+		       recurrence_rule,
+		       COALESCE(recurrence_tz, 'UTC')
+		  FROM meetings
+		 WHERE status = $1
+		   AND start_time < $2`
+	args := []any{meetingStatusSeries, to}
+	if userID != "" {
+		query += ` AND host_user_id::text = $3`
+		args = append(args, userID)
+	}
+
+	type seriesRow struct {
+		series  meetingSeries
+		summary core.MeetingSummary
+	}
+	var all []seriesRow
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var (
+			row      seriesRow
+			rule, tz string
+		)
+		if err := rows.Scan(
+			&row.series.ID,
+			&row.series.Slug,
+			&row.series.Title,
+			&row.summary.Description,
+			&row.series.StartTime,
+			&row.series.DurationMinutes,
+			&row.summary.VoiceProfile,
+			&row.summary.Visibility,
+			&row.summary.HostUserID,
+			&rule,
+			&tz,
+		); err != nil {
+			continue
+		}
+		if row.series.Rule, err = recurrence.Parse(rule); err != nil {
+			continue
+		}
+		if row.series.Location, err = time.LoadLocation(tz); err != nil {
+			row.series.Location = time.UTC
+		}
+		all = append(all, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(all))
+	for i, row := range all {
+		ids[i] = row.series.ID
+	}
+	// Both cancelled and stored occurrences are left out of the expansion
+	skip := make(map[string]map[int64]bool, len(all))
+	skipRows, err := s.db.Query(ctx, `
+		SELECT series_id::text, occurrence_start
+		  FROM meeting_recurrence_exceptions
+		 WHERE series_id = ANY($1::uuid[])
+		UNION ALL
+		SELECT series_id::text, occurrence_start
+		  FROM meetings
+		 WHERE series_id = ANY($1::uuid[])`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for skipRows.Next() {
+		var (
+			seriesID string
+			start    time.Time
+		)
+		if err := skipRows.Scan(&seriesID, &start); err != nil {
+			skipRows.Close()
+			return nil, err
+		}
+		if skip[seriesID] == nil {
+			skip[seriesID] = make(map[int64]bool)
+		}
+		skip[seriesID][start.Unix()] = true
+	}
+	skipRows.Close()
+	if err := skipRows.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []core.MeetingSummary
+	for _, row := range all {
+		duration := time.Duration(row.series.DurationMinutes) * time.Minute
+		for _, start := range row.series.Rule.Between(row.series.dtstart(), from.Add(-duration), to) {
+			if skip[row.series.ID][start.Unix()] {
+				continue
+			}
+			item := row.summary
+			item.ID = occurrenceID(row.series.Slug, start)
+			item.Title = row.series.Title
+			item.StartTime = start
+			item.DurationMinutes = row.series.DurationMinutes
+			item.Status = s.occurrenceStatus(start, row.series.DurationMinutes)
+			item.SeriesID = row.series.Slug
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// listWindow applies the defaults and limits for ListMeetings' time window
+func listWindow(from, to time.Time) (time.Time, time.Time, error) {
+	switch {
+	case from.IsZero() && to.IsZero():
+		from = time.Now().Add(-defaultListLookback)
+		to = time.Now().Add(defaultListLookahead)
+	case from.IsZero():
+		from = to.Add(-defaultListLookback - defaultListLookahead)
+	case to.IsZero():
+		to = from.Add(defaultListLookback + defaultListLookahead)
+	}
+	if !to.After(from) {
+		return from, to, InvalidField("to", "to must be after from")
+	}
+	if to.Sub(from) > maxListWindow {
+		return from, to, InvalidField("to", "time window cannot exceed one year")
+	}
+	return from, to, nil
+}
+
+func sortMeetingsByStartDesc(items []core.MeetingSummary) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].StartTime.After(items[j].StartTime)
+	})
+}
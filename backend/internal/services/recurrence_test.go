@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOccurrenceStatus(t *testing.T) {
+	s := &AppService{missedAfter: 30 * time.Minute}
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		start time.Time
+		want  string
+	}{
+		{name: "upcoming", start: now.Add(time.Hour), want: "scheduled"},
+		{name: "running", start: now.Add(-30 * time.Minute), want: "scheduled"},
+		{name: "within grace after end", start: now.Add(-80 * time.Minute), want: "scheduled"},
+		{name: "grace has passed", start: now.Add(-100 * time.Minute), want: MeetingStatusMissed},
+		{name: "long past", start: now.Add(-30 * 24 * time.Hour), want: MeetingStatusMissed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.occurrenceStatus(tt.start, 60); got != tt.want {
+				t.Errorf("occurrenceStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxMeetingResources        = 50
+	maxResourceTitleLength     = 200
+	maxResourceURLLength       = 2048
+	maxResourceTypeLength      = 40
+	maxMeetingNotesLength      = 20000
+	defaultMeetingResourceType = "link"
+)
+
+// ListMeetingResources returns the links attached to a meeting. Occurrences
+// that are not stored yet show the series' resources.
+func (s *AppService) ListMeetingResources(ctx context.Context, identifier string) (*core.MeetingResourcesResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := loadMeetingResources(ctx, s.db, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return &core.MeetingResourcesResponse{Resources: resources}, nil
+}
+
+func (s *AppService) AddMeetingResource(ctx context.Context, identifier string, req core.ResourceLink) (*core.ResourceLink, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	resource, err := normalizeResource(req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM meeting_resources WHERE meeting_id = $1::uuid`, meetingID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count >= maxMeetingResources {
+		return nil, InvalidField("resources", fmt.Sprintf("a meeting can have at most %d resources", maxMeetingResources))
+	}
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO meeting_resources (meeting_id, order_index, title, url, type)
+		VALUES ($1::uuid, COALESCE((SELECT MAX(order_index) FROM meeting_resources WHERE meeting_id = $1::uuid), 0) + 1, $2, $3, $4)
+		RETURNING id::text`,
+		meetingID,
+		resource.Title,
+		resource.URL,
+		resource.Type,
+	).Scan(&resource.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (s *AppService) UpdateMeetingResource(ctx context.Context, identifier, resourceID string, req core.UpdateMeetingResourceRequest) (*core.ResourceLink, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	resourceID = strings.TrimSpace(resourceID)
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing core.ResourceLink
+	if err := tx.QueryRow(ctx, `
+		SELECT id::text, title, url, type
+		  FROM meeting_resources
+		 WHERE meeting_id = $1::uuid
+		   AND id::text = $2
+		 FOR UPDATE`, meetingID, resourceID).Scan(&existing.ID, &existing.Title, &existing.URL, &existing.Type); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("resource_not_found", "resource not found")
+		}
+		return nil, err
+	}
+
+	updated := existing
+	if req.Title != nil {
+		updated.Title = *req.Title
+	}
+	if req.URL != nil {
+		updated.URL = *req.URL
+	}
+	if req.Type != nil {
+		updated.Type = *req.Type
+	}
+	resource, err := normalizeResource(updated, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE meeting_resources
+		   SET title = $1,
+		       url = $2,
+		       type = $3,
+		       updated_at = NOW()
+		 WHERE meeting_id = $4::uuid
+		   AND id::text = $5`,
+		resource.Title,
+		resource.URL,
+		resource.Type,
+		meetingID,
+		resourceID,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (s *AppService) DeleteMeetingResource(ctx context.Context, identifier, resourceID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	resourceID = strings.TrimSpace(resourceID)
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(ctx, `
+			DELETE FROM meeting_resources
+			 WHERE meeting_id = $1::uuid
+			   AND id::text = $2`, meetingID, resourceID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return notFound("resource_not_found", "resource not found")
+		}
+		return nil
+	})
+}
+
+// GetMeetingNotes returns the host's notes for a meeting
+func (s *AppService) GetMeetingNotes(ctx context.Context, identifier string) (*core.MeetingNotes, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return loadMeetingNotes(ctx, s.db, meetingID)
+}
+
+// UpdateMeetingNotes replaces the notes of a meeting; empty content clears them
+func (s *AppService) UpdateMeetingNotes(ctx context.Context, identifier, userID string, req core.UpdateMeetingNotesRequest) (*core.MeetingNotes, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	content := strings.TrimSpace(req.Content)
+	if utf8.RuneCountInString(content) > maxMeetingNotesLength {
+		return nil, InvalidField("content", fmt.Sprintf("notes must be at most %d characters", maxMeetingNotesLength))
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := setMeetingNotes(ctx, tx, meetingID, strings.TrimSpace(userID), content); err != nil {
+		return nil, err
+	}
+
+	notes, err := loadMeetingNotes(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// setMeetingNotes stores or, for empty content, removes a meeting's notes
+func setMeetingNotes(ctx context.Context, q querier, meetingID, userID, content string) error {
+	if content == "" {
+		_, err := q.Exec(ctx, `DELETE FROM meeting_notes WHERE meeting_id = $1::uuid`, meetingID)
+		return err
+	}
+	_, err := q.Exec(ctx, `
+		INSERT INTO meeting_notes (meeting_id, content, updated_by)
+		VALUES ($1::uuid, $2, NULLIF($3, '')::uuid)
+		ON CONFLICT (meeting_id) DO UPDATE
+		   SET content = EXCLUDED.content,
+		       updated_by = EXCLUDED.updated_by,
+		       updated_at = NOW()`, meetingID, content, userID)
+	return err
+}
+
+func loadMeetingNotes(ctx context.Context, q querier, meetingID string) (*core.MeetingNotes, error) {
+	var (
+		notes     core.MeetingNotes
+		updatedAt time.Time
+	)
+	if err := q.QueryRow(ctx, `
+		SELECT content, COALESCE(updated_by::text, ''), updated_at
+		  FROM meeting_notes
+		 WHERE meeting_id = $1::uuid`, meetingID).Scan(&notes.Content, &notes.UpdatedBy, &updatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return &core.MeetingNotes{}, nil
+		}
+		return nil, err
+	}
+	notes.UpdatedAt = &updatedAt
+	return &notes, nil
+}
+
+func loadMeetingResources(ctx context.Context, q querier, meetingID string) ([]core.ResourceLink, error) {
+	rows, err := q.Query(ctx, `
+		SELECT id::text, title, url, type
+		  FROM meeting_resources
+		 WHERE meeting_id = $1::uuid
+		 ORDER BY order_index`, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resources := []core.ResourceLink{}
+	for rows.Next() {
+		var r core.ResourceLink
+		if err := rows.Scan(&r.ID, &r.Title, &r.URL, &r.Type); err != nil {
+			return nil, err
+		}
+		resources = append(resources, r)
+	}
+	return resources, rows.Err()
+}
+
+// insertMeetingResources validates and stores the resources of a new meeting
+func insertMeetingResources(ctx context.Context, tx pgx.Tx, meetingID string, resources []core.ResourceLink) error {
+	if len(resources) > maxMeetingResources {
+		return InvalidField("resources", fmt.Sprintf("a meeting can have at most %d resources", maxMeetingResources))
+	}
+	for idx, item := range resources {
+		resource, err := normalizeResource(item, fmt.Sprintf("resources[%d].", idx))
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO meeting_resources (meeting_id, order_index, title, url, type)
+			VALUES ($1::uuid, $2, $3, $4, $5)`,
+			meetingID,
+			idx+1,
+			resource.Title,
+			resource.URL,
+			resource.Type,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// meetingRowID resolves a meeting slug for reading. Occurrences that are not
+// stored yet resolve to their series.
+func meetingRowID(ctx context.Context, q querier, identifier string) (string, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return "", InvalidField("meetingId", "meeting identifier is required")
+	}
+	var meetingID string
+	err := q.QueryRow(ctx, `
+		SELECT id::text
+		  FROM meetings
+		 WHERE COALESCE(external_id, id::text) = $1`, identifier).Scan(&meetingID)
+	if err == pgx.ErrNoRows {
+		if seriesSlug, _, ok := splitOccurrenceID(identifier); ok {
+			return meetingRowID(ctx, q, seriesSlug)
+		}
+		return "", notFound("meeting_not_found", "meeting not found")
+	}
+	return meetingID, err
+}
+
+// editableMeetingID locks a meeting for changes to its resources or notes,
+// storing an occurrence of a recurring meeting first so that the change
+// applies to that occurrence alone
+func (s *AppService) editableMeetingID(ctx context.Context, tx pgx.Tx, identifier string) (string, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return "", InvalidField("meetingId", "meeting identifier is required")
+	}
+	if err := s.materializeOccurrence(ctx, tx, identifier); err != nil {
+		return "", err
+	}
+	var meetingID string
+	if err := tx.QueryRow(ctx, `
+		SELECT id::text
+		  FROM meetings
+		 WHERE COALESCE(external_id, id::text) = $1
+		 FOR UPDATE`, identifier).Scan(&meetingID); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", notFound("meeting_not_found", "meeting not found")
+		}
+		return "", err
+	}
+	return meetingID, nil
+}
+
+// normalizeResource trims and validates a resource; prefix qualifies field
+// names in errors
+func normalizeResource(r core.ResourceLink, prefix string) (core.ResourceLink, error) {
+	resource := core.ResourceLink{
+		ID:    r.ID,
+		Title: strings.TrimSpace(r.Title),
+		URL:   strings.TrimSpace(r.URL),
+		Type:  strings.ToLower(strings.TrimSpace(r.Type)),
+	}
+	if resource.Type == "" {
+		resource.Type = defaultMeetingResourceType
+	}
+
+	var verr ValidationError
+	switch n := utf8.RuneCountInString(resource.Title); {
+	case n == 0:
+		verr.Fields = append(verr.Fields, FieldError{Field: prefix + "title", Message: "title is required"})
+	case n > maxResourceTitleLength:
+		verr.Fields = append(verr.Fields, FieldError{Field: prefix + "title", Message: fmt.Sprintf("title must be at most %d characters", maxResourceTitleLength)})
+	}
+	if msg := validateResourceURL(resource.URL); msg != "" {
+		verr.Fields = append(verr.Fields, FieldError{Field: prefix + "url", Message: msg})
+	}
+	if utf8.RuneCountInString(resource.Type) > maxResourceTypeLength {
+		verr.Fields = append(verr.Fields, FieldError{Field: prefix + "type", Message: fmt.Sprintf("type must be at most %d characters", maxResourceTypeLength)})
+	}
+	if len(verr.Fields) > 0 {
+		return core.ResourceLink{}, &verr
+	}
+	return resource, nil
+}
+
+// validateResourceURL only accepts absolute web links, so that resources
+// rendered as links cannot run script (javascript:, data:)
+func validateResourceURL(raw string) string {
+	if raw == "" {
+		return "url is required"
+	}
+	if len(raw) > maxResourceURLLength {
+		return fmt.Sprintf("url must be at most %d characters", maxResourceURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "url is not valid"
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "url must use http or https"
+	}
+	if u.Host == "" {
+		return "url must include a host"
+	}
+	return ""
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+// Participant roles. The host is whoever meetings.host_user_id names; the
+// other roles are stored on meeting_participants.
+const (
+	RoleHost      = "host"
+	RoleCoHost    = "co_host"
+	RolePresenter = "presenter"
+	RoleViewer    = "viewer"
+)
+
+// Meeting permissions
+const (
+	PermissionStart = "start"
+	PermissionEnd   = "end"
+	PermissionEdit  = "edit"
+	// Invite covers handing out and revoking the meeting's guest link
+	PermissionInvite         = "invite"
+	PermissionAdmit          = "admit"
+	PermissionViewTranscript = "view_transcript"
+	// Only the host can change roles, hand the meeting over or delete it
+	PermissionManageRoles = "manage_roles"
+	PermissionDelete      = "delete"
+)
+
+// rolePermissions is the permission matrix
+var rolePermissions = map[string][]string{
+	RoleHost: {
+		PermissionStart, PermissionEnd, PermissionEdit, PermissionInvite, PermissionAdmit,
+		PermissionViewTranscript, PermissionManageRoles, PermissionDelete,
+	},
+	RoleCoHost: {
+		PermissionStart, PermissionEnd, PermissionEdit, PermissionInvite, PermissionAdmit,
+		PermissionViewTranscript,
+	},
+	RolePresenter: {PermissionViewTranscript},
+	RoleViewer:    {PermissionViewTranscript},
+}
+
+// RoleAllows reports whether a role grants a permission
+func RoleAllows(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// MeetingRole returns the user's role in a meeting, or an empty string when
+// the user has none. Occurrences of a recurring meeting that are not stored
+// yet use the roles of their series.
+func (s *AppService) MeetingRole(ctx context.Context, identifier, userID string) (string, error) {
+	if err := s.ensureDB(); err != nil {
+		return "", err
+	}
+	meetingID, err := meetingRowID(ctx, s.db, identifier)
+	if err != nil {
+		return "", err
+	}
+	return meetingRole(ctx, s.db, meetingID, userID)
+}
+
+// MeetingPermissions returns the user's role in a meeting and what it allows
+func (s *AppService) MeetingPermissions(ctx context.Context, identifier, userID string) (*core.MeetingPermissions, error) {
+	role, err := s.MeetingRole(ctx, identifier, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &core.MeetingPermissions{
+		Role:        role,
+		Permissions: append([]string{}, rolePermissions[role]...),
+	}, nil
+}
+
+// SetParticipantRole promotes or demotes a participant. The host role can
+// only change hands through TransferMeetingOwnership.
+func (s *AppService) SetParticipantRole(ctx context.Context, identifier, userID string, req core.UpdateParticipantRoleRequest) (*core.MeetingParticipant, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	switch role {
+	case RoleCoHost, RolePresenter, RoleViewer:
+	case RoleHost:
+		return nil, InvalidField("role", "transfer the meeting to make someone else the host")
+	default:
+		return nil, InvalidField("role", "role must be co_host, presenter or viewer")
+	}
+	userID = strings.TrimSpace(userID)
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostUserID string
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(host_user_id::text, '') FROM meetings WHERE id = $1::uuid`, meetingID).Scan(&hostUserID); err != nil {
+		return nil, err
+	}
+	if userID == hostUserID {
+		return nil, conflict("participant_is_host", "transfer the meeting before changing the host's role")
+	}
+
+	var p core.MeetingParticipant
+	if err := tx.QueryRow(ctx, `
+		UPDATE meeting_participants
+		   SET role = $3
+		 WHERE meeting_id = $1::uuid
+		   AND user_id::text = $2
+		RETURNING user_id::text, display_name, role, COALESCE(avatar_url, '')`,
+		meetingID, userID, role).Scan(&p.ID, &p.Name, &p.Role, &p.AvatarURL); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("participant_not_found", "participant not found in this meeting")
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// TransferMeetingOwnership makes another user the host. The previous host
+// stays on as co-host. Transferring a recurring meeting also transfers its
+// stored occurrences that have not taken place yet.
+func (s *AppService) TransferMeetingOwnership(ctx context.Context, identifier string, req core.TransferMeetingRequest) (*core.MeetingDetail, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	newHostID := strings.TrimSpace(req.UserID)
+	if newHostID == "" {
+		return nil, InvalidField("userId", "userId is required")
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	meetingID, err := s.editableMeetingID(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var slug, hostUserID, status string
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(external_id, id::text), COALESCE(host_user_id::text, ''), status
+		  FROM meetings
+		 WHERE id = $1::uuid`, meetingID).Scan(&slug, &hostUserID, &status); err != nil {
+		return nil, err
+	}
+	if err := closedMeetingError(status); err != nil {
+		return nil, err
+	}
+
+	var userExists bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM app_users WHERE id::text = $1 AND disabled_at IS NULL)`, newHostID).Scan(&userExists); err != nil {
+		return nil, err
+	}
+	if !userExists {
+		return nil, InvalidField("userId", "unknown user")
+	}
+
+	if newHostID != hostUserID {
+		meetingIDs := []string{meetingID}
+		if status == meetingStatusSeries {
+			rows, err := tx.Query(ctx, `
+				SELECT id::text
+				  FROM meetings
+				 WHERE series_id = $1::uuid
+				   AND status = 'scheduled'`, meetingID)
+			if err != nil {
+				return nil, err
+			}
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					rows.Close()
+					return nil, err
+				}
+				meetingIDs = append(meetingIDs, id)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, id := range meetingIDs {
+			if err := transferHost(ctx, tx, id, hostUserID, newHostID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetMeeting(ctx, slug)
+}
+
+// transferHost moves the host of one meeting row from one user to another
+func transferHost(ctx context.Context, tx pgx.Tx, meetingID, fromUserID, toUserID string) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE meetings
+		   SET host_user_id = $2::uuid,
+		       updated_at = NOW(),
+		       ics_sequence = ics_sequence + 1
+		 WHERE id = $1::uuid`, meetingID, toUserID); err != nil {
+		return err
+	}
+	if fromUserID != "" {
+		if _, err := tx.Exec(ctx, `
+			UPDATE meeting_participants
+			   SET role = $3
+			 WHERE meeting_id = $1::uuid
+			   AND user_id::text = $2`, meetingID, fromUserID, RoleCoHost); err != nil {
+			return err
+		}
+	}
+	if err := ensureParticipant(ctx, tx, meetingID, toUserID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		UPDATE meeting_participants
+		   SET role = $3,
+		       admission_status = CASE WHEN admission_status IS NULL THEN NULL ELSE $4 END
+		 WHERE meeting_id = $1::uuid
+		   AND user_id::text = $2`, meetingID, toUserID, RoleHost, AdmissionAdmitted)
+	return err
+}
+
+// meetingRole resolves a user's role in a stored meeting. Meetings without a
+// host predate ownership and are open to everyone, as before. Participants
+// who are waiting in the lobby or were denied have no role.
+func meetingRole(ctx context.Context, q querier, meetingID, userID string) (string, error) {
+	userID = strings.TrimSpace(userID)
+
+	var hostUserID, role, admission string
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(m.host_user_id::text, ''),
+		       COALESCE(p.role, ''),
+		       COALESCE(p.admission_status, '')
+		  FROM meetings m
+		  LEFT JOIN meeting_participants p
+		    ON p.meeting_id = m.id
+		   AND p.user_id::text = $2
+		 WHERE m.id = $1::uuid`, meetingID, userID).Scan(&hostUserID, &role, &admission)
+	if err == pgx.ErrNoRows {
+		return "", notFound("meeting_not_found", "meeting not found")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case hostUserID == "":
+		return RoleHost, nil
+	case userID == "":
+		return "", nil
+	case userID == hostUserID:
+		return RoleHost, nil
+	case admission == AdmissionWaiting || admission == AdmissionDenied:
+		return "", nil
+	case role == RoleHost:
+		// Left over from a host change made outside TransferMeetingOwnership
+		return RoleCoHost, nil
+	}
+	return role, nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import "testing"
+
+func TestRoleAllows(t *testing.T) {
+	permissions := []string{
+		PermissionStart, PermissionEnd, PermissionEdit, PermissionInvite, PermissionAdmit,
+		PermissionViewTranscript, PermissionManageRoles, PermissionDelete,
+	}
+	// Permissions each role is granted; everything else is denied
+	want := map[string][]string{
+		RoleHost: permissions,
+		RoleCoHost: {
+			PermissionStart, PermissionEnd, PermissionEdit, PermissionInvite, PermissionAdmit,
+			PermissionViewTranscript,
+		},
+		RolePresenter: {PermissionViewTranscript},
+		RoleViewer:    {PermissionViewTranscript},
+		"":            nil,
+		"Host":        nil,
+	}
+
+	for role, granted := range want {
+		allowed := make(map[string]bool, len(granted))
+		for _, p := range granted {
+			allowed[p] = true
+		}
+		for _, p := range append(permissions, "unknown") {
+			if got := RoleAllows(role, p); got != allowed[p] {
+				t.Errorf("RoleAllows(%q, %q) = %v, want %v", role, p, got, allowed[p])
+			}
+		}
+	}
+}
@@ -4,14 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type Logger interface {
-	Printf(format string, v ...any)
-	Println(v ...any)
-}
-
 const (
 	alexID          = "11111111-1111-1111-1111-111111111111"
 	jordanID        = "22222222-2222-2222-2222-222222222222"
@@ -26,7 +22,7 @@ const (
 	seedPasswordHash = "$2a$12$NMS4Hy9KaLgsx3ElJYyhf.7WCs8IYtPZxoMA.BHTTX3e8DqRLVQBm"
 )
 
-func Seed(ctx context.Context, pool *pgxpool.Pool, logger Logger) error {
+func Seed(ctx context.Context, pool *pgxpool.Pool, log logger.Logger) error {
 	if pool == nil {
 		return nil
 	}
@@ -42,8 +38,8 @@ func Seed(ctx context.Context, pool *pgxpool.Pool, logger Logger) error {
 		return fmt.Errorf("count app_users: %w", err)
 	}
 	if count > 0 {
-		if logger != nil {
-			logger.Println("seed data already present")
+		if log != nil {
+			log.Info("seed data already present")
 		}
 		return tx.Commit(ctx)
 	}
@@ -69,8 +65,8 @@ func Seed(ctx context.Context, pool *pgxpool.Pool, logger Logger) error {
 
 		fmt.Sprintf(`INSERT INTO meeting_participants (meeting_id, user_id, display_name, role, avatar_url, joined_at)
 			VALUES
-				('%s', '%s', 'Alex Rivera', 'Host', 'https://avatar.vercel.sh/you', NOW()),
-				('%s', '%s', 'Jordan Chen', 'Product Lead', 'https://avatar.vercel.sh/jordan', NULL)`,
+				('%s', '%s', 'Alex Rivera', 'host', 'https://avatar.vercel.sh/you', NOW()),
+				('%s', '%s', 'Jordan Chen', 'presenter', 'https://avatar.vercel.sh/jordan', NULL)`,
 			meetingID, alexID, meetingID, jordanID),
 
 		fmt.Sprintf(`INSERT INTO meeting_agenda_items (id, meeting_id, order_index, title, description, duration_minutes)
@@ -121,8 +117,8 @@ func Seed(ctx context.Context, pool *pgxpool.Pool, logger Logger) error {
 		}
 	}
 
-	if logger != nil {
-		logger.Println("seed data inserted")
+	if log != nil {
+		log.Info("seed data inserted")
 	}
 
 	return tx.Commit(ctx)
@@ -5,23 +5,106 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/logger"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/metrics"
+	"github.com/aicomp/ai-virtual-chat/backend/internal/tracing"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AppService struct {
-	db *pgxpool.Pool
+	db            *pgxpool.Pool
+	loginAttempts loginAttemptStore
+	events        meetingEventBus
+	eventHub      *meetingEventHub
+	mailer        Mailer
+	logger        logger.Logger
+	appURL        string
+	apiURL        string
+	// notificationChannels deliver notifications in this order
+	notificationChannels []NotificationChannel
+	// mailConfigured is set when a mail transport delivers email, rather
+	// than the logging fallback
+	mailConfigured bool
+	// allowPrivateCalendarURLs disables the private address check on calendar imports
+	allowPrivateCalendarURLs bool
+	// missedAfter is the grace after which an unstarted occurrence is missed
+	missedAfter time.Duration
 }
 
-func NewAppService(db *pgxpool.Pool) *AppService {
-	return &AppService{db: db}
+// Options carries the optional collaborators of AppService
+type Options struct {
+	// Redis is used for shared state across replicas when available
+	Redis *redis.Client
+	// Mailer delivers transactional email; without one, email is only logged
+	Mailer Mailer
+	// AppURL is the public frontend URL used to build links in emails
+	AppURL string
+	// APIURL is the public URL of this API, used for calendar feed links
+	APIURL string
+	// AllowPrivateCalendarURLs lets calendar imports fetch private and
+	// loopback addresses, e.g. a local test server
+	AllowPrivateCalendarURLs bool
+	// MissedAfter is how long after its scheduled end an occurrence of a
+	// recurring meeting that was never stored counts as missed. It should
+	// match the lifecycle worker's MeetingLifecycleOptions.MissedAfter.
+	MissedAfter time.Duration
+	// NotificationChannels are added to the built-in in-app, email and
+	// webhook channels. The email channel needs Mailer.
+	NotificationChannels []NotificationChannel
+	Logger               logger.Logger
+}
+
+func NewAppService(db *pgxpool.Pool, opts Options) *AppService {
+	svc := &AppService{
+		db:     db,
+		mailer: opts.Mailer,
+		logger: opts.Logger,
+		appURL: opts.AppURL,
+		apiURL: opts.APIURL,
+
+		mailConfigured: opts.Mailer != nil,
+
+		eventHub: newMeetingEventHub(),
+
+		allowPrivateCalendarURLs: opts.AllowPrivateCalendarURLs,
+		missedAfter:              opts.MissedAfter,
+	}
+	if svc.logger == nil {
+		svc.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if svc.mailer == nil {
+		svc.mailer = LogMailer{Logger: opts.Logger}
+	}
+	// Without a mail transport the email channel is left out, so it is not
+	// offered in preferences
+	svc.notificationChannels = []NotificationChannel{&inAppChannel{db: db}}
+	if opts.Mailer != nil {
+		svc.notificationChannels = append(svc.notificationChannels, &emailChannel{mailer: opts.Mailer})
+	}
+	svc.notificationChannels = append(svc.notificationChannels, newWebhookChannel(db))
+	svc.notificationChannels = append(svc.notificationChannels, opts.NotificationChannels...)
+	if opts.Redis != nil {
+		svc.loginAttempts = &redisLoginAttemptStore{client: opts.Redis}
+		svc.events = &redisMeetingEventBus{client: opts.Redis, hub: svc.eventHub}
+	} else {
+		svc.loginAttempts = &postgresLoginAttemptStore{db: db}
+		svc.events = &localMeetingEventBus{hub: svc.eventHub}
+	}
+	return svc
 }
 
 const (
@@ -43,16 +126,25 @@ func hashRefreshToken(token string) string {
 
 func (s *AppService) ensureDB() error {
 	if s.db == nil {
-		return fmt.Errorf("database connection not available")
+		return unavailable("database_unavailable", "database connection not available")
 	}
 	return nil
 }
 
+// requireUser returns the trimmed id of the acting user, or an error when the
+// request carries none
+func requireUser(userID string) (string, error) {
+	if userID = strings.TrimSpace(userID); userID == "" {
+		return "", unauthorized("session_invalid", "authentication required")
+	}
+	return userID, nil
+}
+
 func (s *AppService) firstUserID(ctx context.Context) (string, error) {
 	var id string
 	if err := s.db.QueryRow(ctx, `SELECT id::text FROM app_users ORDER BY created_at LIMIT 1`).Scan(&id); err != nil {
 		if err == pgx.ErrNoRows {
-			return "", fmt.Errorf("no users found")
+			return "", notFound("user_not_found", "no users found")
 		}
 		return "", err
 	}
@@ -104,7 +196,7 @@ func (s *AppService) GetAuthSession(ctx context.Context, userID string) (*core.A
 		&resp.Preferences.NotificationsOpt,
 	); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, notFound("user_not_found", "user not found")
 		}
 		return nil, err
 	}
@@ -137,10 +229,16 @@ func (s *AppService) AuthenticateUser(ctx context.Context, email string, passwor
 	password = strings.TrimSpace(password)
 
 	if email == "" {
-		return nil, fmt.Errorf("email is required")
+		return nil, InvalidField("email", "email is required")
 	}
 	if password == "" {
-		return nil, fmt.Errorf("password is required")
+		return nil, InvalidField("password", "password is required")
+	}
+
+	// Throttling is keyed by email, not user id, so unknown accounts behave
+	// exactly like real ones.
+	if err := s.checkLoginAllowed(ctx, email); err != nil {
+		return nil, err
 	}
 
 	var userID string
@@ -149,21 +247,31 @@ func (s *AppService) AuthenticateUser(ctx context.Context, email string, passwor
 		SELECT id::text, COALESCE(password_hash, '')
 		  FROM app_users
 		 WHERE LOWER(email) = $1
+		   AND disabled_at IS NULL
 		 LIMIT 1`, email).Scan(&userID, &passwordHash); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("invalid credentials")
+			compareDummyPassword(password)
+			s.recordLoginFailure(ctx, email)
+			return nil, unauthorized("invalid_credentials", "invalid credentials")
 		}
 		return nil, err
 	}
 
 	if passwordHash == "" {
-		return nil, fmt.Errorf("invalid credentials")
+		compareDummyPassword(password)
+		s.recordLoginFailure(ctx, email)
+		return nil, unauthorized("invalid_credentials", "invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)); err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		s.recordLoginFailure(ctx, email)
+		return nil, unauthorized("invalid_credentials", "invalid credentials")
 	}
 
+	// Best effort: a stale counter only delays the next failure's backoff
+	_ = s.loginAttempts.Reset(ctx, email)
+	metrics.Logins.Inc()
+
 	return s.GetAuthSession(ctx, userID)
 }
 
@@ -172,26 +280,36 @@ func (s *AppService) RegisterUser(ctx context.Context, req core.AuthRegisterRequ
 		return nil, err
 	}
 
-	name := strings.TrimSpace(req.Name)
-	email := strings.TrimSpace(strings.ToLower(req.Email))
-	password := strings.TrimSpace(req.Password)
+	userID, err := s.createUser(ctx, req.Name, req.Email, req.Password, "free")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAuthSession(ctx, userID)
+}
+
+// createUser validates and inserts a user with default preferences
+func (s *AppService) createUser(ctx context.Context, name, email, password, planTier string) (string, error) {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(strings.ToLower(email))
+	password = strings.TrimSpace(password)
 
 	if name == "" {
-		return nil, fmt.Errorf("name is required")
+		return "", InvalidField("name", "name is required")
 	}
 	if email == "" {
-		return nil, fmt.Errorf("email is required")
+		return "", InvalidField("email", "email is required")
 	}
 	if password == "" {
-		return nil, fmt.Errorf("password is required")
+		return "", InvalidField("password", "password is required")
 	}
-	if len(password) < 8 {
-		return nil, fmt.Errorf("password must be at least 8 characters long")
+	if err := validatePassword(password); err != nil {
+		return "", err
 	}
 
 	hashBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password")
+		return "", fmt.Errorf("failed to hash password")
 	}
 
 	var userID string
@@ -202,13 +320,14 @@ func (s *AppService) RegisterUser(ctx context.Context, req core.AuthRegisterRequ
 		email,
 		name,
 		"",
-		"free",
+		planTier,
 		string(hashBytes),
 	).Scan(&userID); err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "duplicate") {
-			return nil, fmt.Errorf("email already exists")
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return "", conflict("email_taken", "email already exists")
 		}
-		return nil, err
+		return "", err
 	}
 
 	if _, err := s.db.Exec(ctx, `
@@ -217,10 +336,17 @@ func (s *AppService) RegisterUser(ctx context.Context, req core.AuthRegisterRequ
 		ON CONFLICT (user_id) DO NOTHING`,
 		userID,
 	); err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return s.GetAuthSession(ctx, userID)
+	return userID, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < 8 {
+		return InvalidField("password", "password must be at least 8 characters long")
+	}
+	return nil
 }
 
 func (s *AppService) CreateSession(ctx context.Context, userID string) (string, string, time.Time, error) {
@@ -230,7 +356,7 @@ func (s *AppService) CreateSession(ctx context.Context, userID string) (string,
 
 	userID = strings.TrimSpace(userID)
 	if userID == "" {
-		return "", "", time.Time{}, fmt.Errorf("user id is required")
+		return "", "", time.Time{}, InvalidField("userId", "user id is required")
 	}
 
 	refreshToken, err := generateRandomHex(32)
@@ -263,7 +389,7 @@ func (s *AppService) RefreshSession(ctx context.Context, refreshToken string) (s
 
 	refreshToken = strings.TrimSpace(refreshToken)
 	if refreshToken == "" {
-		return "", "", time.Time{}, "", fmt.Errorf("refresh token is required")
+		return "", "", time.Time{}, "", InvalidField("refreshToken", "refresh token is required")
 	}
 
 	refreshHash := hashRefreshToken(refreshToken)
@@ -284,7 +410,7 @@ func (s *AppService) RefreshSession(ctx context.Context, refreshToken string) (s
 	).Scan(&sessionID, &userID, &expiresAt, &revoked)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return "", "", time.Time{}, "", fmt.Errorf("invalid refresh token")
+			return "", "", time.Time{}, "", unauthorized("invalid_refresh_token", "invalid refresh token")
 		}
 		return "", "", time.Time{}, "", err
 	}
@@ -294,7 +420,7 @@ func (s *AppService) RefreshSession(ctx context.Context, refreshToken string) (s
 			UPDATE session_tokens
 			   SET revoked = TRUE
 			 WHERE id::uuid = $1`, sessionID)
-		return "", "", time.Time{}, "", fmt.Errorf("invalid refresh token")
+		return "", "", time.Time{}, "", unauthorized("invalid_refresh_token", "invalid refresh token")
 	}
 
 	newRefreshToken, err := generateRandomHex(32)
@@ -318,6 +444,8 @@ func (s *AppService) RefreshSession(ctx context.Context, refreshToken string) (s
 		return "", "", time.Time{}, "", err
 	}
 
+	metrics.RefreshRotations.Inc()
+
 	return sessionID, newRefreshToken, newExpires, userID, nil
 }
 
@@ -328,7 +456,7 @@ func (s *AppService) RevokeSession(ctx context.Context, sessionID string) error
 
 	sessionID = strings.TrimSpace(sessionID)
 	if sessionID == "" {
-		return fmt.Errorf("session id is required")
+		return InvalidField("sessionId", "session id is required")
 	}
 
 	result, err := s.db.Exec(ctx, `
@@ -339,7 +467,7 @@ func (s *AppService) RevokeSession(ctx context.Context, sessionID string) error
 		return err
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("session not found")
+		return notFound("session_not_found", "session not found")
 	}
 	return nil
 }
@@ -351,7 +479,7 @@ func (s *AppService) ValidateSession(ctx context.Context, sessionID string) (str
 
 	sessionID = strings.TrimSpace(sessionID)
 	if sessionID == "" {
-		return "", fmt.Errorf("session id is required")
+		return "", InvalidField("sessionId", "session id is required")
 	}
 
 	var (
@@ -369,17 +497,17 @@ func (s *AppService) ValidateSession(ctx context.Context, sessionID string) (str
 	).Scan(&userID, &expiresAt, &revoked)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return "", fmt.Errorf("session not found")
+			return "", notFound("session_not_found", "session not found")
 		}
 		return "", err
 	}
 
 	if revoked {
-		return "", fmt.Errorf("session revoked")
+		return "", unauthorized("session_revoked", "session revoked")
 	}
 
 	if expiresAt.Before(time.Now()) {
-		return "", fmt.Errorf("session expired")
+		return "", unauthorized("session_expired", "session expired")
 	}
 
 	return userID, nil
@@ -440,8 +568,24 @@ func (s *AppService) GetDashboardOverview(ctx context.Context, userID string) (*
 		ORDER BY start_time
 		LIMIT 1
 	`, userID).Scan(&nextMeetingTitle, &nextMeetingDuration, &nextMeetingTime)
+	found := err == nil
 
-	if err == nil {
+	// Recurring meetings only store the occurrences that were changed, so
+	// the next one may still be part of a series
+	now := time.Now()
+	occurrences, err := s.listOccurrences(ctx, userID, now, now.Add(maxListWindow))
+	if err != nil {
+		return nil, err
+	}
+	for _, occ := range occurrences {
+		if occ.StartTime.Before(now) || (found && !occ.StartTime.Before(nextMeetingTime)) {
+			continue
+		}
+		nextMeetingTitle, nextMeetingDuration, nextMeetingTime = occ.Title, occ.DurationMinutes, occ.StartTime
+		found = true
+	}
+
+	if found {
 		// Only set defaults if meeting exists but has missing data
 		if nextMeetingTitle == "" {
 			nextMeetingTitle = "Upcoming session"
@@ -463,9 +607,17 @@ func (s *AppService) GetDashboardOverview(ctx context.Context, userID string) (*
 		}
 	}
 
-	overview.Streak = s.sessionStreak(ctx, userID)
-	overview.InsightMetrics = s.dashboardMetrics(ctx, userID)
-	overview.RecentSessions = s.recentSessions(ctx, userID)
+	streakCtx, span := tracing.Tracer().Start(ctx, "dashboard.sessionStreak")
+	overview.Streak = s.sessionStreak(streakCtx, userID)
+	span.End()
+
+	metricsCtx, span := tracing.Tracer().Start(ctx, "dashboard.metrics")
+	overview.InsightMetrics = s.dashboardMetrics(metricsCtx, userID)
+	span.End()
+
+	recentCtx, span := tracing.Tracer().Start(ctx, "dashboard.recentSessions")
+	overview.RecentSessions = s.recentSessions(recentCtx, userID)
+	span.End()
 
 	return &overview, nil
 }
@@ -662,12 +814,21 @@ func (s *AppService) recentSessions(ctx context.Context, userID string) []core.S
 	return sessions
 }
 
-func (s *AppService) ListMeetings(ctx context.Context, userID string) (*core.MeetingsResponse, error) {
+// ListMeetings lists a user's meetings. Occurrences of recurring meetings are
+// expanded within [from, to); when no window is given it defaults to the last
+// 30 and next 90 days, and one-off meetings are listed regardless of date.
+func (s *AppService) ListMeetings(ctx context.Context, userID string, from, to time.Time) (*core.MeetingsResponse, error) {
 	if err := s.ensureDB(); err != nil {
 		return nil, err
 	}
 
 	userID = strings.TrimSpace(userID)
+	hasWindow := !from.IsZero() || !to.IsZero()
+	from, to, err := listWindow(from, to)
+	if err != nil {
+		return nil, err
+	}
+
 	// Filter by userID if provided, otherwise return all (for backward compatibility)
 	query := `
 		SELECT COALESCE(external_id, id::text),
@@ -677,13 +838,19 @@ func (s *AppService) ListMeetings(ctx context.Context, userID string) (*core.Mee
 			   duration_minutes,
 			   COALESCE(voice_profile, ''),
 			   status,
-			   COALESCE(visibility, 'private')
-		  FROM meetings`
-	args := []any{}
+			   COALESCE(visibility, 'private'),
+			   COALESCE((SELECT COALESCE(p.external_id, p.id::text) FROM meetings p WHERE p.id = meetings.series_id), '')
+		  FROM meetings
+		 WHERE status <> $1`
+	args := []any{meetingStatusSeries}
 
 	if userID != "" {
-		query += ` WHERE host_user_id::text = $1`
 		args = append(args, userID)
+		query += fmt.Sprintf(` AND host_user_id::text = $%d`, len(args))
+	}
+	if hasWindow {
+		args = append(args, from, to)
+		query += fmt.Sprintf(` AND start_time + duration_minutes * INTERVAL '1 minute' > $%d AND start_time < $%d`, len(args)-1, len(args))
 	}
 
 	query += ` ORDER BY start_time DESC`
@@ -706,13 +873,14 @@ func (s *AppService) ListMeetings(ctx context.Context, userID string) (*core.Mee
 		}
 	}
 
+	quickStart, err := s.quickStartTemplates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	resp := &core.MeetingsResponse{
-		SessionHealth: sessionHealth,
-		QuickStartTemplates: []core.QuickStartTemplate{
-			{ID: "template-retro", Title: "Retro reboot", Description: "Re-energize your retros with AI prompts.", Badge: "Popular"},
-			{ID: "template-product", Title: "Product narrative", Description: "Pressure-test the story before investor updates.", Badge: "AI guided"},
-			{ID: "template-onboarding", Title: "Onboarding accelerator", Description: "Give new hires a personal AI co-pilot.", Badge: "New"},
-		},
+		SessionHealth:       sessionHealth,
+		QuickStartTemplates: quickStart,
 	}
 
 	for rows.Next() {
@@ -726,11 +894,21 @@ func (s *AppService) ListMeetings(ctx context.Context, userID string) (*core.Mee
 			&item.VoiceProfile,
 			&item.Status,
 			&item.Visibility,
+			&item.SeriesID,
 		); err == nil {
 			resp.Scheduled = append(resp.Scheduled, item)
 		}
 	}
 
+	occurrences, err := s.listOccurrences(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(occurrences) > 0 {
+		resp.Scheduled = append(resp.Scheduled, occurrences...)
+		sortMeetingsByStartDesc(resp.Scheduled)
+	}
+
 	return resp, nil
 }
 
@@ -740,7 +918,7 @@ func (s *AppService) GetMeeting(ctx context.Context, slug string) (*core.Meeting
 	}
 
 	var detail core.MeetingDetail
-	var meetingID string
+	var meetingID, rrule, timeZone string
 
 	err := s.db.QueryRow(ctx, `
 		SELECT id::text,
@@ -753,7 +931,10 @@ func (s *AppService) GetMeeting(ctx context.Context, slug string) (*core.Meeting
 			   status,
 			   COALESCE(visibility, ''),
 			   COALESCE(ai_persona_id, 'aurora'),
-			   COALESCE(host_user_id::text, '')
+			   COALESCE(host_user_id::text, ''),
+			   COALESCE((SELECT COALESCE(p.external_id, p.id::text) FROM meetings p WHERE p.id = meetings.series_id), ''),
+			   COALESCE(recurrence_rule, ''),
+			   COALESCE(recurrence_tz, 'UTC')
 		  FROM meetings
 		 WHERE COALESCE(external_id, id::text) = $1
 		 LIMIT 1`, slug).Scan(
@@ -768,18 +949,33 @@ func (s *AppService) GetMeeting(ctx context.Context, slug string) (*core.Meeting
 		&detail.Summary.Visibility,
 		&detail.AiPersona.ID,
 		&detail.Summary.HostUserID,
+		&detail.Summary.SeriesID,
+		&rrule,
+		&timeZone,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("meeting not found")
+			// Occurrences of recurring meetings only exist once they are used
+			if _, _, ok := splitOccurrenceID(slug); ok {
+				return s.getOccurrence(ctx, slug)
+			}
+			return nil, notFound("meeting_not_found", "meeting not found")
 		}
 		return nil, err
 	}
 
+	if rrule != "" {
+		detail.Recurrence = &core.MeetingRecurrence{RRule: rrule, TimeZone: timeZone}
+		if series, err := loadSeries(ctx, s.db, slug); err == nil {
+			detail.Recurrence.Exceptions = series.Exceptions
+		}
+	}
+
 	if err := s.db.QueryRow(ctx, `
-		SELECT name, voice_preset, tone, energy, COALESCE(description, '')
-		  FROM ai_personas
-		 WHERE id = $1`, detail.AiPersona.ID).Scan(
+		SELECT p.name, COALESCE(vp.voice_id, p.voice_preset), p.tone, p.energy, COALESCE(p.description, '')
+		  FROM ai_personas p
+		  LEFT JOIN voice_presets vp ON vp.id = p.voice_preset_id
+		 WHERE p.id = $1`, detail.AiPersona.ID).Scan(
 		&detail.AiPersona.Name,
 		&detail.AiPersona.VoicePreset,
 		&detail.AiPersona.Tone,
@@ -796,19 +992,8 @@ func (s *AppService) GetMeeting(ctx context.Context, slug string) (*core.Meeting
 		}
 	}
 
-	agendaRows, err := s.db.Query(ctx, `
-		SELECT id::text, title, COALESCE(description, ''), duration_minutes
-		  FROM meeting_agenda_items
-		 WHERE meeting_id::text = $1
-		 ORDER BY order_index`, meetingID)
-	if err == nil {
-		defer agendaRows.Close()
-		for agendaRows.Next() {
-			var item core.AgendaItem
-			if err := agendaRows.Scan(&item.ID, &item.Title, &item.Description, &item.Duration); err == nil {
-				detail.Agenda = append(detail.Agenda, item)
-			}
-		}
+	if agenda, err := loadMeetingAgenda(ctx, s.db, meetingID); err == nil {
+		detail.Agenda = agenda
 	}
 
 	participantRows, err := s.db.Query(ctx, `
@@ -826,11 +1011,12 @@ func (s *AppService) GetMeeting(ctx context.Context, slug string) (*core.Meeting
 		}
 	}
 
-	detail.Resources = []core.ResourceLink{
-		{Title: "Team scorecard", URL: "https://example.com/scorecard", Type: "notion"},
-		{Title: "Quarterly goals", URL: "https://example.com/goals", Type: "google-doc"},
+	if resources, err := loadMeetingResources(ctx, s.db, meetingID); err == nil {
+		detail.Resources = resources
+	}
+	if notes, err := loadMeetingNotes(ctx, s.db, meetingID); err == nil {
+		detail.Notes = notes.Content
 	}
-	detail.Notes = "Aurora will prompt on blockers around retention experiments."
 
 	return detailClone(detail), nil
 }
@@ -839,7 +1025,7 @@ func detailClone(detail core.MeetingDetail) *core.MeetingDetail {
 	copyDetail := detail
 	copyDetail.Agenda = append([]core.AgendaItem(nil), detail.Agenda...)
 	copyDetail.Participants = append([]core.MeetingParticipant(nil), detail.Participants...)
-	copyDetail.Resources = append([]core.ResourceLink(nil), detail.Resources...)
+	copyDetail.Resources = append([]core.ResourceLink{}, detail.Resources...)
 	return &copyDetail
 }
 
@@ -913,7 +1099,7 @@ func (s *AppService) GetTranscript(ctx context.Context, transcriptID string) (*c
 		&hostUserID,
 	); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("transcript not found")
+			return nil, notFound("transcript_not_found", "transcript not found")
 		}
 		return nil, err
 	}
@@ -967,22 +1153,22 @@ func (s *AppService) CheckTranscriptOwnership(ctx context.Context, transcriptID,
 	userID = strings.TrimSpace(userID)
 
 	if transcriptID == "" {
-		return fmt.Errorf("transcript ID is required")
+		return InvalidField("transcriptId", "transcript ID is required")
 	}
 	if userID == "" {
-		return fmt.Errorf("user ID is required")
+		return InvalidField("userId", "user ID is required")
 	}
 
-	var hostUserID string
+	var meetingID, hostUserID string
 	if err := s.db.QueryRow(ctx, `
-		SELECT COALESCE(m.host_user_id::text, '')
+		SELECT COALESCE(m.id::text, ''), COALESCE(m.host_user_id::text, '')
 		  FROM transcripts t
 		  JOIN sessions s ON s.id = t.session_id
 		  LEFT JOIN meetings m ON m.id = s.meeting_id
 		 WHERE t.id::text = $1
-		 LIMIT 1`, transcriptID).Scan(&hostUserID); err != nil {
+		 LIMIT 1`, transcriptID).Scan(&meetingID, &hostUserID); err != nil {
 		if err == pgx.ErrNoRows {
-			return fmt.Errorf("transcript not found")
+			return notFound("transcript_not_found", "transcript not found")
 		}
 		return err
 	}
@@ -992,8 +1178,13 @@ func (s *AppService) CheckTranscriptOwnership(ctx context.Context, transcriptID,
 		return nil
 	}
 
-	if hostUserID != userID {
-		return fmt.Errorf("unauthorized: user does not own this transcript")
+	// Participants see the transcript when their role allows it
+	role, err := meetingRole(ctx, s.db, meetingID, userID)
+	if err != nil {
+		return err
+	}
+	if !RoleAllows(role, PermissionViewTranscript) {
+		return forbidden("transcript_forbidden", "user does not own this transcript")
 	}
 
 	return nil
@@ -1025,15 +1216,23 @@ func (s *AppService) GetSettings(ctx context.Context, userID string) (*core.Sett
 		return nil, err
 	}
 
+	personaID, err := resolvePersonaID(ctx, s.db, userID, "")
+	if err != nil {
+		return nil, err
+	}
+	var customPersona bool
 	_ = s.db.QueryRow(ctx, `
-		SELECT id, name, voice_preset, tone, energy, COALESCE(description, '')
-		  FROM ai_personas WHERE id = 'aurora'`).Scan(
+		SELECT p.id, p.name, COALESCE(vp.voice_id, p.voice_preset), p.tone, p.energy, COALESCE(p.description, ''), p.owner_user_id IS NOT NULL
+		  FROM ai_personas p
+		  LEFT JOIN voice_presets vp ON vp.id = p.voice_preset_id
+		 WHERE p.id = $1`, personaID).Scan(
 		&resp.Personality.PersonaID,
 		&resp.Personality.PersonaName,
 		&resp.Personality.VoicePreset,
 		&resp.Personality.Tone,
 		&resp.Personality.Energy,
 		&resp.Personality.Summary,
+		&customPersona,
 	)
 
 	if strings.TrimSpace(resp.Personality.Tone) == "" {
@@ -1047,6 +1246,7 @@ func (s *AppService) GetSettings(ctx context.Context, userID string) (*core.Sett
 	resp.Privacy.DataRetentionDays = 30
 	resp.Privacy.AllowModelTraining = false
 
+	var defaultTone, defaultEnergy string
 	if err := s.db.QueryRow(ctx, `
 		SELECT COALESCE(default_tone, 'Warm'),
 			   COALESCE(default_energy, 'Balanced'),
@@ -1055,13 +1255,18 @@ func (s *AppService) GetSettings(ctx context.Context, userID string) (*core.Sett
 			   COALESCE(allow_model_training, FALSE)
 		  FROM user_preferences
 		 WHERE user_id::text = $1`, userID).Scan(
-		&resp.Personality.Tone,
-		&resp.Personality.Energy,
+		&defaultTone,
+		&defaultEnergy,
 		&resp.Privacy.RecordingEnabled,
 		&resp.Privacy.DataRetentionDays,
 		&resp.Privacy.AllowModelTraining,
 	); err != nil {
 		// keep defaults if preferences row missing
+	} else if !customPersona {
+		// Tone and energy preferences tune the built-in persona; custom
+		// personas carry their own
+		resp.Personality.Tone = defaultTone
+		resp.Personality.Energy = defaultEnergy
 	}
 
 	return resp, nil
@@ -1121,6 +1326,12 @@ func (s *AppService) ListVoicePresets(ctx context.Context, userID string) (*core
 // Write-side methods will be implemented in the next steps.
 
 func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRequest, userID string) (*core.MeetingDetail, error) {
+	return s.createMeeting(ctx, req, userID, "")
+}
+
+// createMeeting creates a meeting; icalUID is set for meetings imported from
+// a calendar so that re-imports can find them
+func (s *AppService) createMeeting(ctx context.Context, req core.MeetingCreateRequest, userID, icalUID string) (*core.MeetingDetail, error) {
 	if err := s.ensureDB(); err != nil {
 		return nil, err
 	}
@@ -1128,26 +1339,55 @@ func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRe
 	userID = strings.TrimSpace(userID)
 
 	if strings.TrimSpace(req.Title) == "" {
-		return nil, fmt.Errorf("title is required")
+		return nil, InvalidField("title", "title is required")
 	}
 	if req.StartTime.IsZero() {
-		return nil, fmt.Errorf("startTime is required")
+		return nil, InvalidField("startTime", "startTime is required")
 	}
 	if req.DurationMinutes <= 0 {
 		req.DurationMinutes = 30
 	}
+	notes := strings.TrimSpace(req.Notes)
+	if utf8.RuneCountInString(notes) > maxMeetingNotesLength {
+		return nil, InvalidField("notes", fmt.Sprintf("notes must be at most %d characters", maxMeetingNotesLength))
+	}
+
+	var (
+		rrule    *string
+		timeZone *string
+	)
+	if req.Recurrence != nil {
+		if req.IsInstant {
+			return nil, InvalidField("recurrence", "instant meetings cannot recur")
+		}
+		rule, loc, err := parseRecurrence(req.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		// Occurrence identifiers have second precision
+		req.StartTime = req.StartTime.Truncate(time.Second)
+		if _, ok := rule.First(req.StartTime.In(loc)); !ok {
+			return nil, InvalidField("recurrence.rrule", "rule has no occurrences after startTime")
+		}
+		ruleValue, zoneName := rule.String(), loc.String()
+		rrule, timeZone = &ruleValue, &zoneName
+	}
+
+	visibility, err := normalizeVisibility(req.Visibility)
+	if err != nil {
+		return nil, err
+	}
 
 	if userID == "" {
-		var err error
 		userID, err = s.firstUserID(ctx)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	personaID := req.AiPersonaID
-	if strings.TrimSpace(personaID) == "" {
-		personaID = "aurora"
+	personaID, err := resolvePersonaID(ctx, s.db, userID, req.AiPersonaID)
+	if err != nil {
+		return nil, err
 	}
 
 	externalID := generateMeetingExternalID(req.Title)
@@ -1163,14 +1403,14 @@ func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRe
 	if req.IsInstant {
 		status = "instant"
 	}
-
-	// Default visibility to private for now; can be extended to accept from request
-	visibility := "private"
+	if rrule != nil {
+		status = meetingStatusSeries
+	}
 
 	var meetingID, slug string
 	if err := tx.QueryRow(ctx, `
-		INSERT INTO meetings (external_id, title, description, host_user_id, ai_persona_id, start_time, duration_minutes, voice_profile, status, visibility)
-		VALUES ($1, $2, $3, $4::uuid, $5, $6, $7, $8, $9, $10)
+		INSERT INTO meetings (external_id, title, description, host_user_id, ai_persona_id, start_time, duration_minutes, voice_profile, status, visibility, recurrence_rule, recurrence_tz, ical_uid, lobby_enabled)
+		VALUES ($1, $2, $3, $4::uuid, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14)
 		RETURNING id::text, COALESCE(external_id, id::text)`,
 		externalID,
 		strings.TrimSpace(req.Title),
@@ -1182,10 +1422,20 @@ func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRe
 		strings.TrimSpace(req.VoiceProfile),
 		status,
 		visibility,
+		rrule,
+		timeZone,
+		icalUID,
+		req.LobbyEnabled,
 	).Scan(&meetingID, &slug); err != nil {
 		return nil, err
 	}
 
+	if req.Recurrence != nil {
+		if err := replaceRecurrenceExceptions(ctx, tx, meetingID, req.Recurrence.Exceptions); err != nil {
+			return nil, err
+		}
+	}
+
 	for idx, item := range req.Agenda {
 		if strings.TrimSpace(item.Title) == "" {
 			continue
@@ -1207,6 +1457,15 @@ func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRe
 		}
 	}
 
+	if err := insertMeetingResources(ctx, tx, meetingID, req.Resources); err != nil {
+		return nil, err
+	}
+	if notes != "" {
+		if err := setMeetingNotes(ctx, tx, meetingID, userID, notes); err != nil {
+			return nil, err
+		}
+	}
+
 	var hostName, hostAvatar string
 	if err := tx.QueryRow(ctx, `
 		SELECT name, COALESCE(avatar_url, '')
@@ -1220,7 +1479,7 @@ func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRe
 			meetingID,
 			userID,
 			hostName,
-			"Host",
+			RoleHost,
 			hostAvatar,
 		); err != nil {
 			return nil, err
@@ -1234,12 +1493,66 @@ func (s *AppService) CreateMeeting(ctx context.Context, req core.MeetingCreateRe
 	return s.GetMeeting(ctx, slug)
 }
 
+// UpdateMeeting edits a meeting. For occurrences of a recurring meeting,
+// req.Scope selects whether only that occurrence, it and all following ones, or
+// the whole series changes; the default is the occurrence alone.
 func (s *AppService) UpdateMeeting(ctx context.Context, identifier string, req core.MeetingUpdateRequest) (*core.MeetingDetail, error) {
 	if err := s.ensureDB(); err != nil {
 		return nil, err
 	}
 	if strings.TrimSpace(identifier) == "" {
-		return nil, fmt.Errorf("meeting identifier is required")
+		return nil, InvalidField("meetingId", "meeting identifier is required")
+	}
+
+	scope := strings.TrimSpace(req.Scope)
+	switch scope {
+	case "", UpdateScopeOccurrence, UpdateScopeFollowing, UpdateScopeSeries:
+	default:
+		return nil, InvalidField("scope", "scope must be occurrence, following or series")
+	}
+
+	if req.Visibility != nil {
+		visibility, err := normalizeVisibility(*req.Visibility)
+		if err != nil {
+			return nil, err
+		}
+		req.Visibility = &visibility
+	}
+
+	if req.AiPersonaID != nil && strings.TrimSpace(*req.AiPersonaID) != "" {
+		// The persona must be one the host can use
+		hostSlug := identifier
+		if seriesSlug, _, ok := splitOccurrenceID(identifier); ok {
+			hostSlug = seriesSlug
+		}
+		var hostUserID string
+		if err := s.db.QueryRow(ctx, `
+			SELECT COALESCE(host_user_id::text, '')
+			  FROM meetings
+			 WHERE COALESCE(external_id, id::text) = $1`, hostSlug).Scan(&hostUserID); err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, notFound("meeting_not_found", "meeting not found")
+			}
+			return nil, err
+		}
+		personaID, err := resolvePersonaID(ctx, s.db, hostUserID, *req.AiPersonaID)
+		if err != nil {
+			return nil, err
+		}
+		req.AiPersonaID = &personaID
+	}
+
+	if seriesSlug, start, ok := splitOccurrenceID(identifier); ok {
+		switch scope {
+		case UpdateScopeSeries:
+			identifier = seriesSlug
+		case UpdateScopeFollowing:
+			return s.updateFollowingOccurrences(ctx, seriesSlug, start, req)
+		default:
+			if req.Recurrence != nil {
+				return nil, InvalidField("recurrence", "recurrence can only be changed for the series or this and following occurrences")
+			}
+		}
 	}
 
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
@@ -1248,20 +1561,50 @@ func (s *AppService) UpdateMeeting(ctx context.Context, identifier string, req c
 	}
 	defer tx.Rollback(ctx)
 
-	var meetingID, slug string
+	slug, err := s.updateMeetingTx(ctx, tx, identifier, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetMeeting(ctx, slug)
+}
+
+// updateMeetingTx updates a single meeting, occurrence or series template and
+// returns its slug
+func (s *AppService) updateMeetingTx(ctx context.Context, tx pgx.Tx, identifier string, req core.MeetingUpdateRequest) (string, error) {
+	if err := s.materializeOccurrence(ctx, tx, identifier); err != nil {
+		return "", err
+	}
+
+	var meetingID, slug, status string
 	if err := tx.QueryRow(ctx, `
-		SELECT id::text, COALESCE(external_id, id::text)
+		SELECT id::text, COALESCE(external_id, id::text), status
 		  FROM meetings
 		 WHERE COALESCE(external_id, id::text) = $1
 		 FOR UPDATE`,
 		identifier,
-	).Scan(&meetingID, &slug); err != nil {
+	).Scan(&meetingID, &slug, &status); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("meeting not found")
+			return "", notFound("meeting_not_found", "meeting not found")
 		}
-		return nil, err
+		return "", err
 	}
 
+	if status == meetingStatusSeries {
+		return slug, s.updateSeries(ctx, tx, meetingID, req)
+	}
+	if req.Recurrence != nil {
+		return "", InvalidField("recurrence", "an existing meeting cannot be made recurring")
+	}
+	return slug, applyMeetingUpdate(ctx, tx, meetingID, req)
+}
+
+// applyMeetingUpdate writes the fields set in req to one meeting row
+func applyMeetingUpdate(ctx context.Context, tx pgx.Tx, meetingID string, req core.MeetingUpdateRequest) error {
 	setClauses := make([]string, 0, 6)
 	args := make([]any, 0, 6)
 
@@ -1274,7 +1617,7 @@ func (s *AppService) UpdateMeeting(ctx context.Context, identifier string, req c
 		args = append(args, strings.TrimSpace(*req.Description))
 	}
 	if req.StartTime != nil && !req.StartTime.IsZero() {
-		setClauses = append(setClauses, fmt.Sprintf("start_time = $%d", len(args)+1))
+		setClauses = append(setClauses, fmt.Sprintf("start_time = $%d", len(args)+1), "reminder_sent_at = NULL")
 		args = append(args, *req.StartTime)
 	}
 	if req.DurationMinutes != nil && *req.DurationMinutes > 0 {
@@ -1289,23 +1632,33 @@ func (s *AppService) UpdateMeeting(ctx context.Context, identifier string, req c
 		setClauses = append(setClauses, fmt.Sprintf("ai_persona_id = $%d", len(args)+1))
 		args = append(args, strings.TrimSpace(*req.AiPersonaID))
 	}
+	if req.Visibility != nil {
+		setClauses = append(setClauses, fmt.Sprintf("visibility = $%d", len(args)+1))
+		args = append(args, *req.Visibility)
+		if *req.Visibility == MeetingVisibilityPrivate {
+			// Guest links only work for public meetings
+			if _, err := tx.Exec(ctx, `DELETE FROM meeting_guest_links WHERE meeting_id::text = $1`, meetingID); err != nil {
+				return err
+			}
+		}
+	}
 
 	if len(setClauses) > 0 {
-		setClauses = append(setClauses, "updated_at = NOW()")
+		setClauses = append(setClauses, "updated_at = NOW()", "ics_sequence = ics_sequence + 1")
 		args = append(args, meetingID)
 		query := fmt.Sprintf("UPDATE meetings SET %s WHERE id::text = $%d", strings.Join(setClauses, ", "), len(args))
 		if _, err := tx.Exec(ctx, query, args...); err != nil {
-			return nil, err
+			return err
 		}
 	} else if req.Agenda != nil {
-		if _, err := tx.Exec(ctx, `UPDATE meetings SET updated_at = NOW() WHERE id::text = $1`, meetingID); err != nil {
-			return nil, err
+		if _, err := tx.Exec(ctx, `UPDATE meetings SET updated_at = NOW(), ics_sequence = ics_sequence + 1 WHERE id::text = $1`, meetingID); err != nil {
+			return err
 		}
 	}
 
 	if req.Agenda != nil {
 		if _, err := tx.Exec(ctx, `DELETE FROM meeting_agenda_items WHERE meeting_id::text = $1`, meetingID); err != nil {
-			return nil, err
+			return err
 		}
 		for idx, item := range req.Agenda {
 			if strings.TrimSpace(item.Title) == "" {
@@ -1324,16 +1677,12 @@ func (s *AppService) UpdateMeeting(ctx context.Context, identifier string, req c
 				strings.TrimSpace(item.Description),
 				duration,
 			); err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, err
-	}
-
-	return s.GetMeeting(ctx, slug)
+	return nil
 }
 
 func (s *AppService) DeleteMeeting(ctx context.Context, identifier string) error {
@@ -1341,38 +1690,58 @@ func (s *AppService) DeleteMeeting(ctx context.Context, identifier string) error
 		return err
 	}
 	if strings.TrimSpace(identifier) == "" {
-		return fmt.Errorf("meeting identifier is required")
+		return InvalidField("meetingId", "meeting identifier is required")
 	}
 
-	result, err := s.db.Exec(ctx, `
-		DELETE FROM meetings
-		 WHERE COALESCE(external_id, id::text) = $1`, identifier)
-	if err != nil {
-		return err
+	if _, _, ok := splitOccurrenceID(identifier); ok {
+		return s.cancelOccurrence(ctx, identifier)
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("meeting not found")
-	}
-	return nil
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		// Keep a tombstone so subscribed calendars see the cancellation
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO meeting_cancellations (meeting_slug, host_user_id, invitee_user_ids, invitee_emails, title, start_time, duration_minutes, ics_sequence)
+			SELECT COALESCE(m.external_id, m.id::text),
+			       m.host_user_id,
+			       COALESCE(ARRAY(SELECT i.invitee_user_id FROM meeting_invites i WHERE i.meeting_id = m.id AND i.invitee_user_id IS NOT NULL), '{}'),
+			       COALESCE(ARRAY(SELECT LOWER(i.email) FROM meeting_invites i WHERE i.meeting_id = m.id AND i.email <> ''), '{}'),
+			       m.title,
+			       m.start_time,
+			       m.duration_minutes,
+			       m.ics_sequence + 1
+			  FROM meetings m
+			 WHERE COALESCE(m.external_id, m.id::text) = $1
+			ON CONFLICT (meeting_slug) DO NOTHING`, identifier); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx, `
+			DELETE FROM meetings
+			 WHERE COALESCE(external_id, id::text) = $1`, identifier)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return notFound("meeting_not_found", "meeting not found")
+		}
+		return nil
+	})
 }
 
-// StartMeeting transitions a meeting into an active state. Only the host can start.
+// StartMeeting transitions a meeting into an active state. Only the host and
+// co-hosts can start.
 func (s *AppService) StartMeeting(ctx context.Context, identifier string, userID string) (*core.MeetingDetail, error) {
 	if err := s.ensureDB(); err != nil {
 		return nil, err
 	}
 	identifier = strings.TrimSpace(identifier)
 	if identifier == "" {
-		return nil, fmt.Errorf("meeting identifier is required")
+		return nil, InvalidField("meetingId", "meeting identifier is required")
 	}
 
 	userID = strings.TrimSpace(userID)
 	if userID == "" {
-		var err error
-		userID, err = s.firstUserID(ctx)
-		if err != nil {
-			return nil, err
-		}
+		return nil, unauthorized("session_invalid", "authentication required")
 	}
 
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
@@ -1381,6 +1750,11 @@ func (s *AppService) StartMeeting(ctx context.Context, identifier string, userID
 	}
 	defer tx.Rollback(ctx)
 
+	// Occurrences of recurring meetings get their own row on first start
+	if err := s.materializeOccurrence(ctx, tx, identifier); err != nil {
+		return nil, err
+	}
+
 	var (
 		meetingID   string
 		slug        string
@@ -1403,17 +1777,24 @@ func (s *AppService) StartMeeting(ctx context.Context, identifier string, userID
 		identifier,
 	).Scan(&meetingID, &slug, &hostUserID, &status, &actualStart, &startTime); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("meeting not found")
+			return nil, notFound("meeting_not_found", "meeting not found")
 		}
 		return nil, err
 	}
 
-	if strings.TrimSpace(hostUserID) != "" && userID != hostUserID {
-		return nil, fmt.Errorf("unauthorized to start meeting")
+	role, err := meetingRole(ctx, tx, meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAllows(role, PermissionStart) {
+		return nil, forbidden("meeting_forbidden", "your role in this meeting does not allow starting it")
 	}
 
-	if status == "ended" {
-		return nil, fmt.Errorf("meeting already ended")
+	if err := closedMeetingError(status); err != nil {
+		return nil, err
+	}
+	if status == meetingStatusSeries {
+		return nil, conflict("meeting_is_series", "start an occurrence of this recurring meeting instead")
 	}
 
 	// If already active, just return details
@@ -1432,10 +1813,108 @@ func (s *AppService) StartMeeting(ctx context.Context, identifier string, userID
 	); err != nil {
 		return nil, err
 	}
+	if err := recordMeetingEvent(ctx, tx, meetingID, MeetingEventStarted, core.MeetingStatusEvent{Status: "active", UserID: userID}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	s.notifyMeetingEvents(meetingID)
+	s.notifyAsync(ctx, func(ctx context.Context) {
+		s.notifyMeetingStarted(ctx, meetingID, userID)
+	})
+
+	metrics.MeetingsStarted.Inc()
+
+	return s.GetMeeting(ctx, slug)
+}
+
+// EndMeeting ends an active meeting. Only the host and co-hosts can end it.
+func (s *AppService) EndMeeting(ctx context.Context, identifier string, userID string) (*core.MeetingDetail, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return nil, InvalidField("meetingId", "meeting identifier is required")
+	}
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var meetingID, slug, status string
+	if err := tx.QueryRow(ctx, `
+		SELECT id::text,
+		       COALESCE(external_id, id::text),
+		       status
+		  FROM meetings
+		 WHERE COALESCE(external_id, id::text) = $1
+		 FOR UPDATE`,
+		identifier,
+	).Scan(&meetingID, &slug, &status); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("meeting_not_found", "meeting not found")
+		}
+		return nil, err
+	}
+
+	role, err := meetingRole(ctx, tx, meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAllows(role, PermissionEnd) {
+		return nil, forbidden("meeting_forbidden", "your role in this meeting does not allow ending it")
+	}
+
+	switch status {
+	case "ended":
+		return s.GetMeeting(ctx, slug)
+	case "active", "instant":
+	default:
+		return nil, conflict("meeting_not_active", "only a running meeting can be ended")
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE meetings
+		   SET status = 'ended',
+		       ended_at = NOW(),
+		       updated_at = NOW()
+		 WHERE id = $1::uuid`,
+		meetingID,
+	); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE meeting_participants
+		   SET left_at = NOW()
+		 WHERE meeting_id = $1::uuid
+		   AND joined_at IS NOT NULL
+		   AND left_at IS NULL`,
+		meetingID,
+	); err != nil {
+		return nil, err
+	}
+	if err := recordMeetingEvent(ctx, tx, meetingID, MeetingEventEnded, core.MeetingStatusEvent{Status: "ended", UserID: userID}); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	s.notifyMeetingEvents(meetingID)
+	s.notifyAsync(ctx, func(ctx context.Context) {
+		s.notifyFeedbackRequested(ctx, []string{meetingID})
+	})
+
+	metrics.MeetingsEnded.Inc()
 
 	return s.GetMeeting(ctx, slug)
 }
@@ -1445,10 +1924,25 @@ func (s *AppService) JoinMeeting(ctx context.Context, identifier string, userID
 		return nil, err
 	}
 	if strings.TrimSpace(identifier) == "" {
-		return nil, fmt.Errorf("meeting identifier is required")
+		return nil, InvalidField("meetingId", "meeting identifier is required")
 	}
 
+	// Anonymous callers join public meetings through guest links instead
 	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, unauthorized("session_invalid", "authentication required")
+	}
+
+	// Occurrences are stored on first join, but only for users who may join
+	// the series
+	if err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		if err := checkOccurrenceAccess(ctx, tx, identifier, userID); err != nil {
+			return err
+		}
+		return s.materializeOccurrence(ctx, tx, identifier)
+	}); err != nil {
+		return nil, err
+	}
 
 	var (
 		meetingID  string
@@ -1458,6 +1952,9 @@ func (s *AppService) JoinMeeting(ctx context.Context, identifier string, userID
 		status     string
 		startTime  time.Time
 		visibility string
+		seriesID   string
+		locked     bool
+		lobby      bool
 	)
 
 	if err := s.db.QueryRow(ctx, `
@@ -1467,79 +1964,92 @@ func (s *AppService) JoinMeeting(ctx context.Context, identifier string, userID
 		       COALESCE(host_user_id::text, ''),
 		       status,
 		       start_time,
-		       COALESCE(visibility, 'private')
+		       COALESCE(visibility, 'private'),
+		       COALESCE(series_id::text, ''),
+		       locked,
+		       lobby_enabled
 		  FROM meetings
 		 WHERE COALESCE(external_id, id::text) = $1
 		 LIMIT 1`, identifier,
-	).Scan(&meetingID, &slug, &personaID, &hostUserID, &status, &startTime, &visibility); err != nil {
+	).Scan(&meetingID, &slug, &personaID, &hostUserID, &status, &startTime, &visibility, &seriesID, &locked, &lobby); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("meeting not found")
+			return nil, notFound("meeting_not_found", "meeting not found")
 		}
 		return nil, err
 	}
 
-	if userID == "" {
+	if status == meetingStatusSeries {
+		return nil, conflict("meeting_is_series", "join an occurrence of this recurring meeting instead")
+	}
+
+	// Meetings without a host predate roles; everyone joins them as a
+	// participant
+	role := ""
+	if strings.TrimSpace(hostUserID) != "" {
 		var err error
-		userID, err = s.firstUserID(ctx)
-		if err != nil {
+		if role, err = meetingRole(ctx, s.db, meetingID, userID); err != nil {
 			return nil, err
 		}
 	}
+	// Hosts and co-hosts let others in, so the lock, the lobby and the start
+	// time do not apply to them
+	privileged := RoleAllows(role, PermissionAdmit)
 
-	isHost := strings.TrimSpace(hostUserID) != "" && userID == hostUserID
-
-	// Basic invite enforcement for private meetings (only host or explicitly invited users)
-	isInvited := false
-	if !isHost && visibility == "private" {
-		// Look up user's email to match both by user_id and email
-		var userEmail string
-		_ = s.db.QueryRow(ctx, `
-			SELECT LOWER(email)
-			  FROM app_users
-			 WHERE id::text = $1
-			 LIMIT 1`,
-			userID,
-		).Scan(&userEmail)
-
-		_ = s.db.QueryRow(ctx, `
-			SELECT EXISTS(
-				SELECT 1
-				  FROM meeting_invites
-				 WHERE meeting_id::text = $1
-				   AND status IN ('pending', 'accepted')
-				   AND (
-					 invitee_user_id::text = $2
-					 OR (email <> '' AND LOWER(email) = $3)
-				   )
-			)`,
-			meetingID, userID, userEmail,
-		).Scan(&isInvited)
-
+	// Basic invite enforcement for private meetings (only participants with a
+	// role or explicitly invited users).
+	// Invites to a recurring meeting cover all of its occurrences.
+	if role == "" && visibility == MeetingVisibilityPrivate {
+		isInvited, err := isMeetingInvitee(ctx, s.db, meetingID, seriesID, userID)
+		if err != nil {
+			return nil, err
+		}
 		if !isInvited {
-			return nil, fmt.Errorf("not invited to this meeting")
+			return nil, forbidden("not_invited", "not invited to this meeting")
 		}
 	}
 
-	now := time.Now()
-
-	if !isHost {
-		// Non-host participants cannot join ended meetings
-		if status == "ended" {
-			return nil, fmt.Errorf("meeting has ended")
+	if !privileged {
+		if locked {
+			return nil, conflict("meeting_locked", "the host has locked this meeting")
 		}
 
-		// Non-hosts can only join when the meeting is active or instant. For scheduled
-		// meetings, the host must explicitly start the meeting.
-		if status == "scheduled" {
-			return nil, fmt.Errorf("meeting has not started yet")
+		admission, err := participantAdmission(ctx, s.db, meetingID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if admission == AdmissionDenied {
+			return nil, forbidden("lobby_denied", "the host declined your request to join")
+		}
+		// In lobby mode participants wait, even before the meeting starts,
+		// until the host admits them
+		if lobby && admission != AdmissionAdmitted {
+			if err := closedMeetingError(status); err != nil {
+				return nil, err
+			}
+			if err := requestAdmission(ctx, s.db, meetingID, userID); err != nil {
+				return nil, err
+			}
+			s.notifyMeetingEvents(meetingID)
+			return &core.MeetingJoinResponse{
+				MeetingID:     slug,
+				ParticipantID: userID,
+				Status:        JoinStatusWaiting,
+				PollURL:       fmt.Sprintf("%s/api/v1/meetings/%s/lobby/status", strings.TrimRight(s.apiURL, "/"), slug),
+			}, nil
 		}
 
-		if status != "active" && status != "instant" && now.Before(startTime) {
-			return nil, fmt.Errorf("meeting has not started yet")
+		if err := checkJoinable(status, startTime); err != nil {
+			return nil, err
 		}
 	}
 
+	if err := recordParticipantJoin(ctx, s.db, meetingID, userID); err != nil {
+		return nil, err
+	}
+	s.notifyMeetingEvents(meetingID)
+
 	resp := &core.MeetingJoinResponse{
+		Status:          JoinStatusJoined,
 		MeetingID:       slug,
 		ParticipantID:   userID,
 		WebRTCToken:     "",
@@ -1551,9 +2061,31 @@ func (s *AppService) JoinMeeting(ctx context.Context, identifier string, userID
 
 	_ = personaID // reserved for future SFU integrations
 
+	metrics.MeetingsJoined.Inc()
+
 	return resp, nil
 }
 
+// checkJoinable reports whether participants other than the host can enter
+// a meeting in the given state
+func checkJoinable(status string, startTime time.Time) error {
+	// Non-host participants cannot join closed meetings
+	if err := closedMeetingError(status); err != nil {
+		return err
+	}
+
+	// Non-hosts can only join when the meeting is active or instant. For scheduled
+	// meetings, the host must explicitly start the meeting.
+	if status == "scheduled" {
+		return conflict("meeting_not_started", "meeting has not started yet")
+	}
+
+	if status != "active" && status != "instant" && time.Now().Before(startTime) {
+		return conflict("meeting_not_started", "meeting has not started yet")
+	}
+	return nil
+}
+
 func (s *AppService) UpdateSettings(ctx context.Context, userID string, req core.SettingsUpdateRequest) (*core.SettingsResponse, error) {
 	if err := s.ensureDB(); err != nil {
 		return nil, err
@@ -1596,6 +2128,17 @@ func (s *AppService) UpdateSettings(ctx context.Context, userID string, req core
 	}
 
 	if req.Personality != nil {
+		if personaID := strings.TrimSpace(req.Personality.PersonaID); personaID != "" {
+			if _, err := resolvePersonaID(ctx, tx, userID, personaID); err != nil {
+				if errors.Is(err, ErrValidation) {
+					return nil, InvalidField("personality.personaId", "unknown AI persona")
+				}
+				return nil, err
+			}
+			if err := setDefaultPersona(ctx, tx, userID, personaID); err != nil {
+				return nil, err
+			}
+		}
 		if _, err := tx.Exec(ctx, `
 			UPDATE user_preferences
 			   SET default_voice_id = NULLIF($1, ''),
@@ -1650,8 +2193,15 @@ func (s *AppService) CreateVoicePreset(ctx context.Context, userID string, req c
 
 	userID = strings.TrimSpace(userID)
 
-	if strings.TrimSpace(req.Name) == "" || strings.TrimSpace(req.VoiceID) == "" {
-		return nil, fmt.Errorf("name and voiceId are required")
+	var verr ValidationError
+	if strings.TrimSpace(req.Name) == "" {
+		verr.Fields = append(verr.Fields, FieldError{Field: "name", Message: "name is required"})
+	}
+	if strings.TrimSpace(req.VoiceID) == "" {
+		verr.Fields = append(verr.Fields, FieldError{Field: "voiceId", Message: "voiceId is required"})
+	}
+	if len(verr.Fields) > 0 {
+		return nil, &verr
 	}
 
 	if userID == "" {
@@ -1731,7 +2281,7 @@ func (s *AppService) UpdateVoicePreset(ctx context.Context, userID string, prese
 	presetID = strings.TrimSpace(presetID)
 
 	if presetID == "" {
-		return nil, fmt.Errorf("preset identifier is required")
+		return nil, InvalidField("presetId", "preset identifier is required")
 	}
 
 	if userID == "" {
@@ -1767,7 +2317,7 @@ func (s *AppService) UpdateVoicePreset(ctx context.Context, userID string, prese
 		&existing.IsDefault,
 	); err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("voice preset not found")
+			return nil, notFound("voice_preset_not_found", "voice preset not found")
 		}
 		return nil, err
 	}
@@ -1777,7 +2327,7 @@ func (s *AppService) UpdateVoicePreset(ctx context.Context, userID string, prese
 	if req.Name != nil {
 		val := strings.TrimSpace(*req.Name)
 		if val == "" {
-			return nil, fmt.Errorf("name cannot be empty")
+			return nil, InvalidField("name", "name cannot be empty")
 		}
 		updated.Name = val
 	}
@@ -1785,7 +2335,7 @@ func (s *AppService) UpdateVoicePreset(ctx context.Context, userID string, prese
 	if req.VoiceID != nil {
 		val := strings.TrimSpace(*req.VoiceID)
 		if val == "" {
-			return nil, fmt.Errorf("voiceId cannot be empty")
+			return nil, InvalidField("voiceId", "voiceId cannot be empty")
 		}
 		updated.VoiceID = val
 	}
@@ -1859,7 +2409,7 @@ func (s *AppService) DeleteVoicePreset(ctx context.Context, userID string, prese
 	presetID = strings.TrimSpace(presetID)
 
 	if presetID == "" {
-		return fmt.Errorf("preset identifier is required")
+		return InvalidField("presetId", "preset identifier is required")
 	}
 
 	if userID == "" {
@@ -1886,7 +2436,7 @@ func (s *AppService) DeleteVoicePreset(ctx context.Context, userID string, prese
 		presetID, userID,
 	).Scan(&wasDefault); err != nil {
 		if err == pgx.ErrNoRows {
-			return fmt.Errorf("voice preset not found")
+			return notFound("voice_preset_not_found", "voice preset not found")
 		}
 		return err
 	}
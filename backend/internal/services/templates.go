@@ -0,0 +1,546 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aicomp/ai-virtual-chat/backend/internal/core"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	templateVisibilityPrivate = "private"
+	// templateVisibilityAllUsers shares a template with every account; there
+	// is no workspace or team scope to narrow it to
+	templateVisibilityAllUsers = "all_users"
+
+	maxTemplateTitleLength       = 120
+	maxTemplateDescriptionLength = 1000
+
+	// maxQuickStartTemplates caps the templates shown on the meetings page
+	maxQuickStartTemplates = 6
+)
+
+// templateSelect reads templates visible to the user in $1: built-in ones,
+// the user's own and those shared with all users
+const templateSelect = `
+	SELECT t.id,
+	       t.title,
+	       COALESCE(t.description, ''),
+	       COALESCE(t.badge, CASE WHEN t.owner_user_id::text = $1 THEN 'Yours' ELSE 'Shared' END),
+	       t.duration_minutes,
+	       COALESCE(t.ai_persona_id, ''),
+	       COALESCE(t.voice_profile, ''),
+	       t.visibility,
+	       COALESCE(t.owner_user_id::text, ''),
+	       t.owner_user_id::text IS NOT DISTINCT FROM $1
+	  FROM meeting_templates t
+	 WHERE (t.owner_user_id IS NULL OR t.owner_user_id::text = $1 OR t.visibility = 'all_users')`
+
+func scanTemplate(row pgx.Row) (*core.MeetingTemplate, error) {
+	var t core.MeetingTemplate
+	if err := row.Scan(
+		&t.ID,
+		&t.Title,
+		&t.Description,
+		&t.Badge,
+		&t.DurationMinutes,
+		&t.AiPersonaID,
+		&t.VoiceProfile,
+		&t.Visibility,
+		&t.OwnerUserID,
+		&t.Owned,
+	); err != nil {
+		return nil, err
+	}
+	t.Agenda = []core.AgendaItem{}
+	return &t, nil
+}
+
+// ListMeetingTemplates returns the templates the user can start meetings from
+func (s *AppService) ListMeetingTemplates(ctx context.Context, userID string) (*core.MeetingTemplatesResponse, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := s.listTemplates(ctx, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range templates {
+		if templates[i].Agenda, err = loadTemplateAgenda(ctx, s.db, templates[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return &core.MeetingTemplatesResponse{Templates: templates}, nil
+}
+
+// listTemplates lists visible templates, the user's own first and built-in
+// ones last. A limit of zero lists all of them.
+func (s *AppService) listTemplates(ctx context.Context, userID string, limit int) ([]core.MeetingTemplate, error) {
+	query := templateSelect + `
+		 ORDER BY t.owner_user_id IS NULL, t.owner_user_id::text IS DISTINCT FROM $1, t.updated_at DESC, t.title`
+	args := []any{userID}
+	if limit > 0 {
+		args = append(args, limit)
+		query += ` LIMIT $2`
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []core.MeetingTemplate{}
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, rows.Err()
+}
+
+// quickStartTemplates summarises templates for the meetings page. Without a
+// user only built-in and shared templates are listed.
+func (s *AppService) quickStartTemplates(ctx context.Context, userID string) ([]core.QuickStartTemplate, error) {
+	templates, err := s.listTemplates(ctx, userID, maxQuickStartTemplates)
+	if err != nil {
+		return nil, err
+	}
+	quickStart := make([]core.QuickStartTemplate, 0, len(templates))
+	for _, t := range templates {
+		quickStart = append(quickStart, core.QuickStartTemplate{
+			ID:              t.ID,
+			Title:           t.Title,
+			Description:     t.Description,
+			Badge:           t.Badge,
+			DurationMinutes: t.DurationMinutes,
+		})
+	}
+	return quickStart, nil
+}
+
+// GetMeetingTemplate returns one template visible to the user
+func (s *AppService) GetMeetingTemplate(ctx context.Context, userID, templateID string) (*core.MeetingTemplate, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return getTemplate(ctx, s.db, userID, templateID)
+}
+
+func getTemplate(ctx context.Context, q querier, userID, templateID string) (*core.MeetingTemplate, error) {
+	templateID = strings.TrimSpace(templateID)
+	template, err := scanTemplate(q.QueryRow(ctx, templateSelect+`
+		   AND t.id = $2`, userID, templateID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("template_not_found", "template not found")
+		}
+		return nil, err
+	}
+	if template.Agenda, err = loadTemplateAgenda(ctx, q, template.ID); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func loadTemplateAgenda(ctx context.Context, q querier, templateID string) ([]core.AgendaItem, error) {
+	rows, err := q.Query(ctx, `
+		SELECT id::text,
+		       title,
+		       COALESCE(description, ''),
+		       duration_minutes
+		  FROM meeting_template_agenda_items
+		 WHERE template_id = $1
+		 ORDER BY order_index`, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	agenda := []core.AgendaItem{}
+	for rows.Next() {
+		var item core.AgendaItem
+		if err := rows.Scan(&item.ID, &item.Title, &item.Description, &item.Duration); err != nil {
+			return nil, err
+		}
+		agenda = append(agenda, item)
+	}
+	return agenda, rows.Err()
+}
+
+// CreateMeetingTemplate saves a new template owned by the user
+func (s *AppService) CreateMeetingTemplate(ctx context.Context, userID string, req core.CreateMeetingTemplateRequest) (*core.MeetingTemplate, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := core.MeetingTemplate{Visibility: templateVisibilityPrivate}
+	if meetingID := strings.TrimSpace(req.FromMeetingID); meetingID != "" {
+		if err := s.templateFromMeeting(ctx, userID, meetingID, &template); err != nil {
+			return nil, err
+		}
+	}
+
+	if title := strings.TrimSpace(req.Title); title != "" {
+		template.Title = title
+	}
+	if description := strings.TrimSpace(req.Description); description != "" {
+		template.Description = description
+	}
+	if req.DurationMinutes != 0 {
+		template.DurationMinutes = req.DurationMinutes
+	}
+	if template.DurationMinutes == 0 {
+		template.DurationMinutes = 30
+	}
+	if personaID := strings.TrimSpace(req.AiPersonaID); personaID != "" {
+		template.AiPersonaID = personaID
+	}
+	if voice := strings.TrimSpace(req.VoiceProfile); voice != "" {
+		template.VoiceProfile = voice
+	}
+	if len(req.Agenda) > 0 {
+		template.Agenda = req.Agenda
+	}
+	if visibility := strings.TrimSpace(req.Visibility); visibility != "" {
+		template.Visibility = visibility
+	}
+	if err := validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if template.AiPersonaID != "" {
+		if _, err := resolvePersonaID(ctx, tx, userID, template.AiPersonaID); err != nil {
+			return nil, err
+		}
+	}
+
+	var templateID string
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO meeting_templates (id, owner_user_id, title, description, duration_minutes, ai_persona_id, voice_profile, visibility)
+		VALUES (gen_random_uuid()::text, $1::uuid, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		RETURNING id`,
+		userID,
+		template.Title,
+		template.Description,
+		template.DurationMinutes,
+		template.AiPersonaID,
+		template.VoiceProfile,
+		template.Visibility,
+	).Scan(&templateID); err != nil {
+		return nil, err
+	}
+
+	if err := replaceTemplateAgenda(ctx, tx, templateID, template.Agenda); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetMeetingTemplate(ctx, userID, templateID)
+}
+
+// templateFromMeeting copies a meeting the user can see into template
+func (s *AppService) templateFromMeeting(ctx context.Context, userID, meetingID string, template *core.MeetingTemplate) error {
+	detail, err := s.GetMeeting(ctx, meetingID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return InvalidField("fromMeetingId", "unknown meeting")
+		}
+		return err
+	}
+	if detail.Summary.Visibility == MeetingVisibilityPrivate {
+		role, err := s.MeetingRole(ctx, meetingID, userID)
+		if err != nil {
+			return err
+		}
+		if role == "" {
+			return forbidden("meeting_forbidden", "you do not have access to this meeting")
+		}
+	}
+
+	template.Title = detail.Summary.Title
+	template.Description = detail.Summary.Description
+	template.DurationMinutes = detail.Summary.DurationMinutes
+	template.VoiceProfile = detail.Summary.VoiceProfile
+	template.Agenda = detail.Agenda
+
+	// Another host's custom persona is not available to the user
+	if personaID, err := resolvePersonaID(ctx, s.db, userID, detail.AiPersona.ID); err == nil {
+		template.AiPersonaID = personaID
+	} else if !errors.Is(err, ErrValidation) {
+		return err
+	}
+	return nil
+}
+
+// UpdateMeetingTemplate edits one of the user's own templates
+func (s *AppService) UpdateMeetingTemplate(ctx context.Context, userID, templateID string, req core.UpdateMeetingTemplateRequest) (*core.MeetingTemplate, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	templateID = strings.TrimSpace(templateID)
+	if templateID == "" {
+		return nil, InvalidField("templateId", "template identifier is required")
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	existing, err := ownTemplate(ctx, tx, userID, templateID, "changed")
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *existing
+	if req.Title != nil {
+		updated.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		updated.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.DurationMinutes != nil {
+		updated.DurationMinutes = *req.DurationMinutes
+	}
+	if req.AiPersonaID != nil {
+		updated.AiPersonaID = strings.TrimSpace(*req.AiPersonaID)
+	}
+	if req.VoiceProfile != nil {
+		updated.VoiceProfile = strings.TrimSpace(*req.VoiceProfile)
+	}
+	if req.Agenda != nil {
+		updated.Agenda = *req.Agenda
+	}
+	if req.Visibility != nil {
+		updated.Visibility = strings.TrimSpace(*req.Visibility)
+	}
+	if err := validateTemplate(updated); err != nil {
+		return nil, err
+	}
+	if req.AiPersonaID != nil && updated.AiPersonaID != "" {
+		if _, err := resolvePersonaID(ctx, tx, userID, updated.AiPersonaID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE meeting_templates
+		   SET title = $1,
+		       description = $2,
+		       duration_minutes = $3,
+		       ai_persona_id = NULLIF($4, ''),
+		       voice_profile = $5,
+		       visibility = $6,
+		       updated_at = NOW()
+		 WHERE id = $7
+		   AND owner_user_id::text = $8`,
+		updated.Title,
+		updated.Description,
+		updated.DurationMinutes,
+		updated.AiPersonaID,
+		updated.VoiceProfile,
+		updated.Visibility,
+		templateID,
+		userID,
+	); err != nil {
+		return nil, err
+	}
+
+	if req.Agenda != nil {
+		if err := replaceTemplateAgenda(ctx, tx, templateID, updated.Agenda); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetMeetingTemplate(ctx, userID, templateID)
+}
+
+// DeleteMeetingTemplate removes one of the user's own templates. Meetings
+// created from it are not affected.
+func (s *AppService) DeleteMeetingTemplate(ctx context.Context, userID, templateID string) error {
+	if err := s.ensureDB(); err != nil {
+		return err
+	}
+	templateID = strings.TrimSpace(templateID)
+	if templateID == "" {
+		return InvalidField("templateId", "template identifier is required")
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		if _, err := ownTemplate(ctx, tx, userID, templateID, "deleted"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM meeting_templates WHERE id = $1 AND owner_user_id::text = $2`, templateID, userID)
+		return err
+	})
+}
+
+// ownTemplate locks a template for modification; built-in templates and
+// templates shared by other users are read-only
+func ownTemplate(ctx context.Context, tx pgx.Tx, userID, templateID, action string) (*core.MeetingTemplate, error) {
+	template, err := scanTemplate(tx.QueryRow(ctx, templateSelect+`
+		   AND t.id = $2
+		   FOR UPDATE OF t`, userID, templateID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, notFound("template_not_found", "template not found")
+		}
+		return nil, err
+	}
+	if template.OwnerUserID == "" {
+		return nil, forbidden("template_builtin", "built-in templates cannot be "+action)
+	}
+	if !template.Owned {
+		return nil, forbidden("template_forbidden", "only the owner can modify this template")
+	}
+	if template.Agenda, err = loadTemplateAgenda(ctx, tx, templateID); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func replaceTemplateAgenda(ctx context.Context, tx pgx.Tx, templateID string, agenda []core.AgendaItem) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM meeting_template_agenda_items WHERE template_id = $1`, templateID); err != nil {
+		return err
+	}
+	order := 0
+	for _, item := range agenda {
+		if strings.TrimSpace(item.Title) == "" {
+			continue
+		}
+		order++
+		duration := item.Duration
+		if duration < 0 {
+			duration = 0
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO meeting_template_agenda_items (template_id, order_index, title, description, duration_minutes)
+			VALUES ($1, $2, $3, $4, $5)`,
+			templateID,
+			order,
+			strings.TrimSpace(item.Title),
+			strings.TrimSpace(item.Description),
+			duration,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateMeetingFromTemplate creates a meeting pre-filled from a template.
+// Non-empty fields of overrides take precedence over the template's values.
+func (s *AppService) CreateMeetingFromTemplate(ctx context.Context, userID, templateID string, overrides core.MeetingCreateRequest) (*core.MeetingDetail, error) {
+	if err := s.ensureDB(); err != nil {
+		return nil, err
+	}
+	userID, err := requireUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := getTemplate(ctx, s.db, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := overrides
+	if strings.TrimSpace(req.Title) == "" {
+		req.Title = template.Title
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		req.Description = template.Description
+	}
+	if req.DurationMinutes <= 0 {
+		req.DurationMinutes = template.DurationMinutes
+	}
+	if strings.TrimSpace(req.VoiceProfile) == "" {
+		req.VoiceProfile = template.VoiceProfile
+	}
+	if len(req.Agenda) == 0 {
+		req.Agenda = template.Agenda
+	}
+	if req.StartTime.IsZero() && req.IsInstant {
+		req.StartTime = time.Now().UTC()
+	}
+	if strings.TrimSpace(req.AiPersonaID) == "" && template.AiPersonaID != "" {
+		// A shared template may name its owner's custom persona; other users
+		// get their own default instead
+		if _, err := resolvePersonaID(ctx, s.db, userID, template.AiPersonaID); err == nil {
+			req.AiPersonaID = template.AiPersonaID
+		} else if !errors.Is(err, ErrValidation) {
+			return nil, err
+		}
+	}
+
+	return s.createMeeting(ctx, req, userID, "")
+}
+
+func validateTemplate(t core.MeetingTemplate) error {
+	var verr ValidationError
+	switch n := utf8.RuneCountInString(t.Title); {
+	case n == 0:
+		verr.Fields = append(verr.Fields, FieldError{Field: "title", Message: "title is required"})
+	case n > maxTemplateTitleLength:
+		verr.Fields = append(verr.Fields, FieldError{Field: "title", Message: fmt.Sprintf("title must be at most %d characters", maxTemplateTitleLength)})
+	}
+	if utf8.RuneCountInString(t.Description) > maxTemplateDescriptionLength {
+		verr.Fields = append(verr.Fields, FieldError{Field: "description", Message: fmt.Sprintf("description must be at most %d characters", maxTemplateDescriptionLength)})
+	}
+	if t.DurationMinutes <= 0 {
+		verr.Fields = append(verr.Fields, FieldError{Field: "durationMinutes", Message: "durationMinutes must be positive"})
+	}
+	if len(t.Agenda) > maxAgendaItems {
+		verr.Fields = append(verr.Fields, FieldError{Field: "agenda", Message: fmt.Sprintf("agenda must have at most %d items", maxAgendaItems)})
+	}
+	if t.Visibility != templateVisibilityPrivate && t.Visibility != templateVisibilityAllUsers {
+		verr.Fields = append(verr.Fields, FieldError{Field: "visibility", Message: "visibility must be private or all_users"})
+	}
+	if len(verr.Fields) > 0 {
+		return &verr
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-import axios from 'axios';
+import axios, { type InternalAxiosRequestConfig } from 'axios';
 
 const baseUrl =
   (import.meta.env.VITE_BACKEND_URL as string | undefined)?.replace(
@@ -21,3 +21,65 @@ export const authClient = axios.create({
     'Content-Type': 'application/json',
   },
 });
+
+// CSRF token for state-changing requests on authenticated routes.
+// The token is bound to the session, so it is fetched lazily and dropped
+// whenever the backend rejects it (e.g. after logging in again).
+const CSRF_HEADER = 'X-CSRF-Token';
+const SAFE_METHODS = ['get', 'head', 'options'];
+
+let csrfToken: string | null = null;
+let csrfPromise: Promise<string> | null = null;
+
+async function getCsrfToken(): Promise<string> {
+  if (csrfToken) {
+    return csrfToken;
+  }
+  if (!csrfPromise) {
+    csrfPromise = apiClient
+      .get<{ csrfToken: string }>('/auth/csrf')
+      .then(({ data }) => {
+        csrfToken = data.csrfToken;
+        return csrfToken;
+      })
+      .finally(() => {
+        csrfPromise = null;
+      });
+  }
+  return csrfPromise;
+}
+
+export function clearCsrfToken() {
+  csrfToken = null;
+}
+
+apiClient.interceptors.request.use(async (config) => {
+  const method = (config.method ?? 'get').toLowerCase();
+  if (!SAFE_METHODS.includes(method)) {
+    config.headers.set(CSRF_HEADER, await getCsrfToken());
+  }
+  return config;
+});
+
+apiClient.interceptors.response.use(
+  (response) => response,
+  async (error) => {
+    const { response, config } = error as {
+      response?: { status: number; data?: { code?: string } };
+      config?: InternalAxiosRequestConfig & { _csrfRetried?: boolean };
+    };
+
+    if (
+      response?.status === 403 &&
+      response.data?.code === 'csrf_invalid' &&
+      config &&
+      !config._csrfRetried
+    ) {
+      clearCsrfToken();
+      config._csrfRetried = true;
+      return apiClient(config);
+    }
+
+    return Promise.reject(error);
+  }
+);
@@ -6,7 +6,7 @@ import type {
   AuthRefreshResponse,
   AuthSessionResponse,
 } from '@/types/api';
-import { apiClient, authClient } from '@/lib/api-client';
+import { apiClient, authClient, clearCsrfToken } from '@/lib/api-client';
 
 const STORAGE_KEY = 'neuralive-auth';
 const MIN_PASSWORD_LENGTH = 8;
@@ -69,6 +69,7 @@ const useAuthStoreBase = create<AuthState>()(
       },
 
       clearSession: () => {
+        clearCsrfToken();
         set({ session: null });
       },
 
@@ -185,7 +186,8 @@ export const useAuthStore = useAuthStoreBase;
 let isRefreshing = false;
 let refreshPromise: Promise<AuthSessionResponse> | null = null;
 
-// No request interceptor needed - cookies are sent automatically with withCredentials: true
+// Cookies are sent automatically with withCredentials: true; the CSRF header
+// is added by the request interceptor in api-client.ts
 
 apiClient.interceptors.response.use(
   (response) => response,
@@ -64,12 +64,14 @@ export type MeetingsResponse = {
     status: string;
     visibility?: string;
     hostUserId?: string;
+    seriesId?: string;
   }>;
   quickStartTemplates: Array<{
     id: string;
     title: string;
     description: string;
     badge: string;
+    durationMinutes?: number;
   }>;
   sessionHealth: {
     conversationBalance: string;
@@ -90,17 +92,13 @@ export type MeetingDetail = {
     status: string;
     visibility?: string;
     hostUserId?: string;
+    seriesId?: string;
   };
-  agenda: Array<{
-    id: string;
-    title: string;
-    description: string;
-    durationMinutes: number;
-  }>;
+  agenda: AgendaItem[];
   participants: Array<{
     id: string;
     name: string;
-    role: string;
+    role: ParticipantRole;
     avatarUrl: string;
   }>;
   aiPersona: {
@@ -111,12 +109,99 @@ export type MeetingDetail = {
     energy: string;
     description: string;
   };
-  resources: Array<{
-    title: string;
-    url: string;
-    type: string;
-  }>;
+  resources: MeetingResource[];
   notes: string;
+  recurrence?: MeetingRecurrence;
+};
+
+export type AgendaItem = {
+  id: string;
+  title: string;
+  description: string;
+  durationMinutes: number;
+  // Live progress while and after the meeting runs
+  status?: 'pending' | 'current' | 'completed';
+  startedAt?: string;
+  completedAt?: string;
+  actualMinutes?: number;
+  ranOver?: boolean;
+};
+
+export type MeetingAgendaResponse = {
+  agenda: AgendaItem[];
+};
+
+export type CreateAgendaItemRequest = {
+  title: string;
+  description?: string;
+  durationMinutes?: number;
+  position?: number;
+};
+
+export type UpdateAgendaItemRequest = {
+  title?: string;
+  description?: string;
+  durationMinutes?: number;
+};
+
+export type ReorderAgendaRequest = {
+  itemIds: string[];
+};
+
+export type AgendaProgressRequest = {
+  status: 'pending' | 'current' | 'completed';
+};
+
+// RFC 5545 RRULE, e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=TU;COUNT=10
+export type MeetingRecurrence = {
+  rrule: string;
+  timeZone?: string;
+  exceptions?: string[];
+};
+
+// Only http(s) URLs are accepted
+export type MeetingResource = {
+  id?: string;
+  title: string;
+  url: string;
+  type: string;
+};
+
+export type MeetingResourcesResponse = {
+  resources: MeetingResource[];
+};
+
+export type UpdateMeetingResourceRequest = Partial<Omit<MeetingResource, 'id'>>;
+
+export type MeetingNotes = {
+  content: string;
+  updatedBy?: string;
+  updatedAt?: string;
+};
+
+export type UpdateMeetingNotesRequest = {
+  content: string;
+};
+
+export type CalendarFeedResponse = {
+  url: string;
+};
+
+export type CalendarImportRequest = {
+  ics?: string;
+  url?: string;
+  aiPersonaId?: string;
+  voiceProfile?: string;
+};
+
+export type CalendarImportResponse = {
+  results: Array<{
+    uid: string;
+    title?: string;
+    status: 'created' | 'updated' | 'unchanged' | 'cancelled' | 'skipped' | 'failed';
+    meetingId?: string;
+    reason?: string;
+  }>;
 };
 
 export type TranscriptListResponse = {
@@ -206,6 +291,78 @@ export type UpdateVoicePresetRequest = {
   isDefault?: boolean;
 };
 
+export type AiPersona = {
+  id: string;
+  name: string;
+  voicePreset: string;
+  tone: string;
+  energy: string;
+  description: string;
+  voicePresetId?: string;
+  instructions?: string;
+  custom?: boolean;
+  isDefault?: boolean;
+};
+
+export type AiPersonasResponse = {
+  personas: AiPersona[];
+};
+
+export type CreateAiPersonaRequest = {
+  name: string;
+  tone?: string;
+  energy?: string;
+  voicePresetId?: string;
+  description?: string;
+  instructions?: string;
+  isDefault?: boolean;
+};
+
+export type UpdateAiPersonaRequest = Partial<CreateAiPersonaRequest>;
+
+export type MeetingTemplate = {
+  id: string;
+  title: string;
+  description: string;
+  badge: string;
+  durationMinutes: number;
+  aiPersonaId?: string;
+  voiceProfile?: string;
+  agenda: Array<{
+    id: string;
+    title: string;
+    description: string;
+    durationMinutes: number;
+  }>;
+  visibility: 'private' | 'all_users';
+  ownerUserId?: string;
+  owned: boolean;
+};
+
+export type MeetingTemplatesResponse = {
+  templates: MeetingTemplate[];
+};
+
+export type CreateMeetingTemplateRequest = {
+  fromMeetingId?: string;
+  title?: string;
+  description?: string;
+  durationMinutes?: number;
+  aiPersonaId?: string;
+  voiceProfile?: string;
+  agenda?: Array<{
+    title: string;
+    description: string;
+    durationMinutes: number;
+  }>;
+  visibility?: 'private' | 'all_users';
+};
+
+export type UpdateMeetingTemplateRequest = Omit<CreateMeetingTemplateRequest, 'fromMeetingId'>;
+
+// Body of POST /meetings/from-template/{templateId}; set fields override the template
+export type MeetingFromTemplateRequest = Partial<MeetingCreateRequest>;
+
 export type MeetingCreateRequest = {
   title: string;
   description: string;
@@ -219,6 +376,11 @@ export type MeetingCreateRequest = {
     durationMinutes: number;
   }>;
   isInstant?: boolean;
+  recurrence?: MeetingRecurrence;
+  resources?: Array<Omit<MeetingResource, 'id'>>;
+  notes?: string;
+  visibility?: MeetingVisibility;
+  lobbyEnabled?: boolean;
 };
 
 // Tokens are now sent via HttpOnly cookies, not in response body
@@ -248,6 +410,215 @@ export type MeetingJoinResponse = {
     username: string;
     password: string;
   };
+  status?: 'joined' | 'waiting';
+  pollUrl?: string;
+};
+
+export type MeetingVisibility = 'private' | 'public';
+
+export type MeetingAccessSettings = {
+  visibility: MeetingVisibility;
+  locked: boolean;
+  requireGuestApproval: boolean;
+  guestLinkActive: boolean;
+  lobbyEnabled: boolean;
+};
+
+export type UpdateMeetingAccessRequest = Partial<Omit<MeetingAccessSettings, 'guestLinkActive'>>;
+
+export type GuestLinkResponse = {
+  url: string;
+};
+
+export type GuestStatus = 'waiting' | 'admitted' | 'denied';
+
+export type MeetingGuest = {
+  id: string;
+  displayName: string;
+  status: GuestStatus;
+  createdAt: string;
+  decidedAt?: string;
+  expiresAt: string;
+};
+
+export type MeetingGuestsResponse = {
+  guests: MeetingGuest[];
+};
+
+export type UpdateMeetingGuestRequest = {
+  status: Exclude<GuestStatus, 'waiting'>;
+};
+
+export type GuestMeetingPreview = {
+  title: string;
+  hostName: string;
+  startTime: string;
+  durationMinutes: number;
+  status: string;
+  locked: boolean;
+  requireGuestApproval: boolean;
+};
+
+export type GuestJoinRequest = {
+  displayName: string;
+};
+
+export type GuestJoinResponse = {
+  guestId: string;
+  guestToken?: string;
+  displayName: string;
+  status: GuestStatus;
+  meetingStatus: string;
+  expiresAt: string;
+  join?: MeetingJoinResponse;
+};
+
+export type ParticipantRole = 'host' | 'co_host' | 'presenter' | 'viewer';
+
+export type MeetingPermission =
+  | 'start'
+  | 'end'
+  | 'edit'
+  | 'invite'
+  | 'admit'
+  | 'view_transcript'
+  | 'manage_roles'
+  | 'delete';
+
+export type MeetingPermissions = {
+  role: ParticipantRole | '';
+  permissions: MeetingPermission[];
+};
+
+export type UpdateParticipantRoleRequest = {
+  role: Exclude<ParticipantRole, 'host'>;
+};
+
+export type TransferMeetingRequest = {
+  userId: string;
+};
+
+export type AdmissionStatus = 'waiting' | 'admitted' | 'denied';
+
+export type LobbyEntry = {
+  userId: string;
+  name: string;
+  avatarUrl: string;
+  status: AdmissionStatus;
+  requestedAt?: string;
+  decidedAt?: string;
+};
+
+export type MeetingLobbyResponse = {
+  enabled: boolean;
+  entries: LobbyEntry[];
+};
+
+export type UpdateLobbyEntryRequest = {
+  status: Exclude<AdmissionStatus, 'waiting'>;
+};
+
+export type LobbyStatusResponse = {
+  status: AdmissionStatus | 'none';
+  requestedAt?: string;
+  decidedAt?: string;
+};
+
+export type MeetingEventType =
+  | 'meeting.started'
+  | 'meeting.ended'
+  | 'participant.joined'
+  | 'participant.left'
+  | 'agenda.progress'
+  | 'transcript.section'
+  | 'lobby.requested'
+  | 'lobby.decided';
+
+// Sent by GET /meetings/{id}/events as Server-Sent Events; the SSE event name
+// is the type and the id is used to resume
+export type MeetingEvent<T = unknown> = {
+  id: number;
+  type: MeetingEventType;
+  meetingId: string;
+  data: T;
+  createdAt: string;
+};
+
+export type MeetingStatusEvent = {
+  status: string;
+  userId?: string;
+};
+
+export type ParticipantEvent = {
+  participantId: string;
+  name: string;
+  guest?: boolean;
+};
+
+export type AgendaProgressEvent = {
+  itemId: string;
+  status: 'pending' | 'current' | 'completed';
+  agenda: AgendaItem[];
+};
+
+export type TranscriptSectionEvent = {
+  transcriptId: string;
+  timestampMs: number;
+  speaker: string;
+  text: string;
+};
+
+export type LobbyEvent = {
+  participantId: string;
+  name: string;
+  status: AdmissionStatus;
+  guest?: boolean;
+};
+
+export type NotificationType =
+  | 'invite_received'
+  | 'meeting_starting'
+  | 'meeting_started'
+  | 'transcript_ready'
+  | 'feedback_requested';
+
+export type Notification = {
+  id: string;
+  type: NotificationType;
+  title: string;
+  body: string;
+  link?: string;
+  meetingId?: string;
+  read: boolean;
+  readAt?: string;
+  createdAt: string;
+};
+
+export type NotificationsResponse = {
+  notifications: Notification[];
+  unreadCount: number;
+};
+
+export type NotificationPreference = {
+  type: NotificationType;
+  channels: Record<string, boolean>;
+};
+
+export type NotificationPreferencesResponse = {
+  enabled: boolean;
+  channels: string[];
+  preferences: NotificationPreference[];
+};
+
+export type UpdateNotificationPreferencesRequest = {
+  preferences: NotificationPreference[];
+};
+
+export type NotificationWebhook = {
+  url: string;
+  secret?: string;
+  createdAt: string;
+  updatedAt: string;
 };
 
 export type SettingsUpdateRequest = {